@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/configs"
+	"job_scheduler_go_rabbitmq/internal/core/service"
+	"job_scheduler_go_rabbitmq/internal/infra/clock"
+	"job_scheduler_go_rabbitmq/internal/infra/driven/executor"
+	"job_scheduler_go_rabbitmq/internal/infra/driven/memory"
+	"job_scheduler_go_rabbitmq/internal/infra/driver/dispatcher"
+	"job_scheduler_go_rabbitmq/internal/infra/driver/http/handler"
+	"job_scheduler_go_rabbitmq/internal/infra/driver/mq"
+	"job_scheduler_go_rabbitmq/internal/infra/driver/notifier"
+	"job_scheduler_go_rabbitmq/internal/infra/driver/worker"
+	"job_scheduler_go_rabbitmq/internal/infra/health"
+	"job_scheduler_go_rabbitmq/internal/infra/logging"
+	"job_scheduler_go_rabbitmq/internal/infra/metrics"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/joho/godotenv"
+)
+
+// Modo dev: server, dispatcher, worker y notifier en un solo proceso, con
+// la base y la cola en memoria. Los datos se pierden al salir.
+func main() {
+	// El .env se carga antes del logger: LOG_LEVEL puede venir de ahí
+	envErr := godotenv.Load()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger := logging.New("dev")
+	slog.SetDefault(logger)
+	if envErr != nil {
+		logger.InfoContext(ctx, ".env not loaded", "error", envErr)
+	}
+	logger.InfoContext(ctx, "starting with in-memory store and queue")
+
+	// Configuración: CONFIG_FILE (opcional) más variables de entorno; no
+	// hacen falta base ni broker
+	cfg, err := configs.Load(0)
+	if err != nil {
+		logging.Fatal(ctx, logger, "invalid config", "error", err)
+	}
+	logger.InfoContext(ctx, "config loaded", "config", cfg)
+
+	clk := clock.NewSystem()
+	store := memory.NewStore(clk)
+	defer store.Broker().Close()
+	uow := memory.NewDataStore(store, logging.New("uow"))
+	queue := mq.NewMemoryClient()
+
+	policy, err := cfg.Callbacks.Policy()
+	if err != nil {
+		logging.Fatal(ctx, logger, "invalid callback policy", "error", err)
+	}
+
+	router := mux.NewRouter()
+	checker := health.NewChecker()
+	checker.AddReadiness("queue", queue.Check)
+
+	// Los errores inesperados de los handlers se loguean con el logger del componente http
+	router.Use(handler.LoggerMiddleware(logging.New("http")))
+
+	//API keys
+	apiKeyService := service.NewAPIKeyService(uow, cfg.Auth.AdminKey, clk, logging.New("apikey-service"))
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	handler.RegisterAPIKeyRoutes(router, apiKeyHandler)
+	if cfg.Auth.Enabled {
+		router.Use(handler.AuthMiddleware(apiKeyService))
+	}
+
+	//Job
+	jobService := service.NewJobService(uow, nil, nil, policy, clk, metrics.NewRecorder(), logging.New("job-service"))
+	jobHandler := handler.NewJobHandler(jobService)
+	handler.RegisterJobRoutes(router, jobHandler)
+
+	//Batch
+	batchService := service.NewBatchService(uow)
+	batchHandler := handler.NewBatchHandler(batchService)
+	handler.RegisterBatchRoutes(router, batchHandler)
+
+	//Attempts
+	attemptService := service.NewAttemptService(uow)
+	attemptHandler := handler.NewAttemptHandler(attemptService)
+	handler.RegisterAttemptRoutes(router, attemptHandler)
+
+	//Rate limits
+	rateLimitService := service.NewRateLimitService(uow, clk)
+	rateLimitHandler := handler.NewRateLimitHandler(rateLimitService)
+	handler.RegisterRateLimitRoutes(router, rateLimitHandler)
+
+	//Concurrency limits
+	concurrencyLimitService := service.NewConcurrencyLimitService(uow, clk)
+	concurrencyLimitHandler := handler.NewConcurrencyLimitHandler(concurrencyLimitService)
+	handler.RegisterConcurrencyLimitRoutes(router, concurrencyLimitHandler)
+
+	//Tenant quotas
+	tenantQuotaService := service.NewTenantQuotaService(uow, clk)
+	tenantQuotaHandler := handler.NewTenantQuotaHandler(tenantQuotaService)
+	handler.RegisterTenantQuotaRoutes(router, tenantQuotaHandler)
+
+	//Circuit breakers
+	circuitBreakerService := service.NewCircuitBreakerService(uow)
+	circuitBreakerHandler := handler.NewCircuitBreakerHandler(circuitBreakerService)
+	handler.RegisterCircuitBreakerRoutes(router, circuitBreakerHandler)
+
+	//Stats
+	statsService := service.NewStatsService(uow, queue, clk, logging.New("stats-service"))
+	statsHandler := handler.NewStatsHandler(statsService)
+	handler.RegisterStatsRoutes(router, statsHandler)
+
+	//Webhooks
+	webhookService := service.NewWebhookService(uow, executor.NewWebhookSender(policy), policy, clk, logging.New("webhook-service"))
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	handler.RegisterWebhookRoutes(router, webhookHandler)
+
+	//Event stream (SSE) alimentado directamente por el store
+	eventStreamService := service.NewEventStreamService(uow, store.Broker(), clk)
+	eventStreamHandler := handler.NewEventStreamHandler(eventStreamService)
+	handler.RegisterEventStreamRoutes(router, eventStreamHandler)
+
+	//Métricas Prometheus
+	router.Handle("/metrics", metrics.Handler()).Methods(http.MethodGet)
+
+	//Health
+	router.Handle("/healthz", checker.Liveness()).Methods(http.MethodGet) // GET liveness
+	router.Handle("/readyz", checker.Readiness()).Methods(http.MethodGet) // GET readiness
+
+	var wg sync.WaitGroup
+
+	// Dispatcher
+	dispatcherLogger := logging.New("dispatcher")
+	d := dispatcher.New(uow.Job(), queue, "dev-dispatcher", clk, dispatcher.Settings{
+		BatchSize:      uint(cfg.Dispatcher.BatchSize),
+		PerTenantLimit: uint(cfg.Dispatcher.PerTenantLimit),
+		LockTimeout:    cfg.Dispatcher.LockTimeout,
+	}, dispatcherLogger)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(cfg.Dispatcher.PollInterval)
+		defer ticker.Stop()
+		for {
+			if err := d.RunOnce(ctx); err != nil {
+				dispatcherLogger.ErrorContext(ctx, "dispatch tick failed", "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	// Worker con executor y circuit breakers propios
+	breakers := executor.NewCircuitBreakers(cfg.Worker.CircuitBreaker.Settings(), uow.CircuitBreaker(), "dev-worker", clk, logging.New("circuit-breaker"))
+	exec := executor.NewHTTPExecutor(breakers, policy, cfg.Worker.CallbackTimeout)
+	workerJobService := service.NewJobService(uow, exec, queue, policy, clk, metrics.NewRecorder(), logging.New("job-service"))
+	workerLogger := logging.New("worker")
+	w := worker.New(workerJobService, queue, "dev-worker", cfg.Worker.TenantPollInterval, workerLogger)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := w.Start(ctx); err != nil {
+			workerLogger.ErrorContext(ctx, "worker stopped", "error", err)
+		}
+	}()
+
+	// Notifier de webhooks
+	notifierLogger := logging.New("notifier")
+	n := notifier.New(webhookService, cfg.Notifier.BatchSize, notifierLogger)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			more, err := n.RunOnce(ctx)
+			if err != nil {
+				notifierLogger.ErrorContext(ctx, "notifier run failed", "error", err)
+			}
+			if more {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(cfg.Notifier.PollInterval):
+			}
+		}
+	}()
+
+	port := strconv.Itoa(cfg.Server.Port)
+	server := &http.Server{
+		Addr:    "0.0.0.0:" + port,
+		Handler: router,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logging.Fatal(ctx, logger, "server failed", "error", err)
+		}
+	}()
+	logger.InfoContext(ctx, "server running", "port", cfg.Server.Port)
+
+	<-ctx.Done()
+	logger.InfoContext(ctx, "shutting down")
+	checker.SetDraining()
+
+	ctxShutdown, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctxShutdown); err != nil {
+		logger.ErrorContext(ctx, "server shutdown failed", "error", err)
+	}
+
+	// El worker termina después del mensaje en curso
+	queue.Close()
+	wg.Wait()
+	logger.InfoContext(ctx, "stopped gracefully")
+}
@@ -2,55 +2,114 @@ package main
 
 import (
 	"context"
-	"log"
-	"os"
+	"log/slog"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"job_scheduler_go_rabbitmq/internal/configs"
+	"job_scheduler_go_rabbitmq/internal/infra/clock"
 	"job_scheduler_go_rabbitmq/internal/infra/driven/repositories"
 	"job_scheduler_go_rabbitmq/internal/infra/driver/dispatcher"
 	"job_scheduler_go_rabbitmq/internal/infra/driver/mq"
+	"job_scheduler_go_rabbitmq/internal/infra/health"
+	"job_scheduler_go_rabbitmq/internal/infra/logging"
+	"job_scheduler_go_rabbitmq/internal/infra/metrics"
+	"job_scheduler_go_rabbitmq/internal/infra/migrate"
+	"job_scheduler_go_rabbitmq/internal/infra/tracing"
 
 	"github.com/joho/godotenv"
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Println("[DISPATCHER] .env not loaded")
+	// El .env se carga antes del logger: LOG_LEVEL puede venir de ahí
+	envErr := godotenv.Load()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger := logging.New("dispatcher")
+	slog.SetDefault(logger)
+	if envErr != nil {
+		logger.InfoContext(ctx, ".env not loaded", "error", envErr)
+	}
+	logger.InfoContext(ctx, "starting")
+
+	// Configuración: CONFIG_FILE (opcional) más variables de entorno
+	cfg, err := configs.Load(configs.NeedDB | configs.NeedBroker)
+	if err != nil {
+		logging.Fatal(ctx, logger, "invalid config", "error", err)
 	}
+	logger.InfoContext(ctx, "config loaded", "config", cfg)
 
-	log.Println("[DISPATCHER] Starting...")
-	log.Println("[DISPATCHER] RABBITMQ_URL =", os.Getenv("RABBITMQ_URL"))
+	// Tracing (OTEL_TRACES_EXPORTER=otlp|stdout|none)
+	shutdownTracing, err := tracing.Setup(ctx, "job-scheduler-dispatcher")
+	if err != nil {
+		logging.Fatal(ctx, logger, "tracing setup failed", "error", err)
+	}
+	defer shutdownTracing(context.Background())
 
 	// DB
-	pool, err := configs.NewDBConnection()
+	pool, err := configs.NewDBConnection(cfg.DB)
 	if err != nil {
-		log.Fatal("DB error:", err)
+		logging.Fatal(ctx, logger, "database connection failed", "error", err)
+	}
+	// Con db.schema_check no arranca contra una base con migraciones pendientes
+	if cfg.DB.SchemaCheck {
+		if err := migrate.CheckSchema(ctx, pool); err != nil {
+			logging.Fatal(ctx, logger, "schema check failed", "error", err)
+		}
 	}
-	uow := repositories.NewDataStore(pool)
+	// Con db.server_clock los vencimientos se comparan con now() de Postgres
+	clk := clock.NewSystem()
+	uow := repositories.NewDataStore(pool, repositories.Clock{IClock: clk, DBNow: cfg.DB.ServerClock}, logging.New("uow"))
 
 	// Rabbit
-	rabbit, err := mq.NewRabbitClient()
+	rabbit, err := mq.NewRabbitClient(cfg.Broker.URL, cfg.Broker.Queue, logging.New("rabbitmq"))
 	if err != nil {
-		log.Fatal("RabbitMQ error:", err)
+		logging.Fatal(ctx, logger, "RabbitMQ connection failed", "error", err)
 	}
 
 	// Dispatcher
-	nodeID := os.Getenv("INSTANCE_ID")
+	nodeID := cfg.InstanceID
 	if nodeID == "" {
 		nodeID = "dispatcher-1"
 	}
 
+	// Listener de métricas opcional
+	metrics.Serve(cfg.MetricsAddr, logging.New("metrics"))
+
 	jobRepo := uow.Job()
-	d := dispatcher.New(jobRepo, rabbit, nodeID)
+	d := dispatcher.New(jobRepo, rabbit, nodeID, clk, dispatcher.Settings{
+		BatchSize:      uint(cfg.Dispatcher.BatchSize),
+		PerTenantLimit: uint(cfg.Dispatcher.PerTenantLimit),
+		LockTimeout:    cfg.Dispatcher.LockTimeout,
+	}, logger)
+
+	// Probes: liveness falla si el loop dejó de completar ticks
+	checker := health.NewChecker()
+	checker.AddLiveness("dispatcher", health.Recent(d.LastSuccess, cfg.Dispatcher.MaxTickAge))
+	checker.AddReadiness("postgres", pool.Ping)
+	checker.AddReadiness("rabbitmq", rabbit.Check)
+	health.Serve(cfg.Health.Addr, checker, logging.New("health"))
 
-	ctx := context.Background()
+	ticker := time.NewTicker(cfg.Dispatcher.PollInterval)
+	defer ticker.Stop()
 
 	for {
 		if err := d.RunOnce(ctx); err != nil {
-			log.Println("[DISPATCHER] Error:", err)
+			logger.ErrorContext(ctx, "dispatch tick failed", "node_id", nodeID, "error", err)
 		}
 
-		time.Sleep(1 * time.Second)
+		select {
+		case <-ctx.Done():
+			// Se termina el tick en curso antes de salir
+			checker.SetDraining()
+			logger.InfoContext(ctx, "shutting down", "node_id", nodeID)
+			rabbit.Close()
+			pool.Close()
+			return
+		case <-ticker.C:
+		}
 	}
 }
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+
+	"job_scheduler_go_rabbitmq/internal/configs"
+	"job_scheduler_go_rabbitmq/internal/infra/logging"
+	"job_scheduler_go_rabbitmq/internal/infra/migrate"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `usage: migrate <command>
+
+commands:
+  up                 aplica todas las migraciones pendientes
+  down [n]           revierte las últimas n migraciones (por defecto 1)
+  to <version>       aplica o revierte hasta dejar la base en esa versión (0 = vacía)
+  status             lista las migraciones y cuáles están aplicadas
+  baseline <version> marca como aplicadas hasta esa versión sin ejecutarlas
+                     (una base creada a mano con el viejo ddl.sql: baseline 1 y después up)
+`
+
+func main() {
+	// El .env se carga antes del logger: LOG_LEVEL puede venir de ahí
+	envErr := godotenv.Load()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := logging.New("migrate")
+	slog.SetDefault(logger)
+	if envErr != nil {
+		logger.DebugContext(ctx, ".env not loaded", "error", envErr)
+	}
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg, err := configs.Load(configs.NeedDB)
+	if err != nil {
+		logging.Fatal(ctx, logger, "invalid config", "error", err)
+	}
+
+	// DB
+	pool, err := configs.NewDBConnection(cfg.DB)
+	if err != nil {
+		logging.Fatal(ctx, logger, "database connection failed", "error", err)
+	}
+	defer pool.Close()
+
+	m, err := migrate.New(pool, logger)
+	if err != nil {
+		logging.Fatal(ctx, logger, "load migrations failed", "error", err)
+	}
+	exitOnError := func(err error) {
+		if err != nil {
+			logging.Fatal(ctx, logger, "migration failed", "error", err)
+		}
+	}
+
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "up":
+		n, err := m.Up(ctx)
+		exitOnError(err)
+		fmt.Printf("%d migrations applied, schema at version %d\n", n, m.Latest())
+
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			steps, err = strconv.Atoi(args[0])
+			if err != nil || steps < 1 {
+				usageError(fmt.Sprintf("invalid number of steps %q", args[0]))
+			}
+		}
+		n, err := m.Down(ctx, steps)
+		exitOnError(err)
+		fmt.Printf("%d migrations reverted\n", n)
+
+	case "to":
+		version := versionArg(args)
+		n, err := m.To(ctx, version)
+		exitOnError(err)
+		fmt.Printf("%d migrations run, schema at version %d\n", n, version)
+
+	case "baseline":
+		version := versionArg(args)
+		exitOnError(m.Baseline(ctx, version))
+		fmt.Printf("schema baselined at version %d\n", version)
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		exitOnError(err)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, st := range statuses {
+			applied := "pending"
+			if st.AppliedAt != nil {
+				applied = st.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", st.Version, st.Name, applied)
+		}
+		w.Flush()
+
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func versionArg(args []string) int64 {
+	if len(args) == 0 {
+		usageError("missing version")
+	}
+	version, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || version < 0 {
+		usageError(fmt.Sprintf("invalid version %q", args[0]))
+	}
+	return version
+}
+
+// usageError informa un argumento inválido junto con el uso, como un error de tipeo.
+func usageError(msg string) {
+	fmt.Fprintf(os.Stderr, "%s\n\n%s", msg, usage)
+	os.Exit(2)
+}
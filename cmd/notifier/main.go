@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"job_scheduler_go_rabbitmq/internal/configs"
+	"job_scheduler_go_rabbitmq/internal/core/service"
+	"job_scheduler_go_rabbitmq/internal/infra/clock"
+	"job_scheduler_go_rabbitmq/internal/infra/driven/executor"
+	"job_scheduler_go_rabbitmq/internal/infra/driven/repositories"
+	"job_scheduler_go_rabbitmq/internal/infra/driver/notifier"
+	"job_scheduler_go_rabbitmq/internal/infra/logging"
+	"job_scheduler_go_rabbitmq/internal/infra/migrate"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	// El .env se carga antes del logger: LOG_LEVEL puede venir de ahí
+	envErr := godotenv.Load()
+
+	ctx := context.Background()
+	logger := logging.New("notifier")
+	slog.SetDefault(logger)
+	if envErr != nil {
+		logger.InfoContext(ctx, ".env not loaded", "error", envErr)
+	}
+	logger.InfoContext(ctx, "starting")
+
+	// Configuración: CONFIG_FILE (opcional) más variables de entorno
+	cfg, err := configs.Load(configs.NeedDB)
+	if err != nil {
+		logging.Fatal(ctx, logger, "invalid config", "error", err)
+	}
+	logger.InfoContext(ctx, "config loaded", "config", cfg)
+
+	// DB
+	pool, err := configs.NewDBConnection(cfg.DB)
+	if err != nil {
+		logging.Fatal(ctx, logger, "database connection failed", "error", err)
+	}
+	// Con db.schema_check no arranca contra una base con migraciones pendientes
+	if cfg.DB.SchemaCheck {
+		if err := migrate.CheckSchema(ctx, pool); err != nil {
+			logging.Fatal(ctx, logger, "schema check failed", "error", err)
+		}
+	}
+	// Con db.server_clock los vencimientos se comparan con now() de Postgres
+	clk := clock.NewSystem()
+	uow := repositories.NewDataStore(pool, repositories.Clock{IClock: clk, DBNow: cfg.DB.ServerClock}, logging.New("uow"))
+
+	// Los webhooks pasan por la misma allowlist que los callbacks
+	policy, err := cfg.Callbacks.Policy()
+	if err != nil {
+		logging.Fatal(ctx, logger, "invalid callback policy", "error", err)
+	}
+	sender := executor.NewWebhookSender(policy)
+
+	webhookService := service.NewWebhookService(uow, sender, policy, clk, logging.New("webhook-service"))
+	n := notifier.New(webhookService, cfg.Notifier.BatchSize, logger)
+
+	for {
+		more, err := n.RunOnce(ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, "notifier run failed", "error", err)
+		}
+
+		// Con un lote completo se sigue sin esperar
+		if !more {
+			time.Sleep(cfg.Notifier.PollInterval)
+		}
+	}
+}
@@ -3,13 +3,22 @@ package main
 import (
 	"context"
 	"job_scheduler_go_rabbitmq/internal/configs"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
 	"job_scheduler_go_rabbitmq/internal/core/service"
+	"job_scheduler_go_rabbitmq/internal/infra/clock"
 	"job_scheduler_go_rabbitmq/internal/infra/driven/repositories"
 	"job_scheduler_go_rabbitmq/internal/infra/driver/http/handler"
-	"log"
+	"job_scheduler_go_rabbitmq/internal/infra/driver/mq"
+	"job_scheduler_go_rabbitmq/internal/infra/health"
+	"job_scheduler_go_rabbitmq/internal/infra/logging"
+	"job_scheduler_go_rabbitmq/internal/infra/metrics"
+	"job_scheduler_go_rabbitmq/internal/infra/migrate"
+	"job_scheduler_go_rabbitmq/internal/infra/tracing"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -18,62 +27,184 @@ import (
 )
 
 func main() {
-	// Load envirnonment variables
-	err := godotenv.Load()
+	// Load envirnonment variables (antes del logger: LOG_LEVEL puede venir del .env)
+	envErr := godotenv.Load()
+
+	// Logger estructurado del proceso; también es el default de slog
+	ctx := context.Background()
+	logger := logging.New("server")
+	slog.SetDefault(logger)
+	if envErr != nil {
+		logger.InfoContext(ctx, ".env not loaded", "error", envErr)
+	}
+
+	// Configuración: CONFIG_FILE (opcional) más variables de entorno
+	cfg, err := configs.Load(configs.NeedDB)
 	if err != nil {
-		log.Print("Error loading .env file")
+		logging.Fatal(ctx, logger, "invalid config", "error", err)
 	}
-	if os.Getenv("JOBS_SERVICE_PORT") == "" {
-		log.Println("No se encontro variable de entorno JOBS_SERVICE_PORT")
+	logger.InfoContext(ctx, "config loaded", "config", cfg)
+
+	// Tracing (OTEL_TRACES_EXPORTER=otlp|stdout|none)
+	shutdownTracing, err := tracing.Setup(ctx, "job-scheduler-server")
+	if err != nil {
+		logging.Fatal(ctx, logger, "tracing setup failed", "error", err)
 	}
 
 	//Conneccion DB
-	pool, err := configs.NewDBConnection()
+	pool, err := configs.NewDBConnection(cfg.DB)
 	if err != nil {
-		log.Fatal("Database error:", err)
+		logging.Fatal(ctx, logger, "database connection failed", "error", err)
+	}
+	// Con db.schema_check no arranca contra una base con migraciones pendientes
+	if cfg.DB.SchemaCheck {
+		if err := migrate.CheckSchema(ctx, pool); err != nil {
+			logging.Fatal(ctx, logger, "schema check failed", "error", err)
+		}
 	}
 
 	// Crear el repositorio de datos
-	uow := repositories.NewDataStore(pool)
+	// Con db.server_clock los vencimientos se comparan con now() de Postgres
+	clk := clock.NewSystem()
+	uow := repositories.NewDataStore(pool, repositories.Clock{IClock: clk, DBNow: cfg.DB.ServerClock}, logging.New("uow"))
 
 	// Crear el router
 	router := mux.NewRouter()
 
+	// Probes de Kubernetes
+	checker := health.NewChecker()
+	checker.AddReadiness("postgres", pool.Ping)
+
+	// Allowlist de callbacks
+	policy, err := cfg.Callbacks.Policy()
+	if err != nil {
+		logging.Fatal(ctx, logger, "invalid callback policy", "error", err)
+	}
+
+	// Los errores inesperados de los handlers se loguean con el logger del componente http
+	router.Use(handler.LoggerMiddleware(logging.New("http")))
+
+	//API keys: todas las rutas salvo probes y /metrics piden una key con el scope adecuado
+	apiKeyService := service.NewAPIKeyService(uow, cfg.Auth.AdminKey, clk, logging.New("apikey-service"))
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	handler.RegisterAPIKeyRoutes(router, apiKeyHandler)
+	if cfg.Auth.Enabled {
+		router.Use(handler.AuthMiddleware(apiKeyService))
+	} else {
+		logger.WarnContext(ctx, "auth disabled: the API is open to anyone who can reach it", "auth.enabled", false)
+	}
+
 	//Job
-	jobService := service.NewJobService(uow, nil, nil)
+	jobService := service.NewJobService(uow, nil, nil, policy, clk, metrics.NewRecorder(), logging.New("job-service"))
 	jobHandler := handler.NewJobHandler(jobService)
 	handler.RegisterJobRoutes(router, jobHandler)
 
+	//Batch
+	batchService := service.NewBatchService(uow)
+	batchHandler := handler.NewBatchHandler(batchService)
+	handler.RegisterBatchRoutes(router, batchHandler)
+
+	//Attempts
+	attemptService := service.NewAttemptService(uow)
+	attemptHandler := handler.NewAttemptHandler(attemptService)
+	handler.RegisterAttemptRoutes(router, attemptHandler)
+
+	//Rate limits
+	rateLimitService := service.NewRateLimitService(uow, clk)
+	rateLimitHandler := handler.NewRateLimitHandler(rateLimitService)
+	handler.RegisterRateLimitRoutes(router, rateLimitHandler)
+
+	//Concurrency limits
+	concurrencyLimitService := service.NewConcurrencyLimitService(uow, clk)
+	concurrencyLimitHandler := handler.NewConcurrencyLimitHandler(concurrencyLimitService)
+	handler.RegisterConcurrencyLimitRoutes(router, concurrencyLimitHandler)
+
+	//Tenant quotas
+	tenantQuotaService := service.NewTenantQuotaService(uow, clk)
+	tenantQuotaHandler := handler.NewTenantQuotaHandler(tenantQuotaService)
+	handler.RegisterTenantQuotaRoutes(router, tenantQuotaHandler)
+
+	//Circuit breakers
+	circuitBreakerService := service.NewCircuitBreakerService(uow)
+	circuitBreakerHandler := handler.NewCircuitBreakerHandler(circuitBreakerService)
+	handler.RegisterCircuitBreakerRoutes(router, circuitBreakerHandler)
+
+	//Stats: la profundidad de la cola se consulta a RabbitMQ si está disponible
+	var queue ports.IQueueInspector
+	if cfg.Broker.URL == "" {
+		logger.WarnContext(ctx, "RabbitMQ not configured, /stats will not report queue depth")
+	} else if rabbit, err := mq.NewRabbitClient(cfg.Broker.URL, cfg.Broker.Queue, logging.New("rabbitmq")); err != nil {
+		logger.WarnContext(ctx, "RabbitMQ not available, /stats will not report queue depth", "error", err)
+	} else {
+		defer rabbit.Close()
+		queue = rabbit
+		checker.AddReadiness("rabbitmq", rabbit.Check)
+	}
+	statsService := service.NewStatsService(uow, queue, clk, logging.New("stats-service"))
+	statsHandler := handler.NewStatsHandler(statsService)
+	handler.RegisterStatsRoutes(router, statsHandler)
+
+	//Webhooks (las entregas las envía cmd/notifier)
+	webhookService := service.NewWebhookService(uow, nil, policy, clk, logging.New("webhook-service"))
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	handler.RegisterWebhookRoutes(router, webhookHandler)
+
+	//Event stream (SSE) alimentado por LISTEN/NOTIFY
+	streamCtx, stopStream := context.WithCancel(ctx)
+	eventListener := repositories.NewEventListener(pool, logging.New("events"))
+	go eventListener.Run(streamCtx)
+
+	eventStreamService := service.NewEventStreamService(uow, eventListener, clk)
+	eventStreamHandler := handler.NewEventStreamHandler(eventStreamService)
+	handler.RegisterEventStreamRoutes(router, eventStreamHandler)
+
+	//Métricas Prometheus
+	router.Handle("/metrics", metrics.Handler()).Methods(http.MethodGet)
+
+	//Health
+	router.Handle("/healthz", checker.Liveness()).Methods(http.MethodGet) // GET liveness
+	router.Handle("/readyz", checker.Readiness()).Methods(http.MethodGet) // GET readiness (503 durante el drain)
+
 	// Config para manejar las señales del sistema (graceful shutdown)
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
 	// Iniciar el servidor HTTP
 	server := &http.Server{
-		Addr:    "0.0.0.0:8000",
+		Addr:    "0.0.0.0:" + strconv.Itoa(cfg.Server.Port),
 		Handler: router,
 	}
+	// Shutdown no espera conexiones SSE abiertas: se cierran los streams al apagar
+	server.RegisterOnShutdown(stopStream)
 
 	go func() {
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Error en el servidor: %v", err)
+			logging.Fatal(ctx, logger, "server failed", "error", err)
 		}
 	}()
 
-	log.Println("Server is running...")
+	logger.InfoContext(ctx, "server running", "port", cfg.Server.Port)
 
 	// Esperar una señal de interrupción o terminación
 	<-stop
 
-	log.Println("Shutting down server...")
+	logger.InfoContext(ctx, "shutting down")
+
+	// /readyz responde 503 para que el balanceador saque la instancia antes de cerrar
+	checker.SetDraining()
+	time.Sleep(cfg.Health.DrainDelay)
 
-	// Intentar un apagado suave del servidor con un timeout de 5 segundos
-	ctxShutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// Intentar un apagado suave del servidor con un timeout de server.shutdown_timeout
+	ctxShutdown, cancel := context.WithTimeout(ctx, cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(ctxShutdown); err != nil {
-		log.Fatalf("Error en el apagado del servidor: %v", err)
+		logging.Fatal(ctx, logger, "server shutdown failed", "error", err)
+	}
+
+	if err := shutdownTracing(ctxShutdown); err != nil {
+		logger.ErrorContext(ctx, "tracing shutdown failed", "error", err)
 	}
 
-	log.Println("Server stopped gracefully")
+	logger.InfoContext(ctx, "server stopped gracefully")
 }
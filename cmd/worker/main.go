@@ -2,54 +2,118 @@ package main
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"job_scheduler_go_rabbitmq/internal/configs"
 	"job_scheduler_go_rabbitmq/internal/core/service"
+	"job_scheduler_go_rabbitmq/internal/infra/clock"
 	"job_scheduler_go_rabbitmq/internal/infra/driven/executor"
 	"job_scheduler_go_rabbitmq/internal/infra/driven/repositories"
 	"job_scheduler_go_rabbitmq/internal/infra/driver/mq"
 	"job_scheduler_go_rabbitmq/internal/infra/driver/worker"
+	"job_scheduler_go_rabbitmq/internal/infra/health"
+	"job_scheduler_go_rabbitmq/internal/infra/logging"
+	"job_scheduler_go_rabbitmq/internal/infra/metrics"
+	"job_scheduler_go_rabbitmq/internal/infra/migrate"
+	"job_scheduler_go_rabbitmq/internal/infra/tracing"
 
 	"github.com/joho/godotenv"
 )
 
 func main() {
-	log.Println("[WORKER] Starting...")
-	if err := godotenv.Load(); err != nil {
-		log.Println("[WORKER] No .env file loaded")
+	// El .env se carga antes del logger: LOG_LEVEL puede venir de ahí
+	envErr := godotenv.Load()
+
+	ctx := context.Background()
+	logger := logging.New("worker")
+	slog.SetDefault(logger)
+	if envErr != nil {
+		logger.InfoContext(ctx, ".env not loaded", "error", envErr)
 	}
+	logger.InfoContext(ctx, "starting")
 
-	// DB
-	pool, err := configs.NewDBConnection()
+	// Configuración: CONFIG_FILE (opcional) más variables de entorno
+	cfg, err := configs.Load(configs.NeedDB | configs.NeedBroker)
 	if err != nil {
-		log.Fatal("DB error:", err)
+		logging.Fatal(ctx, logger, "invalid config", "error", err)
 	}
-	uow := repositories.NewDataStore(pool)
+	logger.InfoContext(ctx, "config loaded", "config", cfg)
 
-	log.Println("[WORKER] RABBITMQ_URL =", os.Getenv("RABBITMQ_URL"))
-	log.Println("[WORKER] RABBITMQ_QUEUE =", os.Getenv("RABBITMQ_QUEUE"))
+	// Tracing (OTEL_TRACES_EXPORTER=otlp|stdout|none)
+	shutdownTracing, err := tracing.Setup(ctx, "job-scheduler-worker")
+	if err != nil {
+		logging.Fatal(ctx, logger, "tracing setup failed", "error", err)
+	}
+	defer shutdownTracing(ctx)
+
+	// DB
+	pool, err := configs.NewDBConnection(cfg.DB)
+	if err != nil {
+		logging.Fatal(ctx, logger, "database connection failed", "error", err)
+	}
+	// Con db.schema_check no arranca contra una base con migraciones pendientes
+	if cfg.DB.SchemaCheck {
+		if err := migrate.CheckSchema(ctx, pool); err != nil {
+			logging.Fatal(ctx, logger, "schema check failed", "error", err)
+		}
+	}
+	// Con db.server_clock los vencimientos se comparan con now() de Postgres
+	clk := clock.NewSystem()
+	uow := repositories.NewDataStore(pool, repositories.Clock{IClock: clk, DBNow: cfg.DB.ServerClock}, logging.New("uow"))
 
 	// Rabbit
-	rabbit, err := mq.NewRabbitClient()
+	rabbit, err := mq.NewRabbitClient(cfg.Broker.URL, cfg.Broker.Queue, logging.New("rabbitmq"))
 	if err != nil {
-		log.Fatal("RabbitMQ error:", err)
+		logging.Fatal(ctx, logger, "RabbitMQ connection failed", "error", err)
 	}
 
-	// Executor
-	exec := executor.NewHTTPExecutor()
+	// Cada proceso tiene su propio circuit breaker; nodeID separa sus
+	// snapshots, por eso no se repite entre workers
+	nodeID := cfg.NodeID()
+
+	// Executor con circuit breaker por host
+	breakers := executor.NewCircuitBreakers(cfg.Worker.CircuitBreaker.Settings(), uow.CircuitBreaker(), nodeID, clk, logging.New("circuit-breaker"))
+	policy, err := cfg.Callbacks.Policy()
+	if err != nil {
+		logging.Fatal(ctx, logger, "invalid callback policy", "error", err)
+	}
+	exec := executor.NewHTTPExecutor(breakers, policy, cfg.Worker.CallbackTimeout)
 
 	// Job Service (concreto)
-	jobService := service.NewJobService(uow, exec, rabbit)
+	jobService := service.NewJobService(uow, exec, rabbit, policy, clk, metrics.NewRecorder(), logging.New("job-service"))
+
+	// Listener de métricas opcional
+	metrics.Serve(cfg.MetricsAddr, logging.New("metrics"))
 
 	// Worker
-	w := worker.New(jobService, rabbit)
+	w := worker.New(jobService, rabbit, nodeID, cfg.Worker.TenantPollInterval, logging.New("worker"))
 
-	ctx := context.Background()
+	// Probes
+	checker := health.NewChecker()
+	checker.AddReadiness("postgres", pool.Ping)
+	checker.AddReadiness("rabbitmq", rabbit.Check)
+	health.Serve(cfg.Health.Addr, checker, logging.New("health"))
+
+	// Graceful drain: /readyz pasa a 503, se espera health.drain_delay y se
+	// cierra el canal; Consume termina después del mensaje en curso
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+		<-stop
+
+		logger.InfoContext(ctx, "draining", "delay", cfg.Health.DrainDelay)
+		checker.SetDraining()
+		time.Sleep(cfg.Health.DrainDelay)
+		rabbit.Close()
+	}()
 
-	log.Println("[WORKER] Listening for jobs...")
+	logger.InfoContext(ctx, "listening for jobs", "node_id", nodeID)
 	if err := w.Start(ctx); err != nil {
-		log.Fatal("[WORKER] stopped:", err)
+		logging.Fatal(ctx, logger, "worker stopped", "error", err)
 	}
+	logger.InfoContext(ctx, "stopped gracefully")
 }
@@ -0,0 +1,10 @@
+package configs
+
+import (
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+)
+
+// Policy arma la allowlist de callbacks. Sin esquemas se permiten http y https.
+func (c CallbackConfig) Policy() (*domain.CallbackPolicy, error) {
+	return domain.NewCallbackPolicy(c.AllowedSchemes, c.AllowedHosts, c.AllowedCIDRs)
+}
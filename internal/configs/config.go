@@ -0,0 +1,295 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config es la configuración de todos los binarios. Se arma con los valores
+// por defecto, el archivo YAML de CONFIG_FILE (opcional) y las variables de
+// entorno, en ese orden: cada paso pisa al anterior.
+type Config struct {
+	// InstanceID identifica al proceso en locks, intentos, logs y snapshots
+	// del circuit breaker; vacío usa el nombre por defecto de cada binario
+	// (el worker usa NodeID)
+	InstanceID  string `yaml:"instance_id"`
+	MetricsAddr string `yaml:"metrics_addr"`
+
+	Server     ServerConfig     `yaml:"server"`
+	Health     HealthConfig     `yaml:"health"`
+	DB         DBConfig         `yaml:"db"`
+	Broker     BrokerConfig     `yaml:"broker"`
+	Dispatcher DispatcherConfig `yaml:"dispatcher"`
+	Worker     WorkerConfig     `yaml:"worker"`
+	Notifier   NotifierConfig   `yaml:"notifier"`
+	Auth       AuthConfig       `yaml:"auth"`
+	Callbacks  CallbackConfig   `yaml:"callbacks"`
+}
+
+type ServerConfig struct {
+	Port int `yaml:"port"`
+	// ShutdownTimeout es cuánto se espera a los requests en curso al apagar
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+type HealthConfig struct {
+	// Addr es el listener de probes de worker y dispatcher (vacío = deshabilitado)
+	Addr string `yaml:"addr"`
+	// DrainDelay es el tiempo con /readyz en 503 antes de cerrar
+	DrainDelay time.Duration `yaml:"drain_delay"`
+}
+
+type DBConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password" secret:"true"`
+	Database string `yaml:"database"`
+	SSLMode  string `yaml:"sslmode"`
+
+	MaxConns        int32         `yaml:"max_conns"`
+	MinConns        int32         `yaml:"min_conns"`
+	MaxConnLifetime time.Duration `yaml:"max_conn_lifetime"`
+	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time"`
+
+	// SchemaCheck impide arrancar contra una base con migraciones pendientes
+	SchemaCheck bool `yaml:"schema_check"`
+	// ServerClock compara los vencimientos con now() de Postgres
+	ServerClock bool `yaml:"server_clock"`
+}
+
+type BrokerConfig struct {
+	URL string `yaml:"url" secret:"url"`
+	// Queue es la cola base; cada tenant usa "<queue>.<tenant>"
+	Queue string `yaml:"queue"`
+}
+
+type DispatcherConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval"`
+	// BatchSize es el tope de jobs por tick y PerTenantLimit el de cada tenant
+	BatchSize      int `yaml:"batch_size"`
+	PerTenantLimit int `yaml:"per_tenant_limit"`
+	// LockTimeout es cuándo se considera abandonado el lock de un job
+	LockTimeout time.Duration `yaml:"lock_timeout"`
+	// MaxTickAge es la edad máxima del último tick exitoso antes de fallar /healthz
+	MaxTickAge time.Duration `yaml:"max_tick_age"`
+}
+
+type WorkerConfig struct {
+	// TenantPollInterval es cada cuánto se buscan tenants nuevos con jobs encolados
+	TenantPollInterval time.Duration `yaml:"tenant_poll_interval"`
+	// CallbackTimeout limita cada llamada al callback_url: el worker la hace
+	// dentro de la transacción del job y con un slot de concurrencia tomado
+	CallbackTimeout time.Duration        `yaml:"callback_timeout"`
+	CircuitBreaker  CircuitBreakerConfig `yaml:"circuit_breaker"`
+}
+
+type CircuitBreakerConfig struct {
+	FailureThreshold int           `yaml:"failure_threshold"`
+	CoolDown         time.Duration `yaml:"cooldown"`
+	HalfOpenMaxCalls int           `yaml:"half_open_max_calls"`
+}
+
+type NotifierConfig struct {
+	BatchSize    int           `yaml:"batch_size"`
+	PollInterval time.Duration `yaml:"poll_interval"`
+}
+
+type AuthConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// AdminKey es una key admin de arranque para crear las demás
+	AdminKey string `yaml:"admin_key" secret:"true"`
+}
+
+type CallbackConfig struct {
+	AllowedSchemes []string `yaml:"allowed_schemes"`
+	AllowedHosts   []string `yaml:"allowed_hosts"`
+	AllowedCIDRs   []string `yaml:"allowed_cidrs"`
+}
+
+// Need indica qué dependencias usa un binario: las que no usa no se exigen.
+type Need uint
+
+const (
+	NeedDB Need = 1 << iota
+	NeedBroker
+)
+
+// Default devuelve la configuración sin archivo ni variables de entorno.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:            8000,
+			ShutdownTimeout: 5 * time.Second,
+		},
+		Health: HealthConfig{
+			DrainDelay: 5 * time.Second,
+		},
+		DB: DBConfig{
+			Port:            5432,
+			SSLMode:         "require",
+			MaxConns:        10,
+			MaxConnLifetime: time.Hour,
+			MaxConnIdleTime: 30 * time.Minute,
+		},
+		Broker: BrokerConfig{
+			Queue: "jobs_queue",
+		},
+		Dispatcher: DispatcherConfig{
+			PollInterval:   time.Second,
+			BatchSize:      500,
+			PerTenantLimit: 50,
+			LockTimeout:    5 * time.Minute,
+			MaxTickAge:     30 * time.Second,
+		},
+		Worker: WorkerConfig{
+			TenantPollInterval: 10 * time.Second,
+			CallbackTimeout:    30 * time.Second,
+			CircuitBreaker: CircuitBreakerConfig{
+				FailureThreshold: 5,
+				CoolDown:         30 * time.Second,
+				HalfOpenMaxCalls: 1,
+			},
+		},
+		Notifier: NotifierConfig{
+			BatchSize:    20,
+			PollInterval: time.Second,
+		},
+		Auth: AuthConfig{
+			Enabled: true,
+		},
+	}
+}
+
+// NodeID devuelve InstanceID o, si está vacío, "<hostname>-<pid>": dos
+// procesos sin INSTANCE_ID no comparten identidad.
+func (c Config) NodeID() string {
+	if c.InstanceID != "" {
+		return c.InstanceID
+	}
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "localhost"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// Load arma la configuración y la valida. Los errores de todas las fuentes
+// se informan juntos, uno por línea.
+func Load(need Need) (*Config, error) {
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := errors.Join(cfg.applyEnv(), cfg.Validate(need)); err != nil {
+		return nil, fmt.Errorf("invalid config:\n%w", err)
+	}
+	return &cfg, nil
+}
+
+// loadFile pisa cfg con los valores del archivo. Las claves desconocidas son
+// un error para que un typo no pase desapercibido.
+func (c *Config) loadFile(path string) error {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+	default:
+		return fmt.Errorf("config file %s: unsupported format %q, use .yaml or .yml", path, ext)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate informa todos los valores inválidos con la clave del archivo.
+func (c *Config) Validate(need Need) error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "server.port", "must be between 1 and 65535, got %d", c.Server.Port)
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout", "must be positive")
+	check(c.Health.DrainDelay >= 0, "health.drain_delay", "must not be negative")
+
+	if need&NeedDB != 0 {
+		check(c.DB.Host != "", "db.host", "is required (DB_HOST)")
+		check(c.DB.User != "", "db.user", "is required (DB_USER)")
+		check(c.DB.Database != "", "db.database", "is required (DB_DATABASE)")
+	}
+	check(c.DB.Port > 0 && c.DB.Port <= 65535, "db.port", "must be between 1 and 65535, got %d", c.DB.Port)
+	switch c.DB.SSLMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		check(false, "db.sslmode", "must be one of disable, allow, prefer, require, verify-ca, verify-full, got %q", c.DB.SSLMode)
+	}
+	check(c.DB.MaxConns > 0, "db.max_conns", "must be positive, got %d", c.DB.MaxConns)
+	check(c.DB.MinConns >= 0 && c.DB.MinConns <= c.DB.MaxConns, "db.min_conns", "must be between 0 and db.max_conns (%d), got %d", c.DB.MaxConns, c.DB.MinConns)
+	check(c.DB.MaxConnLifetime > 0, "db.max_conn_lifetime", "must be positive")
+	check(c.DB.MaxConnIdleTime > 0, "db.max_conn_idle_time", "must be positive")
+
+	if need&NeedBroker != 0 {
+		check(c.Broker.URL != "", "broker.url", "is required (RABBITMQ_URL)")
+	}
+	if c.Broker.URL != "" {
+		u, err := url.Parse(c.Broker.URL)
+		check(err == nil && (u.Scheme == "amqp" || u.Scheme == "amqps"), "broker.url", "must be an amqp:// or amqps:// URL")
+	}
+	check(c.Broker.Queue != "", "broker.queue", "must not be empty")
+
+	check(c.Dispatcher.PollInterval > 0, "dispatcher.poll_interval", "must be positive")
+	check(c.Dispatcher.BatchSize > 0, "dispatcher.batch_size", "must be positive, got %d", c.Dispatcher.BatchSize)
+	check(c.Dispatcher.PerTenantLimit > 0 && c.Dispatcher.PerTenantLimit <= c.Dispatcher.BatchSize, "dispatcher.per_tenant_limit",
+		"must be between 1 and dispatcher.batch_size (%d), got %d", c.Dispatcher.BatchSize, c.Dispatcher.PerTenantLimit)
+	check(c.Dispatcher.LockTimeout > 0, "dispatcher.lock_timeout", "must be positive")
+	check(c.Dispatcher.MaxTickAge > c.Dispatcher.PollInterval, "dispatcher.max_tick_age",
+		"must be greater than dispatcher.poll_interval (%s), got %s", c.Dispatcher.PollInterval, c.Dispatcher.MaxTickAge)
+
+	check(c.Worker.TenantPollInterval > 0, "worker.tenant_poll_interval", "must be positive")
+	check(c.Worker.CallbackTimeout > 0, "worker.callback_timeout", "must be positive")
+	check(c.Worker.CircuitBreaker.FailureThreshold > 0, "worker.circuit_breaker.failure_threshold", "must be positive, got %d", c.Worker.CircuitBreaker.FailureThreshold)
+	check(c.Worker.CircuitBreaker.CoolDown > 0, "worker.circuit_breaker.cooldown", "must be positive")
+	check(c.Worker.CircuitBreaker.HalfOpenMaxCalls > 0, "worker.circuit_breaker.half_open_max_calls", "must be positive, got %d", c.Worker.CircuitBreaker.HalfOpenMaxCalls)
+
+	check(c.Notifier.BatchSize > 0, "notifier.batch_size", "must be positive, got %d", c.Notifier.BatchSize)
+	check(c.Notifier.PollInterval > 0, "notifier.poll_interval", "must be positive")
+
+	if _, err := c.Callbacks.Policy(); err != nil {
+		errs = append(errs, fmt.Errorf("callbacks: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Settings devuelve la configuración del circuit breaker por host.
+func (c CircuitBreakerConfig) Settings() domain.CircuitBreakerSettings {
+	return domain.CircuitBreakerSettings{
+		FailureThreshold: c.FailureThreshold,
+		CoolDown:         c.CoolDown,
+		HalfOpenMaxCalls: c.HalfOpenMaxCalls,
+	}
+}
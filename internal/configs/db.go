@@ -3,24 +3,34 @@ package configs
 import (
 	"context"
 	"fmt"
-	"os"
+	"net"
+	"net/url"
+	"strconv"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func NewDBConnection() (*pgxpool.Pool, error) {
-	// Construir la cadena de conexión
-	connString := fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=require",
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_DATABASE"),
-	)
+// ConnString arma la cadena de conexión de Postgres.
+func (c DBConfig) ConnString() string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.User, c.Password),
+		Host:     net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+		Path:     "/" + c.Database,
+		RawQuery: url.Values{"sslmode": {c.SSLMode}}.Encode(),
+	}
+	return u.String()
+}
 
-	cfg, err := pgxpool.ParseConfig(connString)
+func NewDBConnection(c DBConfig) (*pgxpool.Pool, error) {
+	cfg, err := pgxpool.ParseConfig(c.ConnString())
 	if err != nil {
 		return nil, fmt.Errorf("create connection pool: %w", err)
 	}
+	cfg.MaxConns = c.MaxConns
+	cfg.MinConns = c.MinConns
+	cfg.MaxConnLifetime = c.MaxConnLifetime
+	cfg.MaxConnIdleTime = c.MaxConnIdleTime
 
 	// Conectar al pool de conexiones
 	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
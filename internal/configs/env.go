@@ -0,0 +1,137 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// applyEnv pisa la configuración con las variables de entorno definidas. Las
+// variables vacías se ignoran, así un KEY= del .env no borra el archivo. Un
+// valor que no parsea es un error.
+func (c *Config) applyEnv() error {
+	e := &envReader{}
+
+	e.str("INSTANCE_ID", &c.InstanceID)
+	e.str("METRICS_ADDR", &c.MetricsAddr)
+
+	e.int("JOBS_SERVICE_PORT", &c.Server.Port)
+	e.duration("SERVER_SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout)
+
+	e.str("HEALTH_ADDR", &c.Health.Addr)
+	e.duration("HEALTH_DRAIN_DELAY", &c.Health.DrainDelay)
+
+	e.str("DB_HOST", &c.DB.Host)
+	e.int("DB_PORT", &c.DB.Port)
+	e.str("DB_USER", &c.DB.User)
+	e.str("DB_PASSWORD", &c.DB.Password)
+	e.str("DB_DATABASE", &c.DB.Database)
+	e.str("DB_SSLMODE", &c.DB.SSLMode)
+	e.int32("DB_MAX_CONNS", &c.DB.MaxConns)
+	e.int32("DB_MIN_CONNS", &c.DB.MinConns)
+	e.duration("DB_MAX_CONN_LIFETIME", &c.DB.MaxConnLifetime)
+	e.duration("DB_MAX_CONN_IDLE_TIME", &c.DB.MaxConnIdleTime)
+	e.bool("SCHEMA_CHECK", &c.DB.SchemaCheck)
+	e.bool("SCHEDULER_DB_NOW", &c.DB.ServerClock)
+
+	e.str("RABBITMQ_URL", &c.Broker.URL)
+	e.str("RABBITMQ_QUEUE", &c.Broker.Queue)
+
+	e.duration("DISPATCHER_POLL_INTERVAL", &c.Dispatcher.PollInterval)
+	e.int("DISPATCHER_BATCH_SIZE", &c.Dispatcher.BatchSize)
+	e.int("DISPATCHER_PER_TENANT_LIMIT", &c.Dispatcher.PerTenantLimit)
+	e.duration("DISPATCHER_LOCK_TIMEOUT", &c.Dispatcher.LockTimeout)
+	e.duration("DISPATCHER_MAX_TICK_AGE", &c.Dispatcher.MaxTickAge)
+
+	e.duration("WORKER_TENANT_POLL_INTERVAL", &c.Worker.TenantPollInterval)
+	e.duration("WORKER_CALLBACK_TIMEOUT", &c.Worker.CallbackTimeout)
+	e.int("CIRCUIT_BREAKER_FAILURE_THRESHOLD", &c.Worker.CircuitBreaker.FailureThreshold)
+	e.duration("CIRCUIT_BREAKER_COOLDOWN", &c.Worker.CircuitBreaker.CoolDown)
+	e.int("CIRCUIT_BREAKER_HALF_OPEN_MAX_CALLS", &c.Worker.CircuitBreaker.HalfOpenMaxCalls)
+
+	e.int("WEBHOOK_BATCH_SIZE", &c.Notifier.BatchSize)
+	e.duration("NOTIFIER_POLL_INTERVAL", &c.Notifier.PollInterval)
+
+	e.bool("API_AUTH_ENABLED", &c.Auth.Enabled)
+	e.str("API_ADMIN_KEY", &c.Auth.AdminKey)
+
+	e.list("CALLBACK_ALLOWED_SCHEMES", &c.Callbacks.AllowedSchemes)
+	e.list("CALLBACK_ALLOWED_HOSTS", &c.Callbacks.AllowedHosts)
+	e.list("CALLBACK_ALLOWED_CIDRS", &c.Callbacks.AllowedCIDRs)
+
+	return errors.Join(e.errs...)
+}
+
+// envReader junta los errores de parseo para informarlos todos juntos.
+type envReader struct {
+	errs []error
+}
+
+func (e *envReader) lookup(key string) (string, bool) {
+	val := strings.TrimSpace(os.Getenv(key))
+	return val, val != ""
+}
+
+func (e *envReader) fail(key, val, kind string) {
+	e.errs = append(e.errs, fmt.Errorf("%s: invalid %s %q", key, kind, val))
+}
+
+func (e *envReader) str(key string, dst *string) {
+	if val, ok := e.lookup(key); ok {
+		*dst = val
+	}
+}
+
+func (e *envReader) int(key string, dst *int) {
+	if val, ok := e.lookup(key); ok {
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			e.fail(key, val, "integer")
+			return
+		}
+		*dst = n
+	}
+}
+
+func (e *envReader) int32(key string, dst *int32) {
+	if val, ok := e.lookup(key); ok {
+		n, err := strconv.ParseInt(val, 10, 32)
+		if err != nil {
+			e.fail(key, val, "integer")
+			return
+		}
+		*dst = int32(n)
+	}
+}
+
+func (e *envReader) duration(key string, dst *time.Duration) {
+	if val, ok := e.lookup(key); ok {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			e.fail(key, val, "duration (e.g. 30s, 5m)")
+			return
+		}
+		*dst = d
+	}
+}
+
+func (e *envReader) bool(key string, dst *bool) {
+	if val, ok := e.lookup(key); ok {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			e.fail(key, val, "boolean")
+			return
+		}
+		*dst = b
+	}
+}
+
+// list parsea valores separados por coma.
+func (e *envReader) list(key string, dst *[]string) {
+	if val, ok := e.lookup(key); ok {
+		*dst = strings.Split(val, ",")
+	}
+}
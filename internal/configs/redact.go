@@ -0,0 +1,60 @@
+package configs
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// LogValue implements slog.LogValuer: la configuración se loguea al arrancar
+// con las claves del archivo y los secretos (tag secret) ocultos.
+func (c Config) LogValue() slog.Value {
+	return structValue(reflect.ValueOf(c))
+}
+
+// structValue arma un grupo con los campos de v; los structs anidados son
+// subgrupos.
+func structValue(v reflect.Value) slog.Value {
+	attrs := make([]slog.Attr, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		attrs = append(attrs, slog.Attr{Key: key, Value: fieldValue(v.Field(i), field.Tag.Get("secret"))})
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func fieldValue(v reflect.Value, secret string) slog.Value {
+	switch {
+	case v.Kind() == reflect.Struct:
+		return structValue(v)
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		return slog.StringValue(time.Duration(v.Int()).String())
+	case v.Kind() == reflect.Slice:
+		return slog.StringValue(fmt.Sprint(v.Interface()))
+	case v.Kind() == reflect.String && v.String() != "" && secret != "":
+		return slog.StringValue(redact(v.String(), secret))
+	default:
+		return slog.AnyValue(v.Interface())
+	}
+}
+
+// redact oculta un secreto. Con secret:"url" solo se oculta la contraseña,
+// así se ve a qué host apunta.
+func redact(val, secret string) string {
+	if secret == "url" {
+		if u, err := url.Parse(val); err == nil {
+			if _, ok := u.User.Password(); ok {
+				return u.Redacted()
+			}
+			return val
+		}
+	}
+	return "REDACTED"
+}
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyScope string
+
+const (
+	APIKeyScopeCreate APIKeyScope = "create" // crear jobs y demás escrituras no administrativas
+	APIKeyScopeRead   APIKeyScope = "read"   // consultar jobs, intentos, eventos y stats
+	APIKeyScopeAdmin  APIKeyScope = "admin"  // rutas /admin, webhooks y gestión de keys; incluye los demás scopes
+)
+
+// apiKeyPrefix identifica a simple vista los tokens de este servicio.
+const apiKeyPrefix = "jsk_"
+
+// IsValid reports whether s is a known scope.
+func (s APIKeyScope) IsValid() bool {
+	switch s {
+	case APIKeyScopeCreate, APIKeyScopeRead, APIKeyScopeAdmin:
+		return true
+	}
+	return false
+}
+
+// APIKey authenticates API clients. Only the SHA-256 of the token is stored;
+// the token itself is returned once, when the key is created. Empty
+// AllowedJobTypes means the key can create jobs of any type.
+type APIKey struct {
+	ID   uuid.UUID `db:"id" json:"id"`
+	Name string    `db:"name" json:"name"`
+	// Tenant limita la key a los datos de un tenant; vacío = todos los tenants (solo operadores)
+	Tenant          string        `db:"tenant" json:"tenant"`
+	Prefix          string        `db:"prefix" json:"prefix"` // primeros caracteres del token, para reconocerlo
+	Hash            string        `db:"key_hash" json:"-"`
+	Scopes          []APIKeyScope `db:"scopes" json:"scopes"`
+	AllowedJobTypes []string      `db:"allowed_job_types" json:"allowed_job_types"`
+	LastUsedAt      *time.Time    `db:"last_used_at" json:"last_used_at"`
+	RevokedAt       *time.Time    `db:"revoked_at" json:"revoked_at"`
+	CreatedAt       time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+// CreatedAPIKey is the response of key creation, the only time the token is visible.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// APIKeySearchParams defines the parameters for searching API keys.
+type APIKeySearchParams struct {
+	ID             *uuid.UUID
+	Hash           *string
+	Tenant         *string
+	IncludeRevoked bool
+}
+
+// APIKeyInput represents the input required to create an API key.
+type APIKeyInput struct {
+	Name            string        `json:"name"`
+	Tenant          string        `json:"tenant"`
+	Scopes          []APIKeyScope `json:"scopes"`
+	AllowedJobTypes []string      `json:"allowed_job_types"`
+}
+
+// NewAPIKey validates the input and generates a new token.
+func NewAPIKey(input APIKeyInput, now time.Time) (*CreatedAPIKey, error) {
+	v := &ValidationError{}
+
+	if strings.TrimSpace(input.Name) == "" {
+		v.Add("name", "required", "name is required")
+	} else if len(input.Name) > 255 {
+		v.Add("name", "too_long", "name must be at most 255 characters")
+	}
+
+	if len(input.Scopes) == 0 {
+		v.Add("scopes", "required", "at least one scope is required")
+	}
+	for _, s := range input.Scopes {
+		if !s.IsValid() {
+			v.Add("scopes", "invalid_value", "unknown scope "+strconv.Quote(string(s)))
+		}
+	}
+
+	if input.Tenant != "" && !ValidTenant(input.Tenant) {
+		v.Add("tenant", "invalid_value", "tenant must be lowercase letters, digits, '-' or '_' (max 63)")
+	}
+
+	for _, t := range input.AllowedJobTypes {
+		if strings.TrimSpace(t) == "" {
+			v.Add("allowed_job_types", "required", "allowed_job_types cannot contain empty types")
+		}
+	}
+
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	allowedJobTypes := input.AllowedJobTypes
+	if allowedJobTypes == nil {
+		allowedJobTypes = []string{}
+	}
+
+	token := newAPIKeyToken()
+	return &CreatedAPIKey{
+		APIKey: APIKey{
+			ID:              uuid.New(),
+			Name:            input.Name,
+			Tenant:          input.Tenant,
+			Prefix:          token[:len(apiKeyPrefix)+8],
+			Hash:            HashAPIKey(token),
+			Scopes:          input.Scopes,
+			AllowedJobTypes: allowedJobTypes,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		},
+		Key: token,
+	}, nil
+}
+
+// HashAPIKey returns the stored form of a token. Los tokens tienen 256 bits
+// aleatorios, por eso alcanza con SHA-256 sin salt y se puede buscar por hash.
+func HashAPIKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// HasScope reports whether the key grants scope. admin grants every scope.
+func (k APIKey) HasScope(scope APIKeyScope) bool {
+	return slices.Contains(k.Scopes, APIKeyScopeAdmin) || slices.Contains(k.Scopes, scope)
+}
+
+// AllowsJobType reports whether the key can create jobs of type jobType.
+func (k APIKey) AllowsJobType(jobType string) bool {
+	return len(k.AllowedJobTypes) == 0 || slices.Contains(k.AllowedJobTypes, jobType)
+}
+
+// IsRevoked reports whether the key was revoked.
+func (k APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+func newAPIKeyToken() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return apiKeyPrefix + hex.EncodeToString(b)
+}
@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// BulkChunkSize es cuántos jobs se insertan por COPY (y por transacción si no es all-or-nothing).
+	BulkChunkSize = 500
+	// MaxBulkJobs es el máximo de items aceptados en un POST /jobs/batch.
+	MaxBulkJobs = 100_000
+
+	// BatchCompletedJobType es el tipo del job que entrega el callback de un lote terminado.
+	BatchCompletedJobType = "batch.completed"
+	// BatchCallbackMaxRetries son los reintentos del callback del lote.
+	BatchCallbackMaxRetries = 5
+)
+
+// ErrInvalidJSON marca un item del lote que no se pudo decodificar.
+var ErrInvalidJSON = errors.New("invalid json")
+
+// JobBatch groups the jobs created by one POST /jobs/batch.
+type JobBatch struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	Tenant       string    `db:"tenant" json:"tenant"`
+	Total        int       `db:"total" json:"total"`         // items recibidos
+	Created      int       `db:"created" json:"created"`     // jobs insertados
+	Rejected     int       `db:"rejected" json:"rejected"`   // items rechazados
+	Succeeded    int       `db:"succeeded" json:"succeeded"` // miembros completados
+	Failed       int       `db:"failed" json:"failed"`       // miembros muertos
+	AllOrNothing bool      `db:"all_or_nothing" json:"all_or_nothing"`
+	CallbackURL  *string   `db:"callback_url" json:"callback_url"`
+	// CallbackJobID es el job encolado para avisar a CallbackURL que el lote terminó
+	CallbackJobID  *uuid.UUID `db:"callback_job_id" json:"callback_job_id"`
+	CreatedByKeyID *uuid.UUID `db:"created_by_key_id" json:"created_by_key_id"`
+	// SealedAt marca que ya se recibieron todos los items; antes no puede completarse
+	SealedAt    *time.Time `db:"sealed_at" json:"sealed_at"`
+	CompletedAt *time.Time `db:"completed_at" json:"completed_at"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// JobBatchSearchParams defines the parameters for searching batches.
+type JobBatchSearchParams struct {
+	ID     *uuid.UUID
+	Tenant *string
+}
+
+// Done reports whether every job of a sealed batch reached a terminal state.
+func (b JobBatch) Done() bool {
+	return b.SealedAt != nil && b.Created > 0 && b.Succeeded+b.Failed >= b.Created
+}
+
+// Outcome resume el resultado del lote: succeeded, failed o partial.
+func (b JobBatch) Outcome() string {
+	switch {
+	case b.Failed == 0:
+		return "succeeded"
+	case b.Succeeded == 0:
+		return "failed"
+	default:
+		return "partial"
+	}
+}
+
+// BatchStatus is the response of GET /batches/{id}.
+type BatchStatus struct {
+	JobBatch
+	// Counts son los jobs del lote por estado
+	Counts map[JobStatus]int `json:"counts"`
+	// Progress es el porcentaje de jobs en estado terminal (completed o dead)
+	Progress float64 `json:"progress"`
+}
+
+// NewBatchStatus arma el estado del lote a partir de los jobs por estado.
+func NewBatchStatus(batch JobBatch, counts map[JobStatus]int) *BatchStatus {
+	if counts == nil {
+		counts = map[JobStatus]int{}
+	}
+
+	progress := 0.0
+	switch {
+	case batch.Created > 0:
+		progress = float64(batch.Succeeded+batch.Failed) / float64(batch.Created) * 100
+		progress = math.Round(progress*100) / 100
+	case batch.SealedAt != nil:
+		// Un lote sin jobs no tiene nada pendiente
+		progress = 100
+	}
+
+	return &BatchStatus{JobBatch: batch, Counts: counts, Progress: progress}
+}
+
+// NewBatchCompletedJob crea el job que entrega el callback del lote con el
+// resumen de éxitos y fallos. Se reintenta como cualquier otro job.
+func NewBatchCompletedJob(batch JobBatch, now time.Time) (*Job, error) {
+	payload, err := json.Marshal(map[string]any{
+		"batch_id":     batch.ID,
+		"outcome":      batch.Outcome(),
+		"total":        batch.Total,
+		"created":      batch.Created,
+		"rejected":     batch.Rejected,
+		"succeeded":    batch.Succeeded,
+		"failed":       batch.Failed,
+		"completed_at": batch.CompletedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewJob(CreateJobInput{
+		Tenant:      batch.Tenant,
+		Type:        BatchCompletedJobType,
+		CallbackURL: *batch.CallbackURL,
+		Payload:     payload,
+		MaxRetries:  BatchCallbackMaxRetries,
+		// El callback se atribuye a la key que creó el lote
+		CreatedByKeyID: batch.CreatedByKeyID,
+	}, now)
+}
+
+// NewJobBatch crea el registro de un lote vacío; los contadores se rellenan al procesarlo.
+func NewJobBatch(tenant string, createdByKeyID *uuid.UUID, opts BulkCreateOptions, now time.Time) JobBatch {
+	now = now.UTC()
+	return JobBatch{
+		ID:             uuid.New(),
+		Tenant:         tenant,
+		AllOrNothing:   opts.AllOrNothing,
+		CallbackURL:    opts.CallbackURL,
+		CreatedByKeyID: createdByKeyID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// BulkCreateOptions controls how a batch is created.
+type BulkCreateOptions struct {
+	// AllOrNothing inserta todo en una sola transacción y no crea nada si algún item es inválido
+	AllOrNothing bool
+	// CallbackURL recibe un POST con el resumen cuando todos los jobs del lote terminan
+	CallbackURL *string
+}
+
+// Validate checks the options and returns a *ValidationError listing every problem.
+func (o BulkCreateOptions) Validate() error {
+	v := &ValidationError{}
+
+	if o.CallbackURL != nil {
+		if u, err := url.Parse(*o.CallbackURL); err != nil || !u.IsAbs() || u.Host == "" {
+			v.Add("callback_url", "invalid_url", "callback_url must be an absolute URL")
+		}
+	}
+
+	return v.Err()
+}
+
+// BulkItemError explains why an item of a batch was rejected.
+type BulkItemError struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// BulkItemResult is the outcome of one item, in request order.
+type BulkItemResult struct {
+	Index int        `json:"index"`
+	JobID *uuid.UUID `json:"job_id,omitempty"`
+	// NotifySecret es el secreto de la suscripción de notify_url, visible solo acá
+	NotifySecret *string        `json:"notify_secret,omitempty"`
+	Error        *BulkItemError `json:"error,omitempty"`
+}
+
+// BulkCreateResult is the response of POST /jobs/batch.
+type BulkCreateResult struct {
+	JobBatch
+	// RolledBack indica que, en modo all-or-nothing, un item inválido descartó todo el lote
+	RolledBack bool             `json:"rolled_back"`
+	Items      []BulkItemResult `json:"items"`
+}
+
+// NewBulkItemError traduce el error de un item al código que se devuelve al cliente.
+func NewBulkItemError(err error) *BulkItemError {
+	var validationErr *ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return &BulkItemError{Code: "validation_failed", Message: "one or more fields are invalid", Fields: validationErr.Fields}
+	case errors.Is(err, ErrInvalidJSON):
+		return &BulkItemError{Code: "invalid_json", Message: err.Error()}
+	case errors.Is(err, ErrCallbackNotAllowed):
+		return &BulkItemError{Code: "callback_not_allowed", Message: err.Error()}
+	case errors.Is(err, ErrForbidden):
+		return &BulkItemError{Code: "forbidden", Message: err.Error()}
+	case errors.Is(err, ErrQuotaExceeded):
+		return &BulkItemError{Code: "quota_exceeded", Message: err.Error()}
+	default:
+		return &BulkItemError{Code: "invalid", Message: err.Error()}
+	}
+}
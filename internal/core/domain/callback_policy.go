@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+var ErrCallbackNotAllowed = errors.New("callback url not allowed")
+
+// rangos especiales que no cubren los helpers de net.IP
+var reservedCIDRs = mustParseCIDRs(
+	"0.0.0.0/8",     // "this network"
+	"100.64.0.0/10", // CGNAT
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // benchmarking
+)
+
+// CallbackPolicy decides which callback URLs the workers are allowed to call.
+// Addresses inside AllowedCIDRs are always accepted. Otherwise private, loopback
+// and link-local addresses are rejected and, when AllowedHosts is not empty,
+// the host must match it.
+type CallbackPolicy struct {
+	AllowedSchemes []string     // ej. http, https
+	AllowedHosts   []string     // hosts exactos o comodines "*.example.com"
+	AllowedCIDRs   []*net.IPNet // rangos permitidos explícitamente, aunque sean privados
+}
+
+func NewCallbackPolicy(schemes, hosts, cidrs []string) (*CallbackPolicy, error) {
+	policy := &CallbackPolicy{}
+
+	for _, s := range schemes {
+		if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+			policy.AllowedSchemes = append(policy.AllowedSchemes, s)
+		}
+	}
+	if len(policy.AllowedSchemes) == 0 {
+		policy.AllowedSchemes = []string{"http", "https"}
+	}
+
+	for _, h := range hosts {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			policy.AllowedHosts = append(policy.AllowedHosts, h)
+		}
+	}
+
+	for _, c := range cidrs {
+		if c = strings.TrimSpace(c); c == "" {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid callback cidr %q: %w", c, err)
+		}
+		policy.AllowedCIDRs = append(policy.AllowedCIDRs, ipNet)
+	}
+
+	return policy, nil
+}
+
+// ValidateURL checks scheme and host against the allowlist without resolving DNS.
+func (p *CallbackPolicy) ValidateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("%w: malformed url", ErrCallbackNotAllowed)
+	}
+
+	if !contains(p.AllowedSchemes, strings.ToLower(u.Scheme)) {
+		return fmt.Errorf("%w: scheme %q is not allowed", ErrCallbackNotAllowed, u.Scheme)
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: credentials in url are not allowed", ErrCallbackNotAllowed)
+	}
+
+	host := strings.ToLower(u.Hostname())
+
+	// IP literal: se valida directamente contra los rangos
+	if ip := net.ParseIP(host); ip != nil {
+		return p.CheckAddress(host, ip)
+	}
+
+	// Sin CIDRs en la allowlist no hace falta resolver para rechazar el host
+	if len(p.AllowedHosts) > 0 && len(p.AllowedCIDRs) == 0 && !p.hostAllowed(host) {
+		return fmt.Errorf("%w: host %q is not in the allowlist", ErrCallbackNotAllowed, host)
+	}
+
+	return nil
+}
+
+// Validate checks the URL and every address its host resolves to.
+func (p *CallbackPolicy) Validate(ctx context.Context, rawURL string) error {
+	if err := p.ValidateURL(rawURL); err != nil {
+		return err
+	}
+
+	u, _ := url.Parse(rawURL)
+	host := strings.ToLower(u.Hostname())
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("%w: cannot resolve host %q", ErrCallbackNotAllowed, host)
+	}
+	for _, addr := range addrs {
+		if err := p.CheckAddress(host, addr.IP); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckAddress validates one resolved address of host.
+func (p *CallbackPolicy) CheckAddress(host string, ip net.IP) error {
+	for _, allowed := range p.AllowedCIDRs {
+		if allowed.Contains(ip) {
+			return nil
+		}
+	}
+
+	if isReserved(ip) {
+		return fmt.Errorf("%w: address %s is private or reserved", ErrCallbackNotAllowed, ip)
+	}
+
+	if len(p.AllowedHosts) > 0 && !p.hostAllowed(strings.ToLower(host)) {
+		return fmt.Errorf("%w: host %q is not in the allowlist", ErrCallbackNotAllowed, host)
+	}
+
+	return nil
+}
+
+func (p *CallbackPolicy) hostAllowed(host string) bool {
+	for _, allowed := range p.AllowedHosts {
+		if strings.HasPrefix(allowed, "*.") {
+			if strings.HasSuffix(host, allowed[1:]) {
+				return true
+			}
+			continue
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func isReserved(ip net.IP) bool {
+	if ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() {
+		return true
+	}
+	for _, reserved := range reservedCIDRs {
+		if reserved.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		_, ipNet, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets
+}
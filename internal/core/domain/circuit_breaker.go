@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type CircuitState string
+
+const (
+	CircuitStateClosed   CircuitState = "closed"    // ejecuciones normales
+	CircuitStateOpen     CircuitState = "open"      // host caído, se cortan las ejecuciones
+	CircuitStateHalfOpen CircuitState = "half_open" // se dejan pasar algunas pruebas
+)
+
+// CircuitBreakerSettings configures the per-host breakers of an executor.
+type CircuitBreakerSettings struct {
+	FailureThreshold int           // fallos consecutivos para abrir el circuito
+	CoolDown         time.Duration // tiempo abierto antes de pasar a half-open
+	HalfOpenMaxCalls int           // ejecuciones de prueba simultáneas en half-open
+}
+
+// CircuitBreaker is a snapshot of the breaker of a callback host in a worker node.
+type CircuitBreaker struct {
+	NodeID    string       `db:"node_id" json:"node_id"`
+	Host      string       `db:"host" json:"host"`
+	State     CircuitState `db:"state" json:"state"`
+	Failures  int          `db:"failures" json:"failures"`
+	OpenedAt  *time.Time   `db:"opened_at" json:"opened_at"`
+	RetryAt   *time.Time   `db:"retry_at" json:"retry_at"`
+	UpdatedAt time.Time    `db:"updated_at" json:"updated_at"`
+}
+
+// CircuitBreakerSearchParams defines the parameters for searching circuit breakers.
+type CircuitBreakerSearchParams struct {
+	NodeID *string
+	Host   *string
+	State  *CircuitState
+}
+
+// CircuitOpenError is returned by an executor that short-circuited a job.
+type CircuitOpenError struct {
+	Host    string
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit open for host %s until %s", e.Host, e.RetryAt.Format(time.RFC3339))
+}
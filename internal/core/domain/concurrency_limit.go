@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// ConcurrencySlotLease es el tiempo tras el cual un slot huérfano (worker caído) se libera.
+	ConcurrencySlotLease = 15 * time.Minute
+	// ConcurrencyRetryDelay es cuánto se difiere un job que no consiguió slot.
+	ConcurrencyRetryDelay = 2 * time.Second
+)
+
+// ConcurrencyLimit caps how many jobs of a type may run at once across the cluster.
+type ConcurrencyLimit struct {
+	JobType       string    `db:"job_type" json:"job_type"`
+	MaxConcurrent int       `db:"max_concurrent" json:"max_concurrent"`
+	Running       int       `db:"running" json:"running"` // slots ocupados al momento de la consulta
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// ConcurrencyLimitSearchParams defines the parameters for searching concurrency limits.
+type ConcurrencyLimitSearchParams struct {
+	JobType *string
+}
+
+// ConcurrencyLimitInput represents the input required to create or update a concurrency limit.
+type ConcurrencyLimitInput struct {
+	JobType       string `json:"job_type"`
+	MaxConcurrent int    `json:"max_concurrent"`
+}
+
+func NewConcurrencyLimit(input ConcurrencyLimitInput, now time.Time) (*ConcurrencyLimit, error) {
+	v := &ValidationError{}
+
+	jobType := strings.TrimSpace(input.JobType)
+	if jobType == "" {
+		v.Add("job_type", "required", "job_type is required")
+	}
+	if input.MaxConcurrent < 1 {
+		v.Add("max_concurrent", "out_of_range", "max_concurrent must be at least 1")
+	}
+
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	return &ConcurrencyLimit{
+		JobType:       jobType,
+		MaxConcurrent: input.MaxConcurrent,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
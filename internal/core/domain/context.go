@@ -0,0 +1,47 @@
+package domain
+
+import "context"
+
+type contextKey string
+
+const (
+	workerIDKey contextKey = "worker_id"
+	apiKeyKey   contextKey = "api_key"
+)
+
+// WithWorkerID returns a context carrying the identity of the worker processing a job.
+func WithWorkerID(ctx context.Context, workerID string) context.Context {
+	return context.WithValue(ctx, workerIDKey, workerID)
+}
+
+// WorkerIDFrom returns the worker identity stored in ctx, if any.
+func WorkerIDFrom(ctx context.Context) *string {
+	workerID, ok := ctx.Value(workerIDKey).(string)
+	if !ok || workerID == "" {
+		return nil
+	}
+	return &workerID
+}
+
+// WithAPIKey returns a context carrying the API key that authenticated the request.
+func WithAPIKey(ctx context.Context, key *APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyKey, key)
+}
+
+// TenantFrom returns the tenant the request is restricted to, or nil when it
+// can see every tenant (auth disabled, keys without tenant and internal callers
+// such as the worker and the dispatcher).
+func TenantFrom(ctx context.Context) *string {
+	key := APIKeyFrom(ctx)
+	if key == nil || key.Tenant == "" {
+		return nil
+	}
+	return &key.Tenant
+}
+
+// APIKeyFrom returns the API key stored in ctx, or nil when the request was
+// not authenticated (auth disabled or internal callers).
+func APIKeyFrom(ctx context.Context) *APIKey {
+	key, _ := ctx.Value(apiKeyKey).(*APIKey)
+	return key
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrConflict = errors.New("conflict")
+	ErrInvalid  = errors.New("invalid input")
+	// ErrUnauthorized: falta la API key o no es válida
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden: la API key no tiene el scope o el tipo de job requerido
+	ErrForbidden = errors.New("forbidden")
+	// ErrQuotaExceeded: el tenant alcanzó su cuota de jobs pendientes
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrPreconditionFailed: el recurso cambió desde la versión (ETag) que tenía el cliente
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrPreconditionRequired: la modificación exige indicar la versión esperada
+	ErrPreconditionRequired = errors.New("precondition required")
+)
+
+// FieldError describes why a single input field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError groups the field errors of an input. It matches ErrInvalid.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Field+": "+f.Message)
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalid
+}
+
+// Add registra un error para el campo.
+func (e *ValidationError) Add(field, code, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Err devuelve nil si no se registró ningún error.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "github.com/google/uuid"
+
+// StreamEvent is an event as pushed to live subscribers, together with the
+// type and current status of its job.
+type StreamEvent struct {
+	Event
+	JobType   string    `json:"job_type"`
+	JobStatus JobStatus `json:"job_status"`
+}
+
+// EventStreamFilter selects which events a subscriber receives.
+type EventStreamFilter struct {
+	Tenant     *string
+	JobID      *uuid.UUID
+	JobType    *string
+	JobStatus  *JobStatus
+	EventTypes []EventType
+}
+
+// Matches reports whether the event passes the filter.
+func (f EventStreamFilter) Matches(e StreamEvent) bool {
+	if f.Tenant != nil && e.Tenant != *f.Tenant {
+		return false
+	}
+	if f.JobID != nil && e.JobID != *f.JobID {
+		return false
+	}
+	if f.JobType != nil && e.JobType != *f.JobType {
+		return false
+	}
+	if f.JobStatus != nil && e.JobStatus != *f.JobStatus {
+		return false
+	}
+	if len(f.EventTypes) > 0 {
+		for _, t := range f.EventTypes {
+			if e.Type == t {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// SearchParams converts the filter into event search parameters.
+func (f EventStreamFilter) SearchParams() EventSearchParams {
+	return EventSearchParams{
+		Tenant:    f.Tenant,
+		JobID:     f.JobID,
+		Types:     f.EventTypes,
+		JobType:   f.JobType,
+		JobStatus: f.JobStatus,
+	}
+}
@@ -1,8 +1,13 @@
 package domain
 
 import (
+	"bytes"
 	"encoding/json"
 	"job_scheduler_go_rabbitmq/utils"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -36,62 +41,171 @@ const (
 	EventJobSucceeded EventType = "job_succeeded"
 	EventJobFailed    EventType = "job_failed"
 	EventJobDead      EventType = "job_dead"
+	EventJobDeferred  EventType = "job_deferred"
+	EventJobUpdated   EventType = "job_updated"
 )
 
+// IsValid reports whether t is a known event type.
+func (t EventType) IsValid() bool {
+	switch t {
+	case EventJobCreated, EventJobQueued, EventJobRunning, EventJobSucceeded,
+		EventJobFailed, EventJobDead, EventJobDeferred, EventJobUpdated:
+		return true
+	}
+	return false
+}
+
 // Job represents a unit of work to be processed.
 type Job struct {
 	ID          uuid.UUID       `db:"id" json:"id"`
+	Tenant      string          `db:"tenant" json:"tenant"`
 	Type        string          `db:"type" json:"type"`
 	CallbackURL string          `db:"callback_url" json:"callback_url"`
 	Payload     json.RawMessage `db:"payload" json:"payload"`
 	Status      JobStatus       `db:"status" json:"status"`
 	MaxRetries  int             `db:"max_retries" json:"max_retries"`
-	ScheduledAt *time.Time      `db:"scheduled_at" json:"scheduled_at"`
-	LockedAt    *time.Time      `db:"locked_at" json:"locked_at"`
-	LockedBy    *string         `db:"locked_by" json:"locked_by"`
-	CompletedAt *time.Time      `db:"completed_at" json:"completed_at"`
-	Priority    int             `db:"priority" json:"priority"`
-	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time       `db:"updated_at" json:"updated_at"`
+	// Attempts son los intentos ya ejecutados; el próximo es Attempts+1
+	Attempts int `db:"attempts" json:"attempts"`
+	// RetryDeferred marca un job failed cuyo reintento se difirió: no tiene
+	// mensaje en la cola y el dispatcher lo publica a partir de ScheduledAt
+	RetryDeferred bool       `db:"retry_deferred" json:"retry_deferred"`
+	ScheduledAt   *time.Time `db:"scheduled_at" json:"scheduled_at"`
+	LockedAt      *time.Time `db:"locked_at" json:"locked_at"`
+	LockedBy      *string    `db:"locked_by" json:"locked_by"`
+	CompletedAt   *time.Time `db:"completed_at" json:"completed_at"`
+	Priority      int        `db:"priority" json:"priority"`
+	// TraceContext guarda el contexto de trazas (traceparent) del request que creó el job
+	TraceContext map[string]string `db:"trace_context" json:"-"`
+	// CreatedByKeyID es la API key que encoló el job (nil si la auth está deshabilitada)
+	CreatedByKeyID *uuid.UUID `db:"created_by_key_id" json:"created_by_key_id"`
+	// BatchID es el lote de POST /jobs/batch que creó el job
+	BatchID   *uuid.UUID `db:"batch_id" json:"batch_id"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
 }
 
+// CreatedJob is the response of job creation. NotifySecret is the signing
+// secret of the notify_url subscription, visible only here.
+type CreatedJob struct {
+	Job
+	NotifySecret *string `json:"notify_secret,omitempty"`
+}
+
+// JobSortFields are the fields jobs can be sorted and paginated by.
+var JobSortFields = []string{"created_at", "updated_at", "scheduled_at", "priority", "type", "status"}
+
 // JobSearchParams defines the parameters for searching jobs.
 type JobSearchParams struct {
-	ID     *uuid.UUID
-	Type   *string
-	Status *JobStatus
+	ID       *uuid.UUID
+	Tenant   *string
+	Type     *string
+	Status   *JobStatus
+	Statuses []JobStatus
 
-	// Scheduler-specific
-	ReadyToRun  *bool
-	LockFree    *bool
-	LockTimeout *time.Duration
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	ScheduledFrom *time.Time
+	ScheduledTo   *time.Time
+
+	CreatedByKeyID *uuid.UUID
+	BatchID        *uuid.UUID
+
+	// Scheduler-specific. Dispatchable son los jobs que publica el dispatcher:
+	// pending, o failed con el reintento diferido
+	Dispatchable *bool
+	ReadyToRun   *bool
+	LockFree     *bool
+	LockTimeout  *time.Duration
+	// PerTenantLimit reparte el lote del dispatcher: como máximo N jobs por tenant
+	PerTenantLimit *uint
 
 	utils.SearchParams
 }
 
+// IsValid reports whether s is a known job status.
+func (s JobStatus) IsValid() bool {
+	switch s {
+	case JobStatusPending, JobStatusQueued, JobStatusRunning, JobStatusCompleted,
+		JobStatusFailed, JobStatusDead, JobStatusDisabled:
+		return true
+	}
+	return false
+}
+
+// SortValue returns the value of a sort field as used in pagination cursors.
+func (j Job) SortValue(field string) string {
+	switch field {
+	case "updated_at":
+		return j.UpdatedAt.Format(time.RFC3339Nano)
+	case "scheduled_at":
+		if j.ScheduledAt == nil {
+			return ""
+		}
+		return j.ScheduledAt.Format(time.RFC3339Nano)
+	case "priority":
+		return strconv.Itoa(j.Priority)
+	case "type":
+		return j.Type
+	case "status":
+		return string(j.Status)
+	default:
+		return j.CreatedAt.Format(time.RFC3339Nano)
+	}
+}
+
 // Attempt represents an attempt to execute a job.
 type Attempt struct {
 	ID            uuid.UUID     `db:"id" json:"id"`
 	JobID         uuid.UUID     `db:"job_id" json:"job_id"`
+	Tenant        string        `db:"tenant" json:"tenant"`
 	AttemptNumber int           `db:"attempt_number" json:"attempt_number"`
 	StartedAt     time.Time     `db:"started_at" json:"started_at"`
+	FinishedAt    *time.Time    `db:"finished_at" json:"finished_at"`
+	DurationMs    *int64        `db:"duration_ms" json:"duration_ms"`
 	Status        AttemptStatus `db:"status" json:"status"`
 	ErrorMessage  *string       `db:"error_message" json:"error_message"`
 	HTTPStatus    *int          `db:"http_status" json:"http_status"`
+	WorkerID      *string       `db:"worker_id" json:"worker_id"`
 	CreatedAt     time.Time     `db:"created_at" json:"created_at"`
 }
 
+// AttemptSortFields are the fields attempts can be sorted and paginated by.
+var AttemptSortFields = []string{"created_at", "started_at", "attempt_number"}
+
 // AttemptSearchParams defines the parameters for searching job attempts.
 type AttemptSearchParams struct {
-	ID    *uuid.UUID
-	JobID *uuid.UUID
+	ID         *uuid.UUID
+	Tenant     *string
+	JobID      *uuid.UUID
+	JobType    *string
+	Status     *AttemptStatus
+	HTTPStatus *int
+	// HTTPStatusClass filtra por familia de status, ej. 5 para 5xx
+	HTTPStatusClass *int
+	WorkerID        *string
+	StartedFrom     *time.Time
+	StartedTo       *time.Time
 	utils.SearchParams
 }
 
+// SortValue returns the value of a sort field as used in pagination cursors.
+func (a Attempt) SortValue(field string) string {
+	switch field {
+	case "started_at":
+		return a.StartedAt.Format(time.RFC3339Nano)
+	case "attempt_number":
+		return strconv.Itoa(a.AttemptNumber)
+	default:
+		return a.CreatedAt.Format(time.RFC3339Nano)
+	}
+}
+
 // Event represents an event related to a job's lifecycle.
 type Event struct {
 	ID        uuid.UUID       `db:"id" json:"id"`
+	Seq       int64           `db:"seq" json:"seq"` // orden global de inserción, usado como id en los streams
 	JobID     uuid.UUID       `db:"job_id" json:"job_id"`
+	Tenant    string          `db:"tenant" json:"tenant"`
 	Type      EventType       `db:"event_type" json:"event_type"`
 	Message   string          `db:"message" json:"message"`
 	Metadata  json.RawMessage `db:"metadata" json:"metadata"`
@@ -100,8 +214,18 @@ type Event struct {
 
 // EventSearchParams defines the parameters for searching job events.
 type EventSearchParams struct {
-	ID    *uuid.UUID
-	JobID *uuid.UUID
+	ID     *uuid.UUID
+	Tenant *string
+	JobID  *uuid.UUID
+	Types  []EventType
+	Seq    *int64
+	// AfterSeq devuelve solo eventos posteriores (para reanudar un stream)
+	AfterSeq *int64
+
+	// Filtros sobre el job del evento
+	JobType   *string
+	JobStatus *JobStatus
+
 	utils.SearchParams
 }
 
@@ -119,12 +243,22 @@ type JobExecutionResponse struct {
 
 // CreateJobInput represents the input required to create a new job.
 type CreateJobInput struct {
+	// Tenant solo pueden elegirlo las keys sin tenant; el resto usa el de su key
+	Tenant      string          `json:"tenant"`
 	Type        string          `json:"type"`
 	CallbackURL string          `json:"callback_url"`
 	Payload     json.RawMessage `json:"payload"`
 	ScheduledAt *time.Time      `json:"scheduled_at"`
 	MaxRetries  int             `json:"max_retries"`
 	Priority    int             `json:"priority"`
+	// NotifyURL recibe un webhook firmado cuando el job termina, falla o muere
+	NotifyURL *string `json:"notify_url"`
+	// TraceContext lo completa el handler con el contexto de trazas del request
+	TraceContext map[string]string `json:"-"`
+	// CreatedByKeyID lo completa el servicio con la API key del request
+	CreatedByKeyID *uuid.UUID `json:"-"`
+	// BatchID lo completa el servicio en las altas masivas
+	BatchID *uuid.UUID `json:"-"`
 }
 
 type ExecutionResult struct {
@@ -132,66 +266,295 @@ type ExecutionResult struct {
 	Error      error
 }
 
-func NewJob(input CreateJobInput) (*Job, error) {
+// Validate checks the input fields and returns a *ValidationError listing every problem.
+func (in CreateJobInput) Validate() error {
+	v := &ValidationError{}
+
+	if in.Tenant != "" && !ValidTenant(in.Tenant) {
+		v.Add("tenant", "invalid_value", "tenant must be lowercase letters, digits, '-' or '_' (max 63)")
+	}
+
+	if strings.TrimSpace(in.Type) == "" {
+		v.Add("type", "required", "type is required")
+	} else if len(in.Type) > 255 {
+		v.Add("type", "too_long", "type must be at most 255 characters")
+	}
+
+	if strings.TrimSpace(in.CallbackURL) == "" {
+		v.Add("callback_url", "required", "callback_url is required")
+	} else if u, err := url.Parse(in.CallbackURL); err != nil || !u.IsAbs() || u.Host == "" {
+		v.Add("callback_url", "invalid_url", "callback_url must be an absolute URL")
+	}
+
+	if len(in.Payload) > 0 && !json.Valid(in.Payload) {
+		v.Add("payload", "invalid_json", "payload must be valid JSON")
+	}
+
+	if in.MaxRetries < 0 {
+		v.Add("max_retries", "out_of_range", "max_retries must be zero or greater")
+	}
+
+	if in.Priority < 0 {
+		v.Add("priority", "out_of_range", "priority must be zero or greater")
+	}
+
+	if in.NotifyURL != nil {
+		if u, err := url.Parse(*in.NotifyURL); err != nil || !u.IsAbs() || u.Host == "" {
+			v.Add("notify_url", "invalid_url", "notify_url must be an absolute URL")
+		}
+	}
+
+	return v.Err()
+}
+
+// NewJob valida el input y crea el job pending. now es la hora de creación
+// y, si el input no trae scheduled_at, también la de ejecución.
+func NewJob(input CreateJobInput, now time.Time) (*Job, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	// payload y scheduled_at son NOT NULL en la tabla
+	payload := input.Payload
+	if len(payload) == 0 || string(payload) == "null" {
+		payload = json.RawMessage(`{}`)
+	}
+
+	tenant := input.Tenant
+	if tenant == "" {
+		tenant = DefaultTenant
+	}
+
 	job := &Job{
-		ID:          uuid.New(),
-		Type:        input.Type,
-		CallbackURL: input.CallbackURL,
-		Payload:     input.Payload,
-		MaxRetries:  input.MaxRetries,
-		Priority:    input.Priority,
-		Status:      JobStatusPending,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:             uuid.New(),
+		Tenant:         tenant,
+		Type:           input.Type,
+		CallbackURL:    input.CallbackURL,
+		Payload:        payload,
+		MaxRetries:     input.MaxRetries,
+		Priority:       input.Priority,
+		Status:         JobStatusPending,
+		TraceContext:   input.TraceContext,
+		CreatedByKeyID: input.CreatedByKeyID,
+		BatchID:        input.BatchID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
 	}
 
 	if input.ScheduledAt != nil {
 		job.ScheduledAt = input.ScheduledAt
 	} else {
-		job.ScheduledAt = nil
+		job.ScheduledAt = &job.CreatedAt
 	}
 
 	return job, nil
 }
 
-func NewJobSucceededEvent(jobID uuid.UUID) Event {
+// EditableJobStatuses son los estados en los que un job todavía se puede editar.
+var EditableJobStatuses = []JobStatus{JobStatusPending, JobStatusFailed}
+
+// Editable reports whether the job has not been picked up yet (pending) or is
+// waiting for a retry (failed).
+func (j Job) Editable() bool {
+	for _, s := range EditableJobStatuses {
+		if j.Status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ETag identifica la versión del job para la concurrencia optimista de PATCH.
+// Usa microsegundos, la precisión con la que postgres guarda updated_at.
+func (j Job) ETag() string {
+	return `"` + strconv.FormatInt(j.UpdatedAt.UnixMicro(), 36) + `"`
+}
+
+// UpdateJobInput is the body of PATCH /jobs/{id}. Only the fields present are changed.
+type UpdateJobInput struct {
+	ScheduledAt *time.Time      `json:"scheduled_at"`
+	Priority    *int            `json:"priority"`
+	MaxRetries  *int            `json:"max_retries"`
+	Payload     json.RawMessage `json:"payload"`
+	CallbackURL *string         `json:"callback_url"`
+
+	// Versión esperada del job: el ETag del header If-Match o el updated_at
+	// que el cliente leyó. Hace falta uno de los dos.
+	IfMatch   string     `json:"-"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// Validate checks the input fields and returns a *ValidationError listing every problem.
+func (in UpdateJobInput) Validate() error {
+	v := &ValidationError{}
+
+	if in.ScheduledAt == nil && in.Priority == nil && in.MaxRetries == nil && in.Payload == nil && in.CallbackURL == nil {
+		v.Add("body", "required", "at least one of scheduled_at, priority, max_retries, payload or callback_url is required")
+	}
+
+	if in.Priority != nil && *in.Priority < 0 {
+		v.Add("priority", "out_of_range", "priority must be zero or greater")
+	}
+
+	if in.MaxRetries != nil && *in.MaxRetries < 0 {
+		v.Add("max_retries", "out_of_range", "max_retries must be zero or greater")
+	}
+
+	if in.Payload != nil {
+		if !json.Valid(in.Payload) {
+			v.Add("payload", "invalid_json", "payload must be valid JSON")
+		} else if string(in.Payload) == "null" {
+			v.Add("payload", "required", "payload cannot be null")
+		}
+	}
+
+	if in.CallbackURL != nil {
+		if u, err := url.Parse(*in.CallbackURL); err != nil || !u.IsAbs() || u.Host == "" {
+			v.Add("callback_url", "invalid_url", "callback_url must be an absolute URL")
+		}
+	}
+
+	return v.Err()
+}
+
+// Matches reports whether the version the client expects is the job's current one.
+func (in UpdateJobInput) Matches(job Job) bool {
+	if in.IfMatch != "" {
+		return strings.TrimPrefix(in.IfMatch, "W/") == job.ETag()
+	}
+	return in.UpdatedAt != nil && in.UpdatedAt.Equal(job.UpdatedAt)
+}
+
+// JobChange es el valor anterior y el nuevo de un campo editado.
+type JobChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Apply copia en el job los campos del input que cambian su valor y devuelve
+// el diff. Un diff vacío significa que el PATCH no cambia nada. Si hay
+// cambios, now pasa a ser el updated_at del job.
+func (j *Job) Apply(in UpdateJobInput, now time.Time) map[string]JobChange {
+	changes := map[string]JobChange{}
+
+	if in.ScheduledAt != nil && (j.ScheduledAt == nil || !in.ScheduledAt.Equal(*j.ScheduledAt)) {
+		changes["scheduled_at"] = JobChange{From: j.ScheduledAt, To: *in.ScheduledAt}
+		j.ScheduledAt = in.ScheduledAt
+	}
+	if in.Priority != nil && *in.Priority != j.Priority {
+		changes["priority"] = JobChange{From: j.Priority, To: *in.Priority}
+		j.Priority = *in.Priority
+	}
+	if in.MaxRetries != nil && *in.MaxRetries != j.MaxRetries {
+		changes["max_retries"] = JobChange{From: j.MaxRetries, To: *in.MaxRetries}
+		j.MaxRetries = *in.MaxRetries
+	}
+	if in.Payload != nil && !jsonEqual(in.Payload, j.Payload) {
+		changes["payload"] = JobChange{From: j.Payload, To: in.Payload}
+		j.Payload = in.Payload
+	}
+	if in.CallbackURL != nil && *in.CallbackURL != j.CallbackURL {
+		changes["callback_url"] = JobChange{From: j.CallbackURL, To: *in.CallbackURL}
+		j.CallbackURL = *in.CallbackURL
+	}
+
+	if len(changes) > 0 {
+		j.UpdatedAt = now.Truncate(time.Microsecond)
+	}
+	return changes
+}
+
+// jsonEqual compara dos documentos JSON ignorando espacios.
+func jsonEqual(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return false
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+func NewJobSucceededEvent(jobID uuid.UUID, now time.Time) Event {
 	return Event{
 		ID:        uuid.New(),
 		JobID:     jobID,
 		Type:      EventJobSucceeded,
 		Message:   "job completed successfully",
-		CreatedAt: time.Now(),
+		CreatedAt: now,
 	}
 }
 
-func NewJobFailedEvent(jobID uuid.UUID, errMsg string) Event {
+func NewJobFailedEvent(jobID uuid.UUID, errMsg string, now time.Time) Event {
 	return Event{
 		ID:        uuid.New(),
 		JobID:     jobID,
 		Type:      EventJobFailed,
 		Message:   errMsg,
-		CreatedAt: time.Now(),
+		CreatedAt: now,
 	}
 }
 
-func NewJobDeadEvent(jobID uuid.UUID) Event {
+func NewJobDeadEvent(jobID uuid.UUID, now time.Time) Event {
 	return Event{
 		ID:        uuid.New(),
 		JobID:     jobID,
 		Type:      EventJobDead,
 		Message:   "job moved to dead letter queue",
-		CreatedAt: time.Now(),
+		CreatedAt: now,
+	}
+}
+
+func NewJobDeferredEvent(jobID uuid.UUID, reason string, runAt, now time.Time) Event {
+	metadata, _ := json.Marshal(map[string]any{
+		"reason": reason,
+		"run_at": runAt,
+	})
+
+	return Event{
+		ID:        uuid.New(),
+		JobID:     jobID,
+		Type:      EventJobDeferred,
+		Message:   "job deferred: " + reason,
+		Metadata:  metadata,
+		CreatedAt: now,
 	}
 }
 
-func NewAttempt(jobID uuid.UUID, attemptNumber int, status AttemptStatus, errMsg *string, httpStatus *int) Attempt {
+func NewJobUpdatedEvent(jobID uuid.UUID, changes map[string]JobChange, now time.Time) Event {
+	metadata, _ := json.Marshal(map[string]any{
+		"changes": changes,
+	})
+
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	return Event{
+		ID:        uuid.New(),
+		JobID:     jobID,
+		Type:      EventJobUpdated,
+		Message:   "job updated: " + strings.Join(fields, ", "),
+		Metadata:  metadata,
+		CreatedAt: now,
+	}
+}
+
+// NewAttempt registra un intento que corrió entre startedAt y finishedAt.
+func NewAttempt(jobID uuid.UUID, attemptNumber int, status AttemptStatus, errMsg *string, httpStatus *int, startedAt, finishedAt time.Time, workerID *string) Attempt {
+	durationMs := finishedAt.Sub(startedAt).Milliseconds()
+
 	return Attempt{
 		ID:            uuid.New(),
 		JobID:         jobID,
 		AttemptNumber: attemptNumber,
+		StartedAt:     startedAt,
+		FinishedAt:    &finishedAt,
+		DurationMs:    &durationMs,
 		Status:        status,
 		ErrorMessage:  errMsg,
 		HTTPStatus:    httpStatus,
-		CreatedAt:     time.Now(),
+		WorkerID:      workerID,
+		CreatedAt:     finishedAt,
 	}
 }
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// jobTransitions es la máquina de estados de un job: para cada estado, los
+// estados a los que puede pasar. Los estados sin salidas son terminales.
+//
+//	pending  -> queued (dispatcher) | disabled
+//	queued   -> running (worker) | pending (diferido por rate/concurrency limit)
+//	running  -> completed | failed (quedan reintentos) | dead | pending (circuito abierto)
+//	failed   -> running (reintento) | queued (reintento diferido, dispatcher) |
+//	            pending (reprogramado por PATCH) | dead | disabled
+var jobTransitions = map[JobStatus][]JobStatus{
+	JobStatusPending:   {JobStatusQueued, JobStatusDisabled},
+	JobStatusQueued:    {JobStatusRunning, JobStatusPending},
+	JobStatusRunning:   {JobStatusCompleted, JobStatusFailed, JobStatusDead, JobStatusPending},
+	JobStatusFailed:    {JobStatusRunning, JobStatusQueued, JobStatusPending, JobStatusDead, JobStatusDisabled},
+	JobStatusCompleted: {},
+	JobStatusDead:      {},
+	JobStatusDisabled:  {},
+}
+
+// CanTransitionTo reports whether a job in status s may move to status to.
+func (s JobStatus) CanTransitionTo(to JobStatus) bool {
+	for _, next := range jobTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal reports whether no transition leaves s.
+func (s JobStatus) IsTerminal() bool {
+	return len(jobTransitions[s]) == 0
+}
+
+// TransitionSources devuelve los estados desde los que se puede llegar a to;
+// los repositorios lo usan como precondición del UPDATE.
+func TransitionSources(to JobStatus) []JobStatus {
+	var sources []JobStatus
+	for _, from := range []JobStatus{
+		JobStatusPending,
+		JobStatusQueued,
+		JobStatusRunning,
+		JobStatusCompleted,
+		JobStatusFailed,
+		JobStatusDead,
+		JobStatusDisabled,
+	} {
+		if from.CanTransitionTo(to) {
+			sources = append(sources, from)
+		}
+	}
+	return sources
+}
+
+// TransitionError is returned when a job cannot move to a status because its
+// current status does not allow it (typically another worker or request got
+// there first). It matches ErrConflict.
+type TransitionError struct {
+	JobID uuid.UUID
+	From  JobStatus // estado real del job al intentar la transición
+	To    JobStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("job %s cannot transition from %s to %s", e.JobID, e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrConflict
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+var allStatuses = []JobStatus{
+	JobStatusPending,
+	JobStatusQueued,
+	JobStatusRunning,
+	JobStatusCompleted,
+	JobStatusFailed,
+	JobStatusDead,
+	JobStatusDisabled,
+}
+
+func TestCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from JobStatus
+		to   []JobStatus // todos los destinos permitidos
+	}{
+		{JobStatusPending, []JobStatus{JobStatusQueued, JobStatusDisabled}},
+		{JobStatusQueued, []JobStatus{JobStatusRunning, JobStatusPending}},
+		{JobStatusRunning, []JobStatus{JobStatusCompleted, JobStatusFailed, JobStatusDead, JobStatusPending}},
+		{JobStatusFailed, []JobStatus{JobStatusRunning, JobStatusQueued, JobStatusPending, JobStatusDead, JobStatusDisabled}},
+		{JobStatusCompleted, nil},
+		{JobStatusDead, nil},
+		{JobStatusDisabled, nil},
+	}
+
+	for _, tt := range tests {
+		for _, to := range allStatuses {
+			want := slices.Contains(tt.to, to)
+			if got := tt.from.CanTransitionTo(to); got != want {
+				t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", tt.from, to, got, want)
+			}
+		}
+		if got, want := tt.from.IsTerminal(), len(tt.to) == 0; got != want {
+			t.Errorf("%s.IsTerminal() = %v, want %v", tt.from, got, want)
+		}
+	}
+}
+
+func TestCanTransitionToUnknownStatus(t *testing.T) {
+	if JobStatus("archived").CanTransitionTo(JobStatusPending) {
+		t.Error("an unknown status should not transition")
+	}
+	if JobStatusPending.CanTransitionTo(JobStatus("archived")) {
+		t.Error("no status should transition to an unknown one")
+	}
+}
+
+func TestTransitionSources(t *testing.T) {
+	tests := []struct {
+		to   JobStatus
+		want []JobStatus
+	}{
+		{JobStatusPending, []JobStatus{JobStatusQueued, JobStatusRunning, JobStatusFailed}},
+		{JobStatusQueued, []JobStatus{JobStatusPending, JobStatusFailed}},
+		{JobStatusRunning, []JobStatus{JobStatusQueued, JobStatusFailed}},
+		{JobStatusCompleted, []JobStatus{JobStatusRunning}},
+		{JobStatusFailed, []JobStatus{JobStatusRunning}},
+		{JobStatusDead, []JobStatus{JobStatusRunning, JobStatusFailed}},
+		{JobStatusDisabled, []JobStatus{JobStatusPending, JobStatusFailed}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.to), func(t *testing.T) {
+			if got := TransitionSources(tt.to); !slices.Equal(got, tt.want) {
+				t.Errorf("TransitionSources(%s) = %v, want %v", tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransitionError(t *testing.T) {
+	err := error(&TransitionError{JobID: uuid.New(), From: JobStatusCompleted, To: JobStatusRunning})
+
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("TransitionError should match ErrConflict")
+	}
+	var transitionErr *TransitionError
+	if !errors.As(err, &transitionErr) || transitionErr.From != JobStatusCompleted {
+		t.Errorf("errors.As(%v) = %+v", err, transitionErr)
+	}
+}
@@ -8,18 +8,24 @@ import (
 
 type RabbitJobMessage struct {
 	JobID       uuid.UUID       `json:"job_id"`
+	Tenant      string          `json:"tenant"` // decide la cola de RabbitMQ
 	Type        string          `json:"type"`
 	CallbackURL string          `json:"callback_url"`
 	Payload     json.RawMessage `json:"payload"`
 	Attempt     int             `json:"attempt"`
+	// TraceContext viaja en los headers del mensaje, no en el body
+	TraceContext map[string]string `json:"-"`
 }
 
+// NewRabbitJobMessageFromJob arma el mensaje del próximo intento del job.
 func NewRabbitJobMessageFromJob(job Job) RabbitJobMessage {
 	return RabbitJobMessage{
-		JobID:       job.ID,
-		Type:        job.Type,
-		CallbackURL: job.CallbackURL,
-		Payload:     job.Payload,
-		Attempt:     1,
+		JobID:        job.ID,
+		Tenant:       job.Tenant,
+		Type:         job.Type,
+		CallbackURL:  job.CallbackURL,
+		Payload:      job.Payload,
+		Attempt:      job.Attempts + 1,
+		TraceContext: job.TraceContext,
 	}
 }
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"math"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type RateLimitScope string
+
+const (
+	RateLimitScopeType   RateLimitScope = "type"   // límite por tipo de job
+	RateLimitScopeHost   RateLimitScope = "host"   // límite por host del callback
+	RateLimitScopeTenant RateLimitScope = "tenant" // límite por tenant
+)
+
+// RateLimit is a token bucket shared by every worker through the database.
+type RateLimit struct {
+	Scope         RateLimitScope `db:"scope" json:"scope"`
+	Key           string         `db:"key" json:"key"`
+	RatePerSecond float64        `db:"rate_per_second" json:"rate_per_second"`
+	Burst         int            `db:"burst" json:"burst"`
+	Tokens        float64        `db:"tokens" json:"tokens"`
+	RefilledAt    time.Time      `db:"refilled_at" json:"refilled_at"`
+	CreatedAt     time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+// RateLimitKey identifies a single bucket.
+type RateLimitKey struct {
+	Scope RateLimitScope `json:"scope"`
+	Key   string         `json:"key"`
+}
+
+// RateLimitSearchParams defines the parameters for searching rate limits.
+type RateLimitSearchParams struct {
+	Scope *RateLimitScope
+	Key   *string
+}
+
+// RateLimitInput represents the input required to create or update a rate limit.
+type RateLimitInput struct {
+	Scope         RateLimitScope `json:"scope"`
+	Key           string         `json:"key"`
+	RatePerSecond float64        `json:"rate_per_second"`
+	Burst         int            `json:"burst"`
+}
+
+func NewRateLimit(input RateLimitInput, now time.Time) (*RateLimit, error) {
+	v := &ValidationError{}
+
+	if input.Scope != RateLimitScopeType && input.Scope != RateLimitScopeHost && input.Scope != RateLimitScopeTenant {
+		v.Add("scope", "invalid_value", "scope must be one of: type, host, tenant")
+	}
+	key := strings.TrimSpace(input.Key)
+	if key == "" {
+		v.Add("key", "required", "key is required")
+	}
+	if input.Scope == RateLimitScopeHost {
+		key = strings.ToLower(key)
+	}
+	if input.RatePerSecond <= 0 {
+		v.Add("rate_per_second", "out_of_range", "rate_per_second must be greater than zero")
+	}
+	if input.Burst < 1 {
+		v.Add("burst", "out_of_range", "burst must be at least 1")
+	}
+
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	return &RateLimit{
+		Scope:         input.Scope,
+		Key:           key,
+		RatePerSecond: input.RatePerSecond,
+		Burst:         input.Burst,
+		Tokens:        float64(input.Burst),
+		RefilledAt:    now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// Refill adds the tokens accumulated since the last refill, capped at Burst.
+func (l *RateLimit) Refill(now time.Time) {
+	elapsed := now.Sub(l.RefilledAt).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	l.Tokens = math.Min(float64(l.Burst), l.Tokens+elapsed*l.RatePerSecond)
+	l.RefilledAt = now
+}
+
+// Wait returns how long until the bucket holds a full token.
+func (l *RateLimit) Wait() time.Duration {
+	if l.Tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - l.Tokens) / l.RatePerSecond * float64(time.Second))
+}
+
+// TakeToken refills every bucket and consumes one token from each of them only
+// when all of them have one available. Otherwise nothing is consumed and the
+// longest wait is returned.
+func TakeToken(limits []RateLimit, now time.Time) time.Duration {
+	var wait time.Duration
+	for i := range limits {
+		limits[i].Refill(now)
+		if w := limits[i].Wait(); w > wait {
+			wait = w
+		}
+	}
+	if wait > 0 {
+		return wait
+	}
+
+	for i := range limits {
+		limits[i].Tokens--
+		limits[i].UpdatedAt = now
+	}
+	return 0
+}
+
+// RateLimitKeysFor returns the buckets that apply to a job of the given tenant, type and callback.
+func RateLimitKeysFor(tenant string, jobType string, callbackURL string) []RateLimitKey {
+	keys := []RateLimitKey{{Scope: RateLimitScopeType, Key: jobType}}
+	if tenant != "" {
+		keys = append(keys, RateLimitKey{Scope: RateLimitScopeTenant, Key: tenant})
+	}
+
+	if u, err := url.Parse(callbackURL); err == nil && u.Hostname() != "" {
+		keys = append(keys, RateLimitKey{
+			Scope: RateLimitScopeHost,
+			Key:   strings.ToLower(u.Hostname()),
+		})
+	}
+
+	return keys
+}
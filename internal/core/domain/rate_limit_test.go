@@ -0,0 +1,241 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+var rateLimitEpoch = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func bucket(rate float64, burst int, tokens float64) RateLimit {
+	return RateLimit{
+		Scope:         RateLimitScopeType,
+		Key:           "email",
+		RatePerSecond: rate,
+		Burst:         burst,
+		Tokens:        tokens,
+		RefilledAt:    rateLimitEpoch,
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestNewRateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   RateLimitInput
+		fields  []string // campos con error; vacío si es válido
+		wantKey string
+	}{
+		{"válido", RateLimitInput{Scope: RateLimitScopeType, Key: " email ", RatePerSecond: 2, Burst: 5}, nil, "email"},
+		{"host en minúsculas", RateLimitInput{Scope: RateLimitScopeHost, Key: "API.Example.com", RatePerSecond: 1, Burst: 1}, nil, "api.example.com"},
+		{"tenant conserva la key", RateLimitInput{Scope: RateLimitScopeTenant, Key: "Acme", RatePerSecond: 1, Burst: 1}, nil, "Acme"},
+		{"scope inválido", RateLimitInput{Scope: "queue", Key: "email", RatePerSecond: 1, Burst: 1}, []string{"scope"}, ""},
+		{"sin key", RateLimitInput{Scope: RateLimitScopeType, Key: "  ", RatePerSecond: 1, Burst: 1}, []string{"key"}, ""},
+		{"rate cero", RateLimitInput{Scope: RateLimitScopeType, Key: "email", RatePerSecond: 0, Burst: 1}, []string{"rate_per_second"}, ""},
+		{"burst cero", RateLimitInput{Scope: RateLimitScopeType, Key: "email", RatePerSecond: 1, Burst: 0}, []string{"burst"}, ""},
+		{"varios errores", RateLimitInput{Scope: "queue", RatePerSecond: -1}, []string{"scope", "key", "rate_per_second", "burst"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, err := NewRateLimit(tt.input, rateLimitEpoch)
+
+			if len(tt.fields) > 0 {
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("NewRateLimit() error = %v, want *ValidationError", err)
+				}
+				var got []string
+				for _, f := range validationErr.Fields {
+					got = append(got, f.Field)
+				}
+				if !slices.Equal(got, tt.fields) {
+					t.Fatalf("invalid fields = %v, want %v", got, tt.fields)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("NewRateLimit() error = %v", err)
+			}
+			if limit.Key != tt.wantKey {
+				t.Errorf("Key = %q, want %q", limit.Key, tt.wantKey)
+			}
+			// Un bucket nuevo arranca lleno
+			if limit.Tokens != float64(tt.input.Burst) || !limit.RefilledAt.Equal(rateLimitEpoch) {
+				t.Errorf("new bucket = %v tokens at %s, want %d at %s", limit.Tokens, limit.RefilledAt, tt.input.Burst, rateLimitEpoch)
+			}
+		})
+	}
+}
+
+func TestRefill(t *testing.T) {
+	tests := []struct {
+		name         string
+		limit        RateLimit
+		elapsed      time.Duration
+		wantTokens   float64
+		wantRefilled time.Duration // RefilledAt esperado, relativo a rateLimitEpoch
+	}{
+		{"suma rate por segundo", bucket(2, 10, 1), 1500 * time.Millisecond, 4, 1500 * time.Millisecond},
+		{"fracciones de token", bucket(0.5, 10, 0), time.Second, 0.5, time.Second},
+		{"no pasa de burst", bucket(2, 3, 2), time.Minute, 3, time.Minute},
+		{"sin tiempo transcurrido no cambia", bucket(2, 10, 1), 0, 1, 0},
+		{"un reloj atrasado no resta tokens", bucket(2, 10, 1), -time.Second, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := tt.limit
+			l.Refill(rateLimitEpoch.Add(tt.elapsed))
+
+			if !almostEqual(l.Tokens, tt.wantTokens) {
+				t.Errorf("Tokens = %v, want %v", l.Tokens, tt.wantTokens)
+			}
+			if want := rateLimitEpoch.Add(tt.wantRefilled); !l.RefilledAt.Equal(want) {
+				t.Errorf("RefilledAt = %s, want %s", l.RefilledAt, want)
+			}
+		})
+	}
+}
+
+func TestWait(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit RateLimit
+		want  time.Duration
+	}{
+		{"con un token no espera", bucket(1, 5, 1), 0},
+		{"con más de un token no espera", bucket(1, 5, 3.5), 0},
+		{"vacío espera un token entero", bucket(2, 5, 0), 500 * time.Millisecond},
+		{"espera lo que falta del token", bucket(4, 5, 0.5), 125 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limit.Wait(); got != tt.want {
+				t.Errorf("Wait() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTakeToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		limits     []RateLimit
+		elapsed    time.Duration
+		wantWait   time.Duration
+		wantTokens []float64
+	}{
+		{
+			name:       "todos con token: se consume uno de cada uno",
+			limits:     []RateLimit{bucket(1, 5, 2), bucket(1, 5, 1)},
+			wantTokens: []float64{1, 0},
+		},
+		{
+			name:       "uno vacío: no se consume ninguno",
+			limits:     []RateLimit{bucket(1, 5, 2), bucket(2, 5, 0)},
+			wantWait:   500 * time.Millisecond,
+			wantTokens: []float64{2, 0},
+		},
+		{
+			name:       "devuelve la espera más larga",
+			limits:     []RateLimit{bucket(4, 5, 0), bucket(1, 5, 0.5)},
+			wantWait:   500 * time.Millisecond,
+			wantTokens: []float64{0, 0.5},
+		},
+		{
+			name:       "el refill habilita el token",
+			limits:     []RateLimit{bucket(2, 5, 0), bucket(1, 5, 3)},
+			elapsed:    500 * time.Millisecond,
+			wantTokens: []float64{0, 2.5},
+		},
+		{
+			name:       "el refill no alcanza",
+			limits:     []RateLimit{bucket(1, 5, 0)},
+			elapsed:    250 * time.Millisecond,
+			wantWait:   750 * time.Millisecond,
+			wantTokens: []float64{0.25},
+		},
+		{
+			name:       "sin buckets no hay límite",
+			limits:     nil,
+			wantTokens: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := rateLimitEpoch.Add(tt.elapsed)
+			if got := TakeToken(tt.limits, now); got != tt.wantWait {
+				t.Fatalf("TakeToken() = %s, want %s", got, tt.wantWait)
+			}
+
+			for i, l := range tt.limits {
+				if !almostEqual(l.Tokens, tt.wantTokens[i]) {
+					t.Errorf("bucket %d: Tokens = %v, want %v", i, l.Tokens, tt.wantTokens[i])
+				}
+				// UpdatedAt solo cambia cuando se consume un token
+				if consumed := tt.wantWait == 0; consumed != l.UpdatedAt.Equal(now) {
+					t.Errorf("bucket %d: UpdatedAt = %s, consumed = %v", i, l.UpdatedAt, consumed)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitKeysFor(t *testing.T) {
+	tests := []struct {
+		name        string
+		tenant      string
+		jobType     string
+		callbackURL string
+		want        []RateLimitKey
+	}{
+		{
+			name:        "tipo, tenant y host",
+			tenant:      "acme",
+			jobType:     "email",
+			callbackURL: "https://API.Example.com:8443/hook",
+			want: []RateLimitKey{
+				{RateLimitScopeType, "email"},
+				{RateLimitScopeTenant, "acme"},
+				{RateLimitScopeHost, "api.example.com"},
+			},
+		},
+		{
+			name:        "sin tenant",
+			jobType:     "email",
+			callbackURL: "https://example.com/hook",
+			want: []RateLimitKey{
+				{RateLimitScopeType, "email"},
+				{RateLimitScopeHost, "example.com"},
+			},
+		},
+		{
+			name:        "url sin host",
+			tenant:      "acme",
+			jobType:     "email",
+			callbackURL: "/hook",
+			want: []RateLimitKey{
+				{RateLimitScopeType, "email"},
+				{RateLimitScopeTenant, "acme"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RateLimitKeysFor(tt.tenant, tt.jobType, tt.callbackURL); !slices.Equal(got, tt.want) {
+				t.Errorf("RateLimitKeysFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package domain
+
+import "time"
+
+// StatsWindows son las ventanas sobre las que se calculan throughput y tasa de fallos.
+var StatsWindows = []time.Duration{5 * time.Minute, time.Hour, 24 * time.Hour}
+
+// StatsDurationWindow es la ventana de intentos usada para los percentiles de duración.
+const StatsDurationWindow = time.Hour
+
+// JobCount is the number of jobs of a type in a status.
+type JobCount struct {
+	Type   string    `json:"type"`
+	Status JobStatus `json:"status"`
+	Count  int       `json:"count"`
+}
+
+// TypeStats groups the job counts of one type.
+type TypeStats struct {
+	Type     string            `json:"type"`
+	Total    int               `json:"total"`
+	ByStatus map[JobStatus]int `json:"by_status"`
+}
+
+// ThroughputStats summarizes the attempts finished within a window.
+type ThroughputStats struct {
+	Window          string    `json:"window"` // ej. "5m0s", "1h0m0s"
+	Succeeded       int       `json:"succeeded"`
+	Failed          int       `json:"failed"`
+	Dead            int       `json:"dead"`         // jobs que murieron en la ventana
+	PerMinute       float64   `json:"per_minute"`   // intentos exitosos por minuto
+	FailureRate     float64   `json:"failure_rate"` // intentos fallidos / intentos totales
+	WindowStartedAt time.Time `json:"window_started_at"`
+}
+
+// DurationStats holds execution duration percentiles of recent attempts.
+type DurationStats struct {
+	Window  string   `json:"window"`
+	Samples int      `json:"samples"`
+	P50Ms   *float64 `json:"p50_ms"`
+	P95Ms   *float64 `json:"p95_ms"`
+}
+
+// QueueDepth is the live state of the broker queue.
+type QueueDepth struct {
+	Name      string `json:"name"`
+	Messages  int    `json:"messages"`
+	Consumers int    `json:"consumers"`
+}
+
+// JobStats is the snapshot returned by GET /stats.
+type JobStats struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Total       int               `json:"total"`
+	ByStatus    map[JobStatus]int `json:"by_status"`
+	ByType      []TypeStats       `json:"by_type"`
+	// OldestPendingAgeSeconds es cuánto lleva esperando el job pendiente más
+	// atrasado desde su scheduled_at (retraso del dispatcher)
+	OldestPendingAgeSeconds *float64          `json:"oldest_pending_age_seconds"`
+	Throughput              []ThroughputStats `json:"throughput"`
+	Durations               DurationStats     `json:"durations"`
+	Queue                   *QueueDepth       `json:"queue"`
+	QueueError              *string           `json:"queue_error,omitempty"`
+}
+
+// NewJobStats agrupa los conteos por estado y por tipo.
+func NewJobStats(counts []JobCount, now time.Time) *JobStats {
+	stats := &JobStats{
+		GeneratedAt: now,
+		ByStatus:    map[JobStatus]int{},
+		ByType:      []TypeStats{},
+		Throughput:  []ThroughputStats{},
+	}
+
+	byType := map[string]int{}
+	for _, c := range counts {
+		stats.Total += c.Count
+		stats.ByStatus[c.Status] += c.Count
+
+		i, ok := byType[c.Type]
+		if !ok {
+			i = len(stats.ByType)
+			byType[c.Type] = i
+			stats.ByType = append(stats.ByType, TypeStats{Type: c.Type, ByStatus: map[JobStatus]int{}})
+		}
+		stats.ByType[i].Total += c.Count
+		stats.ByType[i].ByStatus[c.Status] += c.Count
+	}
+
+	return stats
+}
+
+// Finish completa las tasas derivadas de los conteos de la ventana.
+func (t *ThroughputStats) Finish(window time.Duration) {
+	t.Window = window.String()
+	if minutes := window.Minutes(); minutes > 0 {
+		t.PerMinute = float64(t.Succeeded) / minutes
+	}
+	if total := t.Succeeded + t.Failed; total > 0 {
+		t.FailureRate = float64(t.Failed) / float64(total)
+	}
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultTenant es el tenant de los jobs creados sin API key o por keys sin tenant.
+const DefaultTenant = "default"
+
+// tenantPattern mantiene los nombres aptos para colas de RabbitMQ y headers.
+var tenantPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// ValidTenant reports whether name can be used as a tenant.
+func ValidTenant(name string) bool {
+	return tenantPattern.MatchString(name)
+}
+
+// TenantQuotaStatuses son los estados que cuentan para la cuota de jobs pendientes.
+var TenantQuotaStatuses = []JobStatus{JobStatusPending, JobStatusQueued}
+
+// TenantQuota caps how many jobs a tenant can have waiting to run (pending or
+// queued) so one team cannot flood the shared deployment.
+type TenantQuota struct {
+	Tenant         string    `db:"tenant" json:"tenant"`
+	MaxPendingJobs int       `db:"max_pending_jobs" json:"max_pending_jobs"`
+	Pending        int       `db:"pending" json:"pending"` // jobs pending/queued al momento de la consulta
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TenantQuotaSearchParams defines the parameters for searching tenant quotas.
+type TenantQuotaSearchParams struct {
+	Tenant *string
+}
+
+// TenantQuotaInput represents the input required to create or update a quota.
+type TenantQuotaInput struct {
+	Tenant         string `json:"tenant"`
+	MaxPendingJobs int    `json:"max_pending_jobs"`
+}
+
+func NewTenantQuota(input TenantQuotaInput, now time.Time) (*TenantQuota, error) {
+	v := &ValidationError{}
+
+	tenant := strings.TrimSpace(input.Tenant)
+	if tenant == "" {
+		v.Add("tenant", "required", "tenant is required")
+	} else if !ValidTenant(tenant) {
+		v.Add("tenant", "invalid_value", "tenant must be lowercase letters, digits, '-' or '_' (max 63)")
+	}
+	if input.MaxPendingJobs < 1 {
+		v.Add("max_pending_jobs", "out_of_range", "max_pending_jobs must be at least 1")
+	}
+
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	return &TenantQuota{
+		Tenant:         tenant,
+		MaxPendingJobs: input.MaxPendingJobs,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
@@ -0,0 +1,265 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"job_scheduler_go_rabbitmq/utils"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultWebhookMaxAttempts es la cantidad de envíos de una entrega antes de darla por muerta.
+	DefaultWebhookMaxAttempts = 8
+	// WebhookDeliveryLease es cuánto queda reservada una entrega mientras se envía.
+	WebhookDeliveryLease = time.Minute
+
+	webhookBaseDelay = 10 * time.Second
+	webhookMaxDelay  = time.Hour
+)
+
+// DefaultWebhookEvents son los eventos notificados cuando la suscripción no indica ninguno.
+var DefaultWebhookEvents = []EventType{EventJobSucceeded, EventJobFailed, EventJobDead}
+
+// WebhookSubscription registers a URL to be notified about job events. A
+// subscription is either bound to one job (JobID) or global, optionally
+// filtered by job type. Empty EventTypes means every event.
+type WebhookSubscription struct {
+	ID          uuid.UUID   `db:"id" json:"id"`
+	Tenant      string      `db:"tenant" json:"tenant"` // solo recibe eventos de jobs de su tenant
+	JobID       *uuid.UUID  `db:"job_id" json:"job_id,omitempty"`
+	JobType     *string     `db:"job_type" json:"job_type,omitempty"`
+	EventTypes  []EventType `db:"event_types" json:"event_types"`
+	URL         string      `db:"url" json:"url"`
+	Secret      string      `db:"secret" json:"-"` // clave HMAC con la que se firman las entregas
+	MaxAttempts int         `db:"max_attempts" json:"max_attempts"`
+	Active      bool        `db:"active" json:"active"`
+	CreatedAt   time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time   `db:"updated_at" json:"updated_at"`
+}
+
+// CreatedWebhookSubscription is the response of subscription creation, the
+// only time the signing secret is visible.
+type CreatedWebhookSubscription struct {
+	WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// WebhookSubscriptionSearchParams defines the parameters for searching subscriptions.
+type WebhookSubscriptionSearchParams struct {
+	ID      *uuid.UUID
+	Tenant  *string
+	JobID   *uuid.UUID
+	JobType *string
+}
+
+// WebhookSubscriptionInput represents the input required to create a subscription.
+type WebhookSubscriptionInput struct {
+	// Tenant lo completa el servicio con el tenant del request
+	Tenant      string      `json:"-"`
+	URL         string      `json:"url"`
+	JobID       *uuid.UUID  `json:"job_id"`
+	JobType     *string     `json:"job_type"`
+	EventTypes  []EventType `json:"event_types"`
+	Secret      string      `json:"secret"`
+	MaxAttempts int         `json:"max_attempts"`
+}
+
+func NewWebhookSubscription(input WebhookSubscriptionInput, now time.Time) (*WebhookSubscription, error) {
+	v := &ValidationError{}
+
+	if strings.TrimSpace(input.URL) == "" {
+		v.Add("url", "required", "url is required")
+	} else if u, err := url.Parse(input.URL); err != nil || !u.IsAbs() || u.Host == "" {
+		v.Add("url", "invalid_url", "url must be an absolute URL")
+	}
+
+	if input.JobID != nil && input.JobType != nil {
+		v.Add("job_type", "conflict", "job_type cannot be combined with job_id")
+	}
+	if input.JobType != nil && strings.TrimSpace(*input.JobType) == "" {
+		v.Add("job_type", "required", "job_type cannot be empty")
+	}
+
+	for _, t := range input.EventTypes {
+		if !t.IsValid() {
+			v.Add("event_types", "invalid_value", "unknown event type "+strconv.Quote(string(t)))
+		}
+	}
+
+	if input.MaxAttempts < 0 {
+		v.Add("max_attempts", "out_of_range", "max_attempts must be zero or greater")
+	}
+
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	eventTypes := input.EventTypes
+	if eventTypes == nil {
+		eventTypes = []EventType{}
+	}
+
+	secret := input.Secret
+	if secret == "" {
+		secret = newWebhookSecret()
+	}
+
+	tenant := input.Tenant
+	if tenant == "" {
+		tenant = DefaultTenant
+	}
+
+	maxAttempts := input.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = DefaultWebhookMaxAttempts
+	}
+
+	return &WebhookSubscription{
+		ID:          uuid.New(),
+		Tenant:      tenant,
+		JobID:       input.JobID,
+		JobType:     input.JobType,
+		EventTypes:  eventTypes,
+		URL:         input.URL,
+		Secret:      secret,
+		MaxAttempts: maxAttempts,
+		Active:      true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryDead      WebhookDeliveryStatus = "dead"
+)
+
+// IsValid reports whether s is a known delivery status.
+func (s WebhookDeliveryStatus) IsValid() bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliveryDelivered, WebhookDeliveryDead:
+		return true
+	}
+	return false
+}
+
+// WebhookDelivery is one event sent (or to be sent) to one subscription. It
+// keeps its own attempt counter, independent from the job's retries.
+type WebhookDelivery struct {
+	ID             uuid.UUID             `db:"id" json:"id"`
+	SubscriptionID uuid.UUID             `db:"subscription_id" json:"subscription_id"`
+	EventID        uuid.UUID             `db:"event_id" json:"event_id"`
+	JobID          uuid.UUID             `db:"job_id" json:"job_id"`
+	EventType      EventType             `db:"event_type" json:"event_type"`
+	URL            string                `db:"url" json:"url"`
+	Status         WebhookDeliveryStatus `db:"status" json:"status"`
+	Attempts       int                   `db:"attempts" json:"attempts"`
+	MaxAttempts    int                   `db:"max_attempts" json:"max_attempts"`
+	NextAttemptAt  *time.Time            `db:"next_attempt_at" json:"next_attempt_at,omitempty"`
+	LastHTTPStatus *int                  `db:"last_http_status" json:"last_http_status,omitempty"`
+	LastError      *string               `db:"last_error" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time            `db:"delivered_at" json:"delivered_at,omitempty"`
+	CreatedAt      time.Time             `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time             `db:"updated_at" json:"updated_at"`
+}
+
+// WebhookDeliverySortFields are the fields deliveries can be sorted and paginated by.
+var WebhookDeliverySortFields = []string{"created_at", "updated_at"}
+
+// SortValue returns the value of a sort field as used in pagination cursors.
+func (d WebhookDelivery) SortValue(field string) string {
+	if field == "updated_at" {
+		return d.UpdatedAt.Format(time.RFC3339Nano)
+	}
+	return d.CreatedAt.Format(time.RFC3339Nano)
+}
+
+// WebhookDeliverySearchParams defines the parameters for searching deliveries.
+type WebhookDeliverySearchParams struct {
+	ID             *uuid.UUID
+	Tenant         *string // tenant de la suscripción
+	SubscriptionID *uuid.UUID
+	JobID          *uuid.UUID
+	EventType      *EventType
+	Status         *WebhookDeliveryStatus
+	utils.SearchParams
+}
+
+// PendingWebhook is a claimed delivery together with what is needed to send it.
+type PendingWebhook struct {
+	Delivery WebhookDelivery
+	Event    Event
+	Secret   string
+}
+
+// RecordResult actualiza la entrega con el resultado de un envío y programa
+// el siguiente intento con backoff exponencial, o la marca como muerta.
+func (d *WebhookDelivery) RecordResult(result ExecutionResult, now time.Time) {
+	d.Attempts++
+	d.UpdatedAt = now
+	if result.HTTPStatus != 0 {
+		status := result.HTTPStatus
+		d.LastHTTPStatus = &status
+	} else {
+		d.LastHTTPStatus = nil
+	}
+
+	if result.Error == nil {
+		d.Status = WebhookDeliveryDelivered
+		d.LastError = nil
+		d.NextAttemptAt = nil
+		d.DeliveredAt = &now
+		return
+	}
+
+	errMsg := result.Error.Error()
+	d.LastError = &errMsg
+
+	if d.Attempts >= d.MaxAttempts {
+		d.Status = WebhookDeliveryDead
+		d.NextAttemptAt = nil
+		return
+	}
+
+	next := now.Add(WebhookRetryDelay(d.Attempts))
+	d.Status = WebhookDeliveryPending
+	d.NextAttemptAt = &next
+}
+
+// WebhookRetryDelay devuelve la espera antes del siguiente envío tras attempts envíos fallidos.
+func WebhookRetryDelay(attempts int) time.Duration {
+	delay := webhookBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookMaxDelay {
+			return webhookMaxDelay
+		}
+	}
+	return delay
+}
+
+// SignWebhook firma el body de una entrega: HMAC-SHA256 de "<timestamp>.<body>"
+// con el secreto de la suscripción. El receptor recalcula la firma y compara.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return "whsec_" + hex.EncodeToString(b)
+}
@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type IAPIKeyHandler interface {
+	RegisterRouter(router *mux.Router)
+	Create() http.HandlerFunc
+	List() http.HandlerFunc
+	Revoke() http.HandlerFunc
+}
+
+type IAPIKeyService interface {
+	Create(ctx context.Context, input domain.APIKeyInput) (*domain.CreatedAPIKey, error)
+	List(ctx context.Context) ([]domain.APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	// Authenticate resuelve el token de un request; devuelve domain.ErrUnauthorized
+	// si no existe o fue revocado.
+	Authenticate(ctx context.Context, token string) (*domain.APIKey, error)
+}
+
+type IAPIKeyRepository interface {
+	Insert(ctx context.Context, key domain.APIKey) error
+	Get(ctx context.Context, params domain.APIKeySearchParams) ([]domain.APIKey, error)
+	GetOne(ctx context.Context, params domain.APIKeySearchParams) (*domain.APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time) error
+	// TouchLastUsed actualiza last_used_at como mucho una vez por minuto por key.
+	TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+}
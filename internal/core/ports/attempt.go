@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/utils"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type IAttemptHandler interface {
+	RegisterRouter(router *mux.Router)
+	List() http.HandlerFunc
+	ListByJob() http.HandlerFunc
+}
+
+type IAttemptService interface {
+	List(ctx context.Context, params domain.AttemptSearchParams) (*utils.PaginatorWrapper[[]domain.Attempt], error)
+	ListByJob(ctx context.Context, jobID uuid.UUID) ([]domain.Attempt, error)
+}
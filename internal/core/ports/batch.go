@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type IBatchHandler interface {
+	RegisterRouter(router *mux.Router)
+	GetOne() http.HandlerFunc
+}
+
+type IBatchService interface {
+	GetStatus(ctx context.Context, params domain.JobBatchSearchParams) (*domain.BatchStatus, error)
+}
+
+type IJobBatchRepository interface {
+	Insert(ctx context.Context, batch domain.JobBatch) error
+	GetOne(ctx context.Context, params domain.JobBatchSearchParams) (*domain.JobBatch, error)
+	// CountJobs devuelve los jobs del lote por estado.
+	CountJobs(ctx context.Context, batchID uuid.UUID) (map[domain.JobStatus]int, error)
+
+	// AddCreated suma n jobs insertados, en la transacción que los inserta.
+	AddCreated(ctx context.Context, batchID uuid.UUID, n int) error
+	// Seal guarda los totales del lote al terminar de recibir items y devuelve
+	// el lote actualizado.
+	Seal(ctx context.Context, batch domain.JobBatch) (*domain.JobBatch, error)
+	// RecordResult suma un miembro terminado (succeeded o failed) y devuelve el
+	// lote actualizado. El UPDATE bloquea la fila hasta el fin de la transacción.
+	RecordResult(ctx context.Context, batchID uuid.UUID, succeeded bool) (*domain.JobBatch, error)
+	// MarkCompleted marca el lote como terminado y devuelve false si ya lo
+	// estaba, para que el callback se encole una sola vez.
+	MarkCompleted(ctx context.Context, batchID uuid.UUID, callbackJobID *uuid.UUID, at time.Time) (bool, error)
+}
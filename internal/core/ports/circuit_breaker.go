@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type ICircuitBreakerHandler interface {
+	RegisterRouter(router *mux.Router)
+	List() http.HandlerFunc
+}
+
+type ICircuitBreakerService interface {
+	List(ctx context.Context, params domain.CircuitBreakerSearchParams) ([]domain.CircuitBreaker, error)
+}
+
+// los workers publican el estado de sus breakers para que el server lo exponga
+type ICircuitBreakerRepository interface {
+	Upsert(ctx context.Context, breaker domain.CircuitBreaker) error
+	Get(ctx context.Context, params domain.CircuitBreakerSearchParams) ([]domain.CircuitBreaker, error)
+}
@@ -0,0 +1,9 @@
+package ports
+
+import "time"
+
+// IClock da la hora a la lógica de scheduling. Se inyecta en lugar de llamar
+// a time.Now() para que los tests puedan fijarla y adelantarla.
+type IClock interface {
+	Now() time.Time
+}
@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type IConcurrencyLimitHandler interface {
+	RegisterRouter(router *mux.Router)
+	List() http.HandlerFunc
+	Put() http.HandlerFunc
+	Delete() http.HandlerFunc
+}
+
+type IConcurrencyLimitService interface {
+	List(ctx context.Context, params domain.ConcurrencyLimitSearchParams) ([]domain.ConcurrencyLimit, error)
+	Put(ctx context.Context, input domain.ConcurrencyLimitInput) (*domain.ConcurrencyLimit, error)
+	Delete(ctx context.Context, jobType string) error
+}
+
+type IConcurrencyLimitRepository interface {
+	Upsert(ctx context.Context, limit domain.ConcurrencyLimit) error
+	Get(ctx context.Context, params domain.ConcurrencyLimitSearchParams) ([]domain.ConcurrencyLimit, error)
+	Delete(ctx context.Context, jobType string) error
+
+	// AcquireSlot reserva un slot del semáforo del tipo para el job. Devuelve
+	// false si el tipo ya alcanzó su límite. Debe ejecutarse dentro de una transacción.
+	AcquireSlot(ctx context.Context, jobType string, jobID uuid.UUID) (bool, error)
+	ReleaseSlot(ctx context.Context, jobID uuid.UUID) error
+}
@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type IEventStreamHandler interface {
+	RegisterRouter(router *mux.Router)
+	Stream() http.HandlerFunc
+	StreamJob() http.HandlerFunc
+}
+
+type IEventStreamService interface {
+	// Stream devuelve los eventos que cumplen el filtro a medida que se insertan.
+	// Si afterSeq no es nil, primero se envían los eventos posteriores a ese seq.
+	// El canal se cierra cuando ctx termina o el subscriber queda atrasado.
+	Stream(ctx context.Context, filter domain.EventStreamFilter, afterSeq *int64) (<-chan domain.StreamEvent, error)
+}
+
+// IEventBroker reparte en memoria los eventos insertados entre los subscribers del proceso.
+type IEventBroker interface {
+	Subscribe(filter domain.EventStreamFilter) (<-chan domain.StreamEvent, func())
+}
@@ -2,8 +2,11 @@ package ports
 
 import (
 	"context"
+	"iter"
 	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/utils"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -12,35 +15,63 @@ import (
 type IJobHandler interface {
 	RegisterRouter(router *mux.Router)
 	Create() http.HandlerFunc
+	CreateBatch() http.HandlerFunc
+	List() http.HandlerFunc
 	GetOne() http.HandlerFunc
+	Update() http.HandlerFunc
 	GetTimeline() http.HandlerFunc
 }
 
 type IJobService interface {
-	Create(ctx context.Context, input domain.CreateJobInput) (*domain.Job, error)
+	Create(ctx context.Context, input domain.CreateJobInput) (*domain.CreatedJob, error)
+	// CreateBatch crea los jobs de items en chunks. Los errores de cada item
+	// (JSON inválido, validación) se informan en el resultado, no como error.
+	CreateBatch(ctx context.Context, items iter.Seq2[domain.CreateJobInput, error], opts domain.BulkCreateOptions) (*domain.BulkCreateResult, error)
 	GetOne(ctx context.Context, params domain.JobSearchParams) (*domain.Job, error)
+	// Update edita un job pending o failed si su versión sigue siendo la que indica el input.
+	Update(ctx context.Context, jobID uuid.UUID, input domain.UpdateJobInput) (*domain.Job, error)
+	List(ctx context.Context, params domain.JobSearchParams) (*utils.PaginatorWrapper[[]domain.Job], error)
 	GetTimeline(ctx context.Context, jobID uuid.UUID) ([]domain.Event, error)
 }
 
 type IJobExecutionService interface {
 	ProcessJobMessage(ctx context.Context, msg domain.RabbitJobMessage) error
+	QueuedTenants(ctx context.Context) ([]string, error)
 }
 
 type IJobRepository interface {
 	// Base
 	Insert(ctx context.Context, job domain.Job) error
+	InsertMany(ctx context.Context, jobs []domain.Job) error
+	// Update guarda los campos editables si el job sigue editable y con updated_at = prevUpdatedAt.
+	Update(ctx context.Context, job domain.Job, prevUpdatedAt time.Time) error
 	GetOne(ctx context.Context, params domain.JobSearchParams) (*domain.Job, error)
 	Get(ctx context.Context, params domain.JobSearchParams) ([]domain.Job, error)
+	Count(ctx context.Context, params domain.JobSearchParams) (int, error)
 
-	// Dispatcher
-	LockJob(ctx context.Context, jobID uuid.UUID, lockedBy string) error
+	// Los cambios de estado siguen la máquina de estados de domain.JobStatus y
+	// devuelven *domain.TransitionError si el estado actual no los permite.
+
+	// Dispatcher. LockJob toma el lock de un job pending (o failed con el
+	// reintento diferido) libre o cuyo lock tiene más de lockTimeout (el
+	// dispatcher que lo tomó se cayó)
+	LockJob(ctx context.Context, jobID uuid.UUID, lockedBy string, lockTimeout time.Duration) error
 	MarkQueued(ctx context.Context, jobID uuid.UUID) error
 
-	// Worker
+	// Worker. MarkCompleted, MarkFailed y MarkDead cierran un intento y suman
+	// uno a Job.Attempts
 	MarkRunning(ctx context.Context, jobID uuid.UUID) error
 	MarkCompleted(ctx context.Context, jobID uuid.UUID) error
 	MarkFailed(ctx context.Context, jobID uuid.UUID, errMsg string, httpStatus *int) error
 	MarkDead(ctx context.Context, jobID uuid.UUID, reason string) error
+	Reschedule(ctx context.Context, jobID uuid.UUID, runAt time.Time) error
+	// DeferRetry deja un job failed en failed, con el reintento diferido hasta
+	// runAt. Devuelve *domain.TransitionError si el job no está failed
+	DeferRetry(ctx context.Context, jobID uuid.UUID, runAt time.Time) error
+	// QueuedTenants devuelve los tenants con mensajes en su cola: jobs
+	// encolados o failed con el reintento ya publicado. El worker consume sus
+	// colas; tras un reinicio una cola con solo reintentos también se retoma
+	QueuedTenants(ctx context.Context) ([]string, error)
 }
 
 // intento de ejecutar un job
@@ -55,6 +86,7 @@ type IAttemptRepository interface {
 type IEventRepository interface {
 	Insert(ctx context.Context, event domain.Event) error
 	Get(ctx context.Context, params domain.EventSearchParams) ([]domain.Event, error)
+	GetStream(ctx context.Context, params domain.EventSearchParams) ([]domain.StreamEvent, error)
 }
 
 type IJobExecutor interface {
@@ -0,0 +1,10 @@
+package ports
+
+import "job_scheduler_go_rabbitmq/internal/core/domain"
+
+// IJobMetrics recibe los hechos del ciclo de vida de los jobs que se exponen como métricas.
+type IJobMetrics interface {
+	JobCreated(jobType string)
+	// JobFinished se llama con completed, failed (se reintentará) o dead.
+	JobFinished(jobType string, status domain.JobStatus)
+}
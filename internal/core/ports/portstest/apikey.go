@@ -0,0 +1,127 @@
+package portstest
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TestAPIKeyRepository checks lookup by hash, revocation, the last_used_at
+// throttling and that tenant keys are hidden from other tenants.
+func TestAPIKeyRepository(t *testing.T, newUoW UnitOfWorkFactory) {
+	ctx := context.Background()
+
+	newKey := func(t *testing.T, tenant string) domain.APIKey {
+		t.Helper()
+		created, err := domain.NewAPIKey(domain.APIKeyInput{
+			Name:   "key-" + tenant,
+			Tenant: tenant,
+			Scopes: []domain.APIKeyScope{domain.APIKeyScopeRead},
+		}, at(0))
+		if err != nil {
+			t.Fatalf("new api key: %v", err)
+		}
+		return created.APIKey
+	}
+
+	t.Run("InsertAndGetOne", func(t *testing.T) {
+		uow := newUoW(t)
+		key := newKey(t, "acme")
+		if err := uow.APIKey().Insert(ctx, key); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		expectErr(t, uow.APIKey().Insert(ctx, key), domain.ErrConflict, "insert duplicate")
+
+		got, err := uow.APIKey().GetOne(ctx, domain.APIKeySearchParams{Hash: &key.Hash})
+		if err != nil {
+			t.Fatalf("get by hash: %v", err)
+		}
+		if got.ID != key.ID || got.Tenant != "acme" || len(got.Scopes) != 1 || got.Scopes[0] != domain.APIKeyScopeRead {
+			t.Fatalf("got %+v, want %+v", got, key)
+		}
+
+		_, err = uow.APIKey().GetOne(ctx, domain.APIKeySearchParams{Hash: ptr(domain.HashAPIKey("unknown"))})
+		expectErr(t, err, domain.ErrNotFound, "get unknown hash")
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		uow := newUoW(t)
+		key := newKey(t, "acme")
+		if err := uow.APIKey().Insert(ctx, key); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+
+		if err := uow.APIKey().Revoke(ctx, key.ID, at(1)); err != nil {
+			t.Fatalf("revoke: %v", err)
+		}
+		expectErr(t, uow.APIKey().Revoke(ctx, key.ID, at(2)), domain.ErrNotFound, "revoke twice")
+		expectErr(t, uow.APIKey().Revoke(ctx, uuid.New(), at(2)), domain.ErrNotFound, "revoke missing")
+
+		// Una key revocada deja de autenticar pero sigue en el historial
+		_, err := uow.APIKey().GetOne(ctx, domain.APIKeySearchParams{Hash: &key.Hash})
+		expectErr(t, err, domain.ErrNotFound, "get revoked key")
+		got, err := uow.APIKey().GetOne(ctx, domain.APIKeySearchParams{ID: &key.ID, IncludeRevoked: true})
+		if err != nil || got.RevokedAt == nil || !got.RevokedAt.Equal(at(1)) {
+			t.Fatalf("get revoked with IncludeRevoked: got %+v (%v)", got, err)
+		}
+	})
+
+	t.Run("TouchLastUsed", func(t *testing.T) {
+		uow := newUoW(t)
+		key := newKey(t, "acme")
+		if err := uow.APIKey().Insert(ctx, key); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		lastUsed := func(t *testing.T) *time.Time {
+			t.Helper()
+			got, err := uow.APIKey().GetOne(ctx, domain.APIKeySearchParams{ID: &key.ID})
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			return got.LastUsedAt
+		}
+
+		steps := []struct {
+			touch time.Time
+			want  time.Time
+		}{
+			{at(0), at(0)},
+			{at(0).Add(30 * time.Second), at(0)}, // dentro del minuto: no se escribe
+			{at(2), at(2)},
+		}
+		for _, s := range steps {
+			if err := uow.APIKey().TouchLastUsed(ctx, key.ID, s.touch); err != nil {
+				t.Fatalf("touch: %v", err)
+			}
+			if got := lastUsed(t); got == nil || !got.Equal(s.want) {
+				t.Fatalf("touch at %v: last_used_at %v, want %v", s.touch, got, s.want)
+			}
+		}
+	})
+
+	t.Run("TenantScope", func(t *testing.T) {
+		uow := newUoW(t)
+		acme, globex, operator := newKey(t, "acme"), newKey(t, "globex"), newKey(t, "")
+		for _, k := range []domain.APIKey{acme, globex, operator} {
+			if err := uow.APIKey().Insert(ctx, k); err != nil {
+				t.Fatalf("insert: %v", err)
+			}
+		}
+
+		all, err := uow.APIKey().Get(ctx, domain.APIKeySearchParams{})
+		if err != nil || len(all) != 3 {
+			t.Fatalf("get all: got %d (%v), want 3", len(all), err)
+		}
+		byParam, err := uow.APIKey().Get(ctx, domain.APIKeySearchParams{Tenant: ptr("globex")})
+		if err != nil || len(byParam) != 1 || byParam[0].ID != globex.ID {
+			t.Fatalf("get by tenant: got %+v (%v), want only globex", byParam, err)
+		}
+		scoped, err := uow.APIKey().Get(tenantCtx("acme"), domain.APIKeySearchParams{})
+		if err != nil || len(scoped) != 1 || scoped[0].ID != acme.ID {
+			t.Fatalf("scoped get: got %+v (%v), want only acme", scoped, err)
+		}
+	})
+}
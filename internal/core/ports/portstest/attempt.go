@@ -0,0 +1,238 @@
+package portstest
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/utils"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// newAttempt arma un intento terminado del job, empezado en at(start).
+func newAttempt(jobID uuid.UUID, number int, start int, mods ...func(*domain.Attempt)) domain.Attempt {
+	started := at(start)
+	finished := started.Add(250 * time.Millisecond)
+	a := domain.Attempt{
+		ID:            uuid.New(),
+		JobID:         jobID,
+		AttemptNumber: number,
+		StartedAt:     started,
+		FinishedAt:    &finished,
+		DurationMs:    ptr(int64(250)),
+		Status:        domain.AttemptStatusSuccess,
+		HTTPStatus:    ptr(200),
+		WorkerID:      ptr("worker-1"),
+		CreatedAt:     finished,
+	}
+	for _, mod := range mods {
+		mod(&a)
+	}
+	return a
+}
+
+func insertAttempts(t *testing.T, uow ports.IUnitOfWork, attempts ...domain.Attempt) {
+	t.Helper()
+	atomic(t, uow, func(tx ports.IUnitOfWork) error {
+		for _, a := range attempts {
+			if err := tx.Attempt().Insert(context.Background(), a); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func attemptIDs(attempts []domain.Attempt) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(attempts))
+	for _, a := range attempts {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+// TestAttemptRepository checks that attempts take the tenant of their job and
+// the search filters, ordering and pagination of attempts.
+func TestAttemptRepository(t *testing.T, newUoW UnitOfWorkFactory) {
+	ctx := context.Background()
+
+	t.Run("InsertTakesJobTenant", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob(func(j *domain.Job) { j.Tenant = "acme" })
+		insertJobs(t, uow, job)
+		attempt := newAttempt(job.ID, 1, 0)
+		insertAttempts(t, uow, attempt)
+
+		got, err := uow.Attempt().Get(ctx, domain.AttemptSearchParams{ID: &attempt.ID})
+		if err != nil || len(got) != 1 {
+			t.Fatalf("get: got %d (%v), want 1", len(got), err)
+		}
+		if got[0].Tenant != "acme" || got[0].JobID != job.ID || got[0].AttemptNumber != 1 {
+			t.Fatalf("got %+v", got[0])
+		}
+
+		err = uow.Atomic(ctx, func(tx ports.IUnitOfWork) error {
+			return tx.Attempt().Insert(ctx, newAttempt(uuid.New(), 1, 0))
+		})
+		if err == nil {
+			t.Fatal("attempt of a missing job was inserted")
+		}
+	})
+
+	t.Run("Mark", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob()
+		insertJobs(t, uow, job)
+		ok := newAttempt(job.ID, 1, 0, func(a *domain.Attempt) { a.Status = domain.AttemptStatusFailed })
+		failed := newAttempt(job.ID, 2, 1)
+		insertAttempts(t, uow, ok, failed)
+
+		atomic(t, uow, func(tx ports.IUnitOfWork) error {
+			if err := tx.Attempt().MarkSuccess(ctx, ok.ID); err != nil {
+				return err
+			}
+			return tx.Attempt().MarkFailed(ctx, failed.ID, "timeout", ptr(504))
+		})
+
+		got, err := uow.Attempt().Get(ctx, domain.AttemptSearchParams{JobID: &job.ID})
+		if err != nil || len(got) != 2 {
+			t.Fatalf("get: got %d (%v), want 2", len(got), err)
+		}
+		for _, a := range got {
+			switch a.ID {
+			case ok.ID:
+				if a.Status != domain.AttemptStatusSuccess {
+					t.Fatalf("mark success: got %s", a.Status)
+				}
+			case failed.ID:
+				if a.Status != domain.AttemptStatusFailed || a.ErrorMessage == nil || *a.ErrorMessage != "timeout" ||
+					a.HTTPStatus == nil || *a.HTTPStatus != 504 {
+					t.Fatalf("mark failed: got %+v", a)
+				}
+			}
+		}
+	})
+
+	t.Run("SearchFilters", func(t *testing.T) {
+		uow := newUoW(t)
+		email := newJob()
+		report := newJob(func(j *domain.Job) { j.Type = "report"; j.Tenant = "acme" })
+		insertJobs(t, uow, email, report)
+
+		success := newAttempt(email.ID, 1, 0)
+		timeout := newAttempt(email.ID, 2, 10, func(a *domain.Attempt) {
+			a.Status = domain.AttemptStatusFailed
+			a.HTTPStatus = ptr(504)
+			a.ErrorMessage = ptr("Gateway Timeout")
+			a.WorkerID = ptr("worker-2")
+		})
+		notFound := newAttempt(report.ID, 1, 20, func(a *domain.Attempt) {
+			a.Status = domain.AttemptStatusFailed
+			a.HTTPStatus = ptr(404)
+			a.ErrorMessage = ptr("not found")
+		})
+		insertAttempts(t, uow, success, timeout, notFound)
+
+		cases := []struct {
+			name   string
+			params domain.AttemptSearchParams
+			want   []domain.Attempt
+		}{
+			{"All", domain.AttemptSearchParams{}, []domain.Attempt{success, timeout, notFound}},
+			{"ID", domain.AttemptSearchParams{ID: &timeout.ID}, []domain.Attempt{timeout}},
+			{"Tenant", domain.AttemptSearchParams{Tenant: ptr("acme")}, []domain.Attempt{notFound}},
+			{"JobID", domain.AttemptSearchParams{JobID: &email.ID}, []domain.Attempt{success, timeout}},
+			{"JobType", domain.AttemptSearchParams{JobType: ptr("report")}, []domain.Attempt{notFound}},
+			{"Status", domain.AttemptSearchParams{Status: ptr(domain.AttemptStatusFailed)}, []domain.Attempt{timeout, notFound}},
+			{"HTTPStatus", domain.AttemptSearchParams{HTTPStatus: ptr(404)}, []domain.Attempt{notFound}},
+			{"HTTPStatusClass", domain.AttemptSearchParams{HTTPStatusClass: ptr(5)}, []domain.Attempt{timeout}},
+			{"WorkerID", domain.AttemptSearchParams{WorkerID: ptr("worker-2")}, []domain.Attempt{timeout}},
+			{"StartedRange", domain.AttemptSearchParams{StartedFrom: ptr(at(10)), StartedTo: ptr(at(20))}, []domain.Attempt{timeout}},
+			{"QMatchesError", domain.AttemptSearchParams{SearchParams: utils.SearchParams{Q: ptr("timeout")}}, []domain.Attempt{timeout}},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				got, err := uow.Attempt().Get(ctx, c.params)
+				if err != nil {
+					t.Fatalf("get: %v", err)
+				}
+				if !sameIDs(attemptIDs(got), attemptIDs(c.want)) {
+					t.Fatalf("got %s, want %s", describe(attemptIDs(got)), describe(attemptIDs(c.want)))
+				}
+				count, err := uow.Attempt().Count(ctx, c.params)
+				if err != nil || count != len(c.want) {
+					t.Fatalf("count: got %d (%v), want %d", count, err, len(c.want))
+				}
+			})
+		}
+
+		t.Run("TenantScope", func(t *testing.T) {
+			got, err := uow.Attempt().Get(tenantCtx("acme"), domain.AttemptSearchParams{})
+			if err != nil || !sameIDs(attemptIDs(got), []uuid.UUID{notFound.ID}) {
+				t.Fatalf("scoped get: got %s (%v), want only the tenant's attempt", describe(attemptIDs(got)), err)
+			}
+		})
+	})
+
+	t.Run("OrderingAndPagination", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob()
+		insertJobs(t, uow, job)
+		var attempts []domain.Attempt
+		for i := range 5 {
+			attempts = append(attempts, newAttempt(job.ID, 5-i, i))
+		}
+		insertAttempts(t, uow, attempts...)
+
+		for _, sortParam := range []string{"created_at", "-started_at", "attempt_number"} {
+			t.Run(sortParam, func(t *testing.T) {
+				field, desc := utils.ParseSort(sortParam)
+				want := slices.Clone(attempts)
+				slices.SortStableFunc(want, func(a, b domain.Attempt) int {
+					var c int
+					switch field {
+					case "started_at":
+						c = a.StartedAt.Compare(b.StartedAt)
+					case "attempt_number":
+						c = a.AttemptNumber - b.AttemptNumber
+					default:
+						c = a.CreatedAt.Compare(b.CreatedAt)
+					}
+					if c == 0 {
+						c = strings.Compare(a.ID.String(), b.ID.String())
+					}
+					if desc {
+						return -c
+					}
+					return c
+				})
+
+				var got []domain.Attempt
+				var cursor *string
+				for range len(attempts) {
+					chunk, err := uow.Attempt().Get(ctx, domain.AttemptSearchParams{SearchParams: utils.SearchParams{
+						Sort:   &sortParam,
+						Limit:  ptr(uint(2)),
+						Cursor: cursor,
+					}})
+					if err != nil {
+						t.Fatalf("get: %v", err)
+					}
+					got = append(got, chunk...)
+					if len(chunk) < 2 {
+						break
+					}
+					last := chunk[len(chunk)-1]
+					cursor = ptr(utils.EncodeCursor(utils.Cursor{Sort: sortParam, Value: last.SortValue(field), ID: last.ID.String()}))
+				}
+				if !slices.Equal(attemptIDs(got), attemptIDs(want)) {
+					t.Fatalf("got %s, want %s", describe(attemptIDs(got)), describe(attemptIDs(want)))
+				}
+			})
+		}
+	})
+}
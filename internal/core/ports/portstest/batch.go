@@ -0,0 +1,126 @@
+package portstest
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"maps"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestJobBatchRepository checks the batch counters and that MarkCompleted
+// reports the completion only once.
+func TestJobBatchRepository(t *testing.T, newUoW UnitOfWorkFactory) {
+	ctx := context.Background()
+
+	t.Run("InsertAndGetOne", func(t *testing.T) {
+		uow := newUoW(t)
+		batch := domain.NewJobBatch("acme", nil, domain.BulkCreateOptions{AllOrNothing: true}, at(0))
+		if err := uow.JobBatch().Insert(ctx, batch); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+
+		got, err := uow.JobBatch().GetOne(ctx, domain.JobBatchSearchParams{ID: &batch.ID})
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if got.Tenant != "acme" || !got.AllOrNothing || got.SealedAt != nil || got.CompletedAt != nil {
+			t.Fatalf("got %+v, want %+v", got, batch)
+		}
+
+		_, err = uow.JobBatch().GetOne(tenantCtx("globex"), domain.JobBatchSearchParams{ID: &batch.ID})
+		expectErr(t, err, domain.ErrNotFound, "get another tenant's batch")
+		_, err = uow.JobBatch().GetOne(ctx, domain.JobBatchSearchParams{ID: ptr(uuid.New())})
+		expectErr(t, err, domain.ErrNotFound, "get missing batch")
+	})
+
+	t.Run("Counters", func(t *testing.T) {
+		uow := newUoW(t)
+		batch := domain.NewJobBatch(domain.DefaultTenant, nil, domain.BulkCreateOptions{}, at(0))
+		if err := uow.JobBatch().Insert(ctx, batch); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+
+		atomic(t, uow, func(tx ports.IUnitOfWork) error {
+			if err := tx.JobBatch().AddCreated(ctx, batch.ID, 2); err != nil {
+				return err
+			}
+			return tx.JobBatch().AddCreated(ctx, batch.ID, 1)
+		})
+
+		sealed := batch
+		sealed.Total, sealed.Rejected, sealed.UpdatedAt = 4, 1, at(5)
+		got, err := uow.JobBatch().Seal(ctx, sealed)
+		if err != nil {
+			t.Fatalf("seal: %v", err)
+		}
+		if got.Total != 4 || got.Rejected != 1 || got.Created != 3 || got.SealedAt == nil || !got.SealedAt.Equal(at(5)) {
+			t.Fatalf("sealed batch: %+v", got)
+		}
+
+		var last *domain.JobBatch
+		atomic(t, uow, func(tx ports.IUnitOfWork) error {
+			for _, succeeded := range []bool{true, false, true} {
+				b, err := tx.JobBatch().RecordResult(ctx, batch.ID, succeeded)
+				if err != nil {
+					return err
+				}
+				last = b
+			}
+			return nil
+		})
+		if last.Succeeded != 2 || last.Failed != 1 || !last.Done() {
+			t.Fatalf("batch after results: %+v", last)
+		}
+	})
+
+	t.Run("MarkCompletedOnce", func(t *testing.T) {
+		uow := newUoW(t)
+		batch := domain.NewJobBatch(domain.DefaultTenant, nil, domain.BulkCreateOptions{}, at(0))
+		if err := uow.JobBatch().Insert(ctx, batch); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+
+		first, err := uow.JobBatch().MarkCompleted(ctx, batch.ID, nil, at(10))
+		if err != nil || !first {
+			t.Fatalf("first mark completed: got %v (%v), want true", first, err)
+		}
+		second, err := uow.JobBatch().MarkCompleted(ctx, batch.ID, nil, at(11))
+		if err != nil || second {
+			t.Fatalf("second mark completed: got %v (%v), want false", second, err)
+		}
+
+		got, err := uow.JobBatch().GetOne(ctx, domain.JobBatchSearchParams{ID: &batch.ID})
+		if err != nil || got.CompletedAt == nil || !got.CompletedAt.Equal(at(10)) {
+			t.Fatalf("completed_at: got %+v (%v), want %v", got, err, at(10))
+		}
+	})
+
+	t.Run("CountJobs", func(t *testing.T) {
+		uow := newUoW(t)
+		batch := domain.NewJobBatch(domain.DefaultTenant, nil, domain.BulkCreateOptions{}, at(0))
+		if err := uow.JobBatch().Insert(ctx, batch); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+		inBatch := func(status domain.JobStatus) domain.Job {
+			return newJob(func(j *domain.Job) { j.BatchID = &batch.ID; j.Status = status })
+		}
+		insertJobs(t, uow,
+			inBatch(domain.JobStatusPending),
+			inBatch(domain.JobStatusPending),
+			inBatch(domain.JobStatusCompleted),
+			newJob(),
+		)
+
+		counts, err := uow.JobBatch().CountJobs(ctx, batch.ID)
+		if err != nil {
+			t.Fatalf("count jobs: %v", err)
+		}
+		want := map[domain.JobStatus]int{domain.JobStatusPending: 2, domain.JobStatusCompleted: 1}
+		if !maps.Equal(counts, want) {
+			t.Fatalf("got %v, want %v", counts, want)
+		}
+	})
+}
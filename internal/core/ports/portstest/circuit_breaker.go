@@ -0,0 +1,74 @@
+package portstest
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"slices"
+	"testing"
+)
+
+// TestCircuitBreakerRepository checks that Upsert replaces the state of a
+// (node, host) breaker and the filters and ordering of Get.
+func TestCircuitBreakerRepository(t *testing.T, newUoW UnitOfWorkFactory) {
+	ctx := context.Background()
+	uow := newUoW(t)
+
+	breaker := func(node, host string, state domain.CircuitState, failures int) domain.CircuitBreaker {
+		return domain.CircuitBreaker{NodeID: node, Host: host, State: state, Failures: failures, UpdatedAt: at(0)}
+	}
+	opened := breaker("node-b", "a.example.com", domain.CircuitStateOpen, 5)
+	opened.OpenedAt, opened.RetryAt = ptr(at(1)), ptr(at(2))
+
+	for _, cb := range []domain.CircuitBreaker{
+		breaker("node-a", "b.example.com", domain.CircuitStateClosed, 0),
+		breaker("node-b", "a.example.com", domain.CircuitStateClosed, 4),
+		breaker("node-a", "a.example.com", domain.CircuitStateHalfOpen, 1),
+		opened,
+	} {
+		if err := uow.CircuitBreaker().Upsert(ctx, cb); err != nil {
+			t.Fatalf("upsert: %v", err)
+		}
+	}
+
+	type id struct{ node, host string }
+	ids := func(breakers []domain.CircuitBreaker) []id {
+		out := make([]id, 0, len(breakers))
+		for _, cb := range breakers {
+			out = append(out, id{cb.NodeID, cb.Host})
+		}
+		return out
+	}
+
+	cases := []struct {
+		name   string
+		params domain.CircuitBreakerSearchParams
+		want   []id
+	}{
+		{"AllSortedByHostAndNode", domain.CircuitBreakerSearchParams{}, []id{{"node-a", "a.example.com"}, {"node-b", "a.example.com"}, {"node-a", "b.example.com"}}},
+		{"NodeID", domain.CircuitBreakerSearchParams{NodeID: ptr("node-a")}, []id{{"node-a", "a.example.com"}, {"node-a", "b.example.com"}}},
+		{"Host", domain.CircuitBreakerSearchParams{Host: ptr("a.example.com")}, []id{{"node-a", "a.example.com"}, {"node-b", "a.example.com"}}},
+		{"State", domain.CircuitBreakerSearchParams{State: ptr(domain.CircuitStateOpen)}, []id{{"node-b", "a.example.com"}}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := uow.CircuitBreaker().Get(ctx, c.params)
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			if !slices.Equal(ids(got), c.want) {
+				t.Fatalf("got %v, want %v", ids(got), c.want)
+			}
+		})
+	}
+
+	t.Run("UpsertReplacesState", func(t *testing.T) {
+		got, err := uow.CircuitBreaker().Get(ctx, domain.CircuitBreakerSearchParams{NodeID: ptr("node-b")})
+		if err != nil || len(got) != 1 {
+			t.Fatalf("get: got %d (%v), want 1", len(got), err)
+		}
+		cb := got[0]
+		if cb.Failures != 5 || cb.OpenedAt == nil || !cb.OpenedAt.Equal(at(1)) || cb.RetryAt == nil || !cb.RetryAt.Equal(at(2)) {
+			t.Fatalf("got %+v, want %+v", cb, opened)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package portstest
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestConcurrencyLimitRepository checks the per-type semaphore: slots are
+// granted up to the limit, counted in Running and freed by ReleaseSlot.
+func TestConcurrencyLimitRepository(t *testing.T, newUoW UnitOfWorkFactory) {
+	ctx := context.Background()
+
+	newLimit := func(jobType string, max int) domain.ConcurrencyLimit {
+		limit, err := domain.NewConcurrencyLimit(domain.ConcurrencyLimitInput{JobType: jobType, MaxConcurrent: max}, at(0))
+		if err != nil {
+			t.Fatalf("new concurrency limit: %v", err)
+		}
+		return *limit
+	}
+	acquire := func(t *testing.T, uow ports.IUnitOfWork, jobType string, jobID uuid.UUID) bool {
+		t.Helper()
+		var ok bool
+		atomic(t, uow, func(tx ports.IUnitOfWork) error {
+			var err error
+			ok, err = tx.ConcurrencyLimit().AcquireSlot(ctx, jobType, jobID)
+			return err
+		})
+		return ok
+	}
+
+	t.Run("UpsertGetDelete", func(t *testing.T) {
+		uow := newUoW(t)
+		for _, l := range []domain.ConcurrencyLimit{newLimit("report", 2), newLimit("email", 1), newLimit("email", 3)} {
+			if err := uow.ConcurrencyLimit().Upsert(ctx, l); err != nil {
+				t.Fatalf("upsert: %v", err)
+			}
+		}
+
+		got, err := uow.ConcurrencyLimit().Get(ctx, domain.ConcurrencyLimitSearchParams{})
+		if err != nil || len(got) != 2 {
+			t.Fatalf("get: got %d (%v), want 2", len(got), err)
+		}
+		if got[0].JobType != "email" || got[0].MaxConcurrent != 3 || got[1].JobType != "report" {
+			t.Fatalf("got %+v, want email (updated to 3) then report", got)
+		}
+
+		if err := uow.ConcurrencyLimit().Delete(ctx, "email"); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		got, err = uow.ConcurrencyLimit().Get(ctx, domain.ConcurrencyLimitSearchParams{JobType: ptr("email")})
+		if err != nil || len(got) != 0 {
+			t.Fatalf("after delete: got %d (%v), want 0", len(got), err)
+		}
+	})
+
+	t.Run("Slots", func(t *testing.T) {
+		uow := newUoW(t)
+		if err := uow.ConcurrencyLimit().Upsert(ctx, newLimit("email", 2)); err != nil {
+			t.Fatalf("upsert: %v", err)
+		}
+		jobs := []domain.Job{newJob(), newJob(), newJob()}
+		insertJobs(t, uow, jobs...)
+
+		if !acquire(t, uow, "email", jobs[0].ID) || !acquire(t, uow, "email", jobs[1].ID) {
+			t.Fatal("acquire under the limit was refused")
+		}
+		if acquire(t, uow, "email", jobs[2].ID) {
+			t.Fatal("acquire over the limit was granted")
+		}
+
+		got, err := uow.ConcurrencyLimit().Get(ctx, domain.ConcurrencyLimitSearchParams{JobType: ptr("email")})
+		if err != nil || len(got) != 1 || got[0].Running != 2 {
+			t.Fatalf("running: got %+v (%v), want 2", got, err)
+		}
+
+		if err := uow.ConcurrencyLimit().ReleaseSlot(ctx, jobs[0].ID); err != nil {
+			t.Fatalf("release: %v", err)
+		}
+		if !acquire(t, uow, "email", jobs[2].ID) {
+			t.Fatal("acquire after a release was refused")
+		}
+	})
+
+	t.Run("RolledBackSlotIsFree", func(t *testing.T) {
+		uow := newUoW(t)
+		if err := uow.ConcurrencyLimit().Upsert(ctx, newLimit("email", 1)); err != nil {
+			t.Fatalf("upsert: %v", err)
+		}
+		first, second := newJob(), newJob()
+		insertJobs(t, uow, first, second)
+
+		_ = uow.Atomic(ctx, func(tx ports.IUnitOfWork) error {
+			if _, err := tx.ConcurrencyLimit().AcquireSlot(ctx, "email", first.ID); err != nil {
+				return err
+			}
+			return domain.ErrConflict
+		})
+		if !acquire(t, uow, "email", second.ID) {
+			t.Fatal("slot taken in a rolled back transaction is still held")
+		}
+	})
+
+	t.Run("NoLimit", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob()
+		insertJobs(t, uow, job)
+		if !acquire(t, uow, "email", job.ID) {
+			t.Fatal("acquire on a type without limit was refused")
+		}
+		if _, err := uow.ConcurrencyLimit().AcquireSlot(ctx, "email", job.ID); err == nil {
+			t.Fatal("acquire outside a transaction succeeded")
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package portstest
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/utils"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func insertEvents(t *testing.T, uow ports.IUnitOfWork, events ...domain.Event) {
+	t.Helper()
+	atomic(t, uow, func(tx ports.IUnitOfWork) error {
+		for _, e := range events {
+			if err := tx.Event().Insert(context.Background(), e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func eventIDs(events []domain.Event) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+// TestEventRepository checks that events get increasing sequence numbers and
+// the tenant of their job, come back in insertion order and honour the
+// filters used by the timeline and the event stream.
+func TestEventRepository(t *testing.T, newUoW UnitOfWorkFactory) {
+	ctx := context.Background()
+
+	t.Run("SequenceAndOrder", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob(func(j *domain.Job) { j.Tenant = "acme" })
+		insertJobs(t, uow, job)
+
+		var events []domain.Event
+		for range 5 {
+			events = append(events, domain.NewJobFailedEvent(job.ID, "boom", at(0)))
+		}
+		insertEvents(t, uow, events...)
+
+		got, err := uow.Event().Get(ctx, domain.EventSearchParams{JobID: &job.ID})
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if !slices.Equal(eventIDs(got), eventIDs(events)) {
+			t.Fatalf("got %s, want insertion order %s", describe(eventIDs(got)), describe(eventIDs(events)))
+		}
+		for i, e := range got {
+			if e.Tenant != "acme" {
+				t.Fatalf("event %d: tenant %q, want the job's", i, e.Tenant)
+			}
+			if i > 0 && e.Seq <= got[i-1].Seq {
+				t.Fatalf("seq not increasing: %d after %d", e.Seq, got[i-1].Seq)
+			}
+		}
+
+		err = uow.Atomic(ctx, func(tx ports.IUnitOfWork) error {
+			return tx.Event().Insert(ctx, domain.NewJobDeadEvent(uuid.New(), at(0)))
+		})
+		if err == nil {
+			t.Fatal("event of a missing job was inserted")
+		}
+	})
+
+	t.Run("SearchFilters", func(t *testing.T) {
+		uow := newUoW(t)
+		email := newJob()
+		report := newJob(func(j *domain.Job) {
+			j.Type = "report"
+			j.Tenant = "acme"
+			j.Status = domain.JobStatusFailed
+		})
+		insertJobs(t, uow, email, report)
+
+		succeeded := domain.NewJobSucceededEvent(email.ID, at(0))
+		failed := domain.NewJobFailedEvent(report.ID, "boom", at(0))
+		dead := domain.NewJobDeadEvent(report.ID, at(0))
+		insertEvents(t, uow, succeeded, failed, dead)
+
+		all, err := uow.Event().Get(ctx, domain.EventSearchParams{})
+		if err != nil || len(all) != 3 {
+			t.Fatalf("get all: got %d (%v), want 3", len(all), err)
+		}
+		seqOf := func(id uuid.UUID) int64 {
+			for _, e := range all {
+				if e.ID == id {
+					return e.Seq
+				}
+			}
+			t.Fatalf("event %s not found", id)
+			return 0
+		}
+
+		cases := []struct {
+			name   string
+			params domain.EventSearchParams
+			want   []domain.Event
+		}{
+			{"ID", domain.EventSearchParams{ID: &failed.ID}, []domain.Event{failed}},
+			{"Tenant", domain.EventSearchParams{Tenant: ptr("acme")}, []domain.Event{failed, dead}},
+			{"JobID", domain.EventSearchParams{JobID: &email.ID}, []domain.Event{succeeded}},
+			{"Types", domain.EventSearchParams{Types: []domain.EventType{domain.EventJobSucceeded, domain.EventJobDead}}, []domain.Event{succeeded, dead}},
+			{"Seq", domain.EventSearchParams{Seq: ptr(seqOf(dead.ID))}, []domain.Event{dead}},
+			{"AfterSeq", domain.EventSearchParams{AfterSeq: ptr(seqOf(succeeded.ID))}, []domain.Event{failed, dead}},
+			{"JobType", domain.EventSearchParams{JobType: ptr("report")}, []domain.Event{failed, dead}},
+			{"JobStatus", domain.EventSearchParams{JobStatus: ptr(domain.JobStatusPending)}, []domain.Event{succeeded}},
+			{"Limit", domain.EventSearchParams{SearchParams: utils.SearchParams{Limit: ptr(uint(2))}}, []domain.Event{succeeded, failed}},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				got, err := uow.Event().Get(ctx, c.params)
+				if err != nil {
+					t.Fatalf("get: %v", err)
+				}
+				if !slices.Equal(eventIDs(got), eventIDs(c.want)) {
+					t.Fatalf("got %s, want %s", describe(eventIDs(got)), describe(eventIDs(c.want)))
+				}
+			})
+		}
+
+		t.Run("TenantScope", func(t *testing.T) {
+			got, err := uow.Event().Get(tenantCtx("acme"), domain.EventSearchParams{})
+			if err != nil || !slices.Equal(eventIDs(got), []uuid.UUID{failed.ID, dead.ID}) {
+				t.Fatalf("scoped get: got %s (%v), want only the tenant's events", describe(eventIDs(got)), err)
+			}
+		})
+	})
+
+	t.Run("GetStream", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob(func(j *domain.Job) { j.Type = "report" })
+		insertJobs(t, uow, job)
+		event := domain.NewJobFailedEvent(job.ID, "boom", at(0))
+		insertEvents(t, uow, event)
+		atomic(t, uow, func(tx ports.IUnitOfWork) error {
+			return tx.Job().MarkQueued(ctx, job.ID)
+		})
+
+		// El stream lleva el tipo y el estado actual del job
+		got, err := uow.Event().GetStream(ctx, domain.EventSearchParams{JobID: &job.ID})
+		if err != nil || len(got) != 1 {
+			t.Fatalf("get stream: got %d (%v), want 1", len(got), err)
+		}
+		if got[0].ID != event.ID || got[0].JobType != "report" || got[0].JobStatus != domain.JobStatusQueued {
+			t.Fatalf("got %+v", got[0])
+		}
+	})
+}
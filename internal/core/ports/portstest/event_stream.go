@@ -0,0 +1,94 @@
+package portstest
+
+import (
+	"context"
+	"errors"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"testing"
+	"time"
+)
+
+// EventBrokerFactory devuelve un IUnitOfWork vacío y el broker que reparte
+// los eventos que se confirman en él.
+type EventBrokerFactory func(t *testing.T) (ports.IUnitOfWork, ports.IEventBroker)
+
+// deliveryTimeout es cuánto se espera un mensaje o evento que tiene que llegar.
+const deliveryTimeout = 5 * time.Second
+
+// quietPeriod es cuánto se espera para afirmar que algo no llega.
+const quietPeriod = 200 * time.Millisecond
+
+// TestEventBroker checks that subscribers receive the committed events that
+// match their filter, never the rolled back ones, and that unsubscribing
+// closes the channel.
+func TestEventBroker(t *testing.T, newBroker EventBrokerFactory) {
+	ctx := context.Background()
+
+	t.Run("DeliversCommittedMatchingEvents", func(t *testing.T) {
+		uow, broker := newBroker(t)
+		email := newJob(func(j *domain.Job) { j.Tenant = "acme" })
+		report := newJob(func(j *domain.Job) { j.Tenant = "acme"; j.Type = "report" })
+		insertJobs(t, uow, email, report)
+
+		events, unsubscribe := broker.Subscribe(domain.EventStreamFilter{Tenant: ptr("acme"), JobType: ptr("report")})
+		defer unsubscribe()
+
+		insertEvents(t, uow, domain.NewJobSucceededEvent(email.ID, at(0)))
+		failed := domain.NewJobFailedEvent(report.ID, "boom", at(0))
+		insertEvents(t, uow, failed)
+
+		select {
+		case got, ok := <-events:
+			if !ok {
+				t.Fatal("channel closed before the event arrived")
+			}
+			if got.ID != failed.ID || got.JobType != "report" || got.JobStatus != domain.JobStatusPending || got.Tenant != "acme" {
+				t.Fatalf("got %+v, want the report event", got)
+			}
+		case <-time.After(deliveryTimeout):
+			t.Fatal("committed event was not delivered")
+		}
+	})
+
+	t.Run("SkipsRolledBackEvents", func(t *testing.T) {
+		uow, broker := newBroker(t)
+		job := newJob()
+		insertJobs(t, uow, job)
+
+		events, unsubscribe := broker.Subscribe(domain.EventStreamFilter{JobID: &job.ID})
+		defer unsubscribe()
+
+		errAbort := errors.New("abort")
+		err := uow.Atomic(ctx, func(tx ports.IUnitOfWork) error {
+			if err := tx.Event().Insert(ctx, domain.NewJobFailedEvent(job.ID, "boom", at(0))); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		expectErr(t, err, errAbort, "atomic")
+
+		select {
+		case got := <-events:
+			t.Fatalf("rolled back event was delivered: %+v", got)
+		case <-time.After(quietPeriod):
+		}
+	})
+
+	t.Run("UnsubscribeClosesChannel", func(t *testing.T) {
+		_, broker := newBroker(t)
+		events, unsubscribe := broker.Subscribe(domain.EventStreamFilter{})
+		unsubscribe()
+		// Llamarlo dos veces no debe romper nada
+		unsubscribe()
+
+		select {
+		case _, ok := <-events:
+			if ok {
+				t.Fatal("received an event after unsubscribing")
+			}
+		case <-time.After(deliveryTimeout):
+			t.Fatal("channel was not closed")
+		}
+	})
+}
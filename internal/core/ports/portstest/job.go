@@ -0,0 +1,691 @@
+package portstest
+
+import (
+	"context"
+	"errors"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/utils"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// allJobStatuses son todos los estados, para recorrer la máquina de estados completa.
+var allJobStatuses = []domain.JobStatus{
+	domain.JobStatusPending,
+	domain.JobStatusQueued,
+	domain.JobStatusRunning,
+	domain.JobStatusCompleted,
+	domain.JobStatusFailed,
+	domain.JobStatusDead,
+	domain.JobStatusDisabled,
+}
+
+// jobMarks aplica la transición a cada estado destino que tiene un método en el repositorio.
+var jobMarks = map[domain.JobStatus]func(ctx context.Context, repo ports.IJobRepository, id uuid.UUID) error{
+	domain.JobStatusQueued: func(ctx context.Context, repo ports.IJobRepository, id uuid.UUID) error {
+		return repo.MarkQueued(ctx, id)
+	},
+	domain.JobStatusRunning: func(ctx context.Context, repo ports.IJobRepository, id uuid.UUID) error {
+		return repo.MarkRunning(ctx, id)
+	},
+	domain.JobStatusCompleted: func(ctx context.Context, repo ports.IJobRepository, id uuid.UUID) error {
+		return repo.MarkCompleted(ctx, id)
+	},
+	domain.JobStatusFailed: func(ctx context.Context, repo ports.IJobRepository, id uuid.UUID) error {
+		return repo.MarkFailed(ctx, id, "callback failed", ptr(500))
+	},
+	domain.JobStatusDead: func(ctx context.Context, repo ports.IJobRepository, id uuid.UUID) error {
+		return repo.MarkDead(ctx, id, "no retries left")
+	},
+	domain.JobStatusPending: func(ctx context.Context, repo ports.IJobRepository, id uuid.UUID) error {
+		return repo.Reschedule(ctx, id, at(30))
+	},
+}
+
+// TestJobRepository checks an IJobRepository against the job state machine,
+// the dispatcher lock, every search filter, tenant scoping, sort order and
+// both page and cursor pagination.
+func TestJobRepository(t *testing.T, newUoW UnitOfWorkFactory) {
+	ctx := context.Background()
+
+	t.Run("InsertAndGetOne", func(t *testing.T) {
+		uow := newUoW(t)
+		batch := domain.NewJobBatch(domain.DefaultTenant, nil, domain.BulkCreateOptions{}, at(0))
+		if err := uow.JobBatch().Insert(ctx, batch); err != nil {
+			t.Fatalf("insert batch: %v", err)
+		}
+		job := newJob(func(j *domain.Job) {
+			j.Payload = []byte(`{"to":"a@example.com"}`)
+			j.Priority = 3
+			j.MaxRetries = 2
+			j.ScheduledAt = ptr(at(5))
+			j.BatchID = &batch.ID
+		})
+		insertJobs(t, uow, job)
+
+		got := getJob(t, uow, job.ID)
+		if got.Tenant != job.Tenant || got.Type != job.Type || got.CallbackURL != job.CallbackURL ||
+			got.Status != job.Status || got.Priority != job.Priority || got.MaxRetries != job.MaxRetries {
+			t.Fatalf("got %+v, want %+v", got, job)
+		}
+		if !jsonEqual(got.Payload, job.Payload) {
+			t.Fatalf("payload: got %s, want %s", got.Payload, job.Payload)
+		}
+		if got.ScheduledAt == nil || !got.ScheduledAt.Equal(*job.ScheduledAt) || !got.CreatedAt.Equal(job.CreatedAt) {
+			t.Fatalf("timestamps: got scheduled %v created %v, want %v %v", got.ScheduledAt, got.CreatedAt, job.ScheduledAt, job.CreatedAt)
+		}
+		if got.BatchID == nil || *got.BatchID != batch.ID {
+			t.Fatalf("batch_id: got %v, want %s", got.BatchID, batch.ID)
+		}
+		if got.LockedAt != nil || got.LockedBy != nil || got.CompletedAt != nil {
+			t.Fatalf("new job should not be locked or completed: %+v", got)
+		}
+
+		missing := uuid.New()
+		_, err := uow.Job().GetOne(ctx, domain.JobSearchParams{ID: &missing})
+		expectErr(t, err, domain.ErrNotFound, "get missing job")
+	})
+
+	t.Run("InsertDuplicate", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob()
+		insertJobs(t, uow, job)
+		expectErr(t, uow.Job().Insert(ctx, job), domain.ErrConflict, "insert duplicate job")
+	})
+
+	t.Run("InsertMany", func(t *testing.T) {
+		uow := newUoW(t)
+		jobs := []domain.Job{newJob(), newJob(), newJob()}
+		atomic(t, uow, func(tx ports.IUnitOfWork) error {
+			return tx.Job().InsertMany(ctx, jobs)
+		})
+
+		got, err := uow.Job().Get(ctx, domain.JobSearchParams{})
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if !sameIDs(jobIDs(got), jobIDs(jobs)) {
+			t.Fatalf("got %s, want %s", describe(jobIDs(got)), describe(jobIDs(jobs)))
+		}
+	})
+
+	t.Run("StatusTransitions", func(t *testing.T) {
+		for _, from := range allJobStatuses {
+			for to, mark := range jobMarks {
+				t.Run(string(from)+"_to_"+string(to), func(t *testing.T) {
+					uow := newUoW(t)
+					job := newJob(func(j *domain.Job) { j.Status = from })
+					insertJobs(t, uow, job)
+
+					var err error
+					atomic(t, uow, func(tx ports.IUnitOfWork) error {
+						err = mark(ctx, tx.Job(), job.ID)
+						return nil
+					})
+					got := getJob(t, uow, job.ID)
+
+					if from.CanTransitionTo(to) {
+						if err != nil {
+							t.Fatalf("transition: %v", err)
+						}
+						if got.Status != to {
+							t.Fatalf("status: got %s, want %s", got.Status, to)
+						}
+						if !got.UpdatedAt.After(job.UpdatedAt) {
+							t.Fatalf("updated_at not advanced: %v", got.UpdatedAt)
+						}
+						return
+					}
+
+					var transitionErr *domain.TransitionError
+					if !errors.As(err, &transitionErr) || !errors.Is(err, domain.ErrConflict) {
+						t.Fatalf("got error %v, want *domain.TransitionError", err)
+					}
+					if transitionErr.From != from || transitionErr.To != to || transitionErr.JobID != job.ID {
+						t.Fatalf("got %+v, want from %s to %s", transitionErr, from, to)
+					}
+					if got.Status != from {
+						t.Fatalf("status changed by a rejected transition: got %s, want %s", got.Status, from)
+					}
+				})
+			}
+		}
+
+		t.Run("MissingJob", func(t *testing.T) {
+			uow := newUoW(t)
+			for to, mark := range jobMarks {
+				atomic(t, uow, func(tx ports.IUnitOfWork) error {
+					expectErr(t, mark(ctx, tx.Job(), uuid.New()), domain.ErrNotFound, "mark missing job "+string(to))
+					return nil
+				})
+			}
+		})
+
+		t.Run("Effects", func(t *testing.T) {
+			uow := newUoW(t)
+			job := newJob()
+			insertJobs(t, uow, job)
+
+			atomic(t, uow, func(tx ports.IUnitOfWork) error {
+				for _, step := range []func() error{
+					func() error { return tx.Job().MarkQueued(ctx, job.ID) },
+					func() error { return tx.Job().MarkRunning(ctx, job.ID) },
+					func() error { return tx.Job().MarkCompleted(ctx, job.ID) },
+				} {
+					if err := step(); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if got := getJob(t, uow, job.ID); got.CompletedAt == nil || got.Attempts != 1 {
+				t.Fatalf("completed job: completed_at %v attempts %d, want a completed_at and 1 attempt", got.CompletedAt, got.Attempts)
+			}
+
+			// Cada intento cerrado suma uno; diferir o reprogramar no
+			retried := newJob(func(j *domain.Job) { j.Status = domain.JobStatusQueued })
+			insertJobs(t, uow, retried)
+			atomic(t, uow, func(tx ports.IUnitOfWork) error {
+				for _, step := range []func() error{
+					func() error { return tx.Job().MarkRunning(ctx, retried.ID) },
+					func() error { return tx.Job().MarkFailed(ctx, retried.ID, "boom", nil) },
+					func() error { return tx.Job().Reschedule(ctx, retried.ID, at(30)) },
+					func() error { return tx.Job().MarkQueued(ctx, retried.ID) },
+					func() error { return tx.Job().MarkRunning(ctx, retried.ID) },
+					func() error { return tx.Job().MarkDead(ctx, retried.ID, "no retries left") },
+				} {
+					if err := step(); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if got := getJob(t, uow, retried.ID); got.Attempts != 2 {
+				t.Fatalf("attempts: got %d, want 2", got.Attempts)
+			}
+			if msg := domain.NewRabbitJobMessageFromJob(getJob(t, uow, retried.ID)); msg.Attempt != 3 {
+				t.Fatalf("message attempt: got %d, want 3", msg.Attempt)
+			}
+
+			rescheduled := newJob(func(j *domain.Job) { j.Status = domain.JobStatusFailed })
+			insertJobs(t, uow, rescheduled)
+			atomic(t, uow, func(tx ports.IUnitOfWork) error {
+				return tx.Job().Reschedule(ctx, rescheduled.ID, at(90))
+			})
+			if got := getJob(t, uow, rescheduled.ID); got.ScheduledAt == nil || !got.ScheduledAt.Equal(at(90)) {
+				t.Fatalf("scheduled_at after reschedule: got %v, want %v", got.ScheduledAt, at(90))
+			}
+		})
+
+		t.Run("DeferRetry", func(t *testing.T) {
+			uow := newUoW(t)
+			failed := newJob(func(j *domain.Job) { j.Status = domain.JobStatusFailed })
+			queued := newJob(func(j *domain.Job) { j.Status = domain.JobStatusQueued })
+			insertJobs(t, uow, failed, queued)
+
+			atomic(t, uow, func(tx ports.IUnitOfWork) error {
+				return tx.Job().DeferRetry(ctx, failed.ID, at(90))
+			})
+			got := getJob(t, uow, failed.ID)
+			if got.Status != domain.JobStatusFailed || !got.RetryDeferred || got.ScheduledAt == nil || !got.ScheduledAt.Equal(at(90)) {
+				t.Fatalf("deferred retry: got status %s deferred %v scheduled_at %v, want failed, deferred, %v",
+					got.Status, got.RetryDeferred, got.ScheduledAt, at(90))
+			}
+
+			// Solo se difiere el reintento de un job failed
+			atomic(t, uow, func(tx ports.IUnitOfWork) error {
+				var transitionErr *domain.TransitionError
+				if err := tx.Job().DeferRetry(ctx, queued.ID, at(90)); !errors.As(err, &transitionErr) || transitionErr.From != domain.JobStatusQueued {
+					t.Fatalf("defer retry of a queued job: got %v, want *domain.TransitionError from queued", err)
+				}
+				expectErr(t, tx.Job().DeferRetry(ctx, uuid.New(), at(90)), domain.ErrNotFound, "defer retry of a missing job")
+				return nil
+			})
+
+			// El dispatcher lo publica como un pending y MarkQueued borra la marca
+			jobs, err := uow.Job().Get(ctx, domain.JobSearchParams{Dispatchable: ptr(true)})
+			if err != nil {
+				t.Fatalf("get dispatchable: %v", err)
+			}
+			if !sameIDs(jobIDs(jobs), []uuid.UUID{failed.ID}) {
+				t.Fatalf("dispatchable: got %s, want only the deferred retry", describe(jobIDs(jobs)))
+			}
+			if err := uow.Job().LockJob(ctx, failed.ID, "dispatcher-1", time.Hour); err != nil {
+				t.Fatalf("lock a deferred retry: %v", err)
+			}
+			atomic(t, uow, func(tx ports.IUnitOfWork) error {
+				return tx.Job().MarkQueued(ctx, failed.ID)
+			})
+			if got := getJob(t, uow, failed.ID); got.Status != domain.JobStatusQueued || got.RetryDeferred {
+				t.Fatalf("queued retry: got status %s deferred %v, want queued and not deferred", got.Status, got.RetryDeferred)
+			}
+		})
+	})
+
+	t.Run("LockJob", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob()
+		queued := newJob(func(j *domain.Job) { j.Status = domain.JobStatusQueued })
+		insertJobs(t, uow, job, queued)
+
+		if err := uow.Job().LockJob(ctx, job.ID, "dispatcher-1", time.Hour); err != nil {
+			t.Fatalf("lock: %v", err)
+		}
+		got := getJob(t, uow, job.ID)
+		if got.LockedAt == nil || got.LockedBy == nil || *got.LockedBy != "dispatcher-1" {
+			t.Fatalf("lock not recorded: locked_at %v locked_by %v", got.LockedAt, got.LockedBy)
+		}
+
+		expectErr(t, uow.Job().LockJob(ctx, job.ID, "dispatcher-2", time.Hour), domain.ErrConflict, "lock a locked job")
+		expectErr(t, uow.Job().LockJob(ctx, queued.ID, "dispatcher-1", time.Hour), domain.ErrConflict, "lock a queued job")
+		// Un failed con el reintento en la cola no es del dispatcher
+		failed := newJob(func(j *domain.Job) { j.Status = domain.JobStatusFailed })
+		insertJobs(t, uow, failed)
+		expectErr(t, uow.Job().LockJob(ctx, failed.ID, "dispatcher-1", time.Hour), domain.ErrConflict, "lock a failed job")
+		expectErr(t, uow.Job().LockJob(ctx, uuid.New(), "dispatcher-1", time.Hour), domain.ErrConflict, "lock a missing job")
+
+		// Las transiciones que devuelven el job a la cola de espera liberan el lock
+		atomic(t, uow, func(tx ports.IUnitOfWork) error {
+			if err := tx.Job().MarkQueued(ctx, job.ID); err != nil {
+				return err
+			}
+			if err := tx.Job().MarkRunning(ctx, job.ID); err != nil {
+				return err
+			}
+			return tx.Job().MarkFailed(ctx, job.ID, "boom", nil)
+		})
+		if got := getJob(t, uow, job.ID); got.LockedAt != nil || got.LockedBy != nil {
+			t.Fatalf("failed job still locked: %v %v", got.LockedAt, got.LockedBy)
+		}
+	})
+
+	t.Run("LockExpiry", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob()
+		insertJobs(t, uow, job)
+		if err := uow.Job().LockJob(ctx, job.ID, "dispatcher-1", time.Hour); err != nil {
+			t.Fatalf("lock: %v", err)
+		}
+
+		lockFree := func(timeout *time.Duration) []uuid.UUID {
+			t.Helper()
+			jobs, err := uow.Job().Get(ctx, domain.JobSearchParams{LockFree: ptr(true), LockTimeout: timeout})
+			if err != nil {
+				t.Fatalf("get lock free: %v", err)
+			}
+			return jobIDs(jobs)
+		}
+
+		if got := lockFree(nil); len(got) != 0 {
+			t.Fatalf("locked job listed as lock free: %s", describe(got))
+		}
+		if got := lockFree(ptr(time.Hour)); len(got) != 0 {
+			t.Fatalf("fresh lock listed as expired: %s", describe(got))
+		}
+		time.Sleep(10 * time.Millisecond)
+		if got := lockFree(ptr(time.Millisecond)); !sameIDs(got, []uuid.UUID{job.ID}) {
+			t.Fatalf("expired lock not listed: got %s", describe(got))
+		}
+
+		// Otro dispatcher solo toma el lock si ya venció
+		expectErr(t, uow.Job().LockJob(ctx, job.ID, "dispatcher-2", time.Hour), domain.ErrConflict, "lock before the timeout")
+		if err := uow.Job().LockJob(ctx, job.ID, "dispatcher-2", time.Millisecond); err != nil {
+			t.Fatalf("lock after the timeout: %v", err)
+		}
+		if got := getJob(t, uow, job.ID); got.LockedBy == nil || *got.LockedBy != "dispatcher-2" {
+			t.Fatalf("expired lock not taken over: locked_by %v", got.LockedBy)
+		}
+	})
+
+	t.Run("ReadyToRun", func(t *testing.T) {
+		uow := newUoW(t)
+		due := newJob()
+		future := newJob(func(j *domain.Job) { j.ScheduledAt = ptr(time.Now().Add(time.Hour)) })
+		insertJobs(t, uow, due, future)
+
+		jobs, err := uow.Job().Get(ctx, domain.JobSearchParams{ReadyToRun: ptr(true)})
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		if !sameIDs(jobIDs(jobs), []uuid.UUID{due.ID}) {
+			t.Fatalf("got %s, want only the due job", describe(jobIDs(jobs)))
+		}
+	})
+
+	t.Run("SearchFilters", func(t *testing.T) {
+		uow := newUoW(t)
+		batch := domain.NewJobBatch("acme", nil, domain.BulkCreateOptions{}, at(0))
+		if err := uow.JobBatch().Insert(ctx, batch); err != nil {
+			t.Fatalf("insert batch: %v", err)
+		}
+		keyID := uuid.New()
+
+		email := newJob(func(j *domain.Job) {
+			j.Payload = []byte(`{"subject":"Welcome"}`)
+		})
+		report := newJob(func(j *domain.Job) {
+			j.Type = "report"
+			j.Status = domain.JobStatusFailed
+			j.CreatedAt = at(10)
+			j.ScheduledAt = ptr(at(20))
+			j.CreatedByKeyID = &keyID
+		})
+		acme := newJob(func(j *domain.Job) {
+			j.Tenant = "acme"
+			j.Type = "report"
+			j.Status = domain.JobStatusCompleted
+			j.CreatedAt = at(20)
+			j.ScheduledAt = ptr(at(40))
+			j.BatchID = &batch.ID
+		})
+		insertJobs(t, uow, email, report, acme)
+
+		cases := []struct {
+			name   string
+			params domain.JobSearchParams
+			want   []domain.Job
+		}{
+			{"All", domain.JobSearchParams{}, []domain.Job{email, report, acme}},
+			{"ID", domain.JobSearchParams{ID: &report.ID}, []domain.Job{report}},
+			{"Tenant", domain.JobSearchParams{Tenant: ptr("acme")}, []domain.Job{acme}},
+			{"Type", domain.JobSearchParams{Type: ptr("report")}, []domain.Job{report, acme}},
+			{"Status", domain.JobSearchParams{Status: ptr(domain.JobStatusFailed)}, []domain.Job{report}},
+			{"Statuses", domain.JobSearchParams{Statuses: []domain.JobStatus{domain.JobStatusPending, domain.JobStatusCompleted}}, []domain.Job{email, acme}},
+			{"CreatedFrom", domain.JobSearchParams{CreatedFrom: ptr(at(10))}, []domain.Job{report, acme}},
+			{"CreatedTo", domain.JobSearchParams{CreatedTo: ptr(at(10))}, []domain.Job{email}},
+			{"ScheduledRange", domain.JobSearchParams{ScheduledFrom: ptr(at(20)), ScheduledTo: ptr(at(40))}, []domain.Job{report}},
+			{"CreatedByKeyID", domain.JobSearchParams{CreatedByKeyID: &keyID}, []domain.Job{report}},
+			{"BatchID", domain.JobSearchParams{BatchID: &batch.ID}, []domain.Job{acme}},
+			{"QMatchesType", domain.JobSearchParams{SearchParams: utils.SearchParams{Q: ptr("REPO")}}, []domain.Job{report, acme}},
+			{"QMatchesPayload", domain.JobSearchParams{SearchParams: utils.SearchParams{Q: ptr("welcome")}}, []domain.Job{email}},
+			{"Combined", domain.JobSearchParams{Type: ptr("report"), Tenant: ptr(domain.DefaultTenant)}, []domain.Job{report}},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				got, err := uow.Job().Get(ctx, c.params)
+				if err != nil {
+					t.Fatalf("get: %v", err)
+				}
+				if !sameIDs(jobIDs(got), jobIDs(c.want)) {
+					t.Fatalf("got %s, want %s", describe(jobIDs(got)), describe(jobIDs(c.want)))
+				}
+				count, err := uow.Job().Count(ctx, c.params)
+				if err != nil || count != len(c.want) {
+					t.Fatalf("count: got %d (%v), want %d", count, err, len(c.want))
+				}
+			})
+		}
+	})
+
+	t.Run("TenantScope", func(t *testing.T) {
+		uow := newUoW(t)
+		own := newJob(func(j *domain.Job) { j.Tenant = "acme" })
+		other := newJob(func(j *domain.Job) { j.Tenant = "globex" })
+		insertJobs(t, uow, own, other)
+
+		scoped := tenantCtx("acme")
+		jobs, err := uow.Job().Get(scoped, domain.JobSearchParams{})
+		if err != nil || !sameIDs(jobIDs(jobs), []uuid.UUID{own.ID}) {
+			t.Fatalf("scoped get: got %s (%v), want only the tenant's job", describe(jobIDs(jobs)), err)
+		}
+		// El tenant del request gana sobre el filtro
+		jobs, err = uow.Job().Get(scoped, domain.JobSearchParams{Tenant: ptr("globex")})
+		if err != nil || len(jobs) != 0 {
+			t.Fatalf("scoped get of another tenant: got %s (%v), want none", describe(jobIDs(jobs)), err)
+		}
+		_, err = uow.Job().GetOne(scoped, domain.JobSearchParams{ID: &other.ID})
+		expectErr(t, err, domain.ErrNotFound, "get another tenant's job")
+		if count, err := uow.Job().Count(scoped, domain.JobSearchParams{}); err != nil || count != 1 {
+			t.Fatalf("scoped count: got %d (%v), want 1", count, err)
+		}
+	})
+
+	t.Run("Ordering", func(t *testing.T) {
+		uow := newUoW(t)
+		types := []string{"charlie", "alpha", "bravo"}
+		statuses := []domain.JobStatus{domain.JobStatusPending, domain.JobStatusFailed, domain.JobStatusQueued}
+		var jobs []domain.Job
+		for i := range 6 {
+			jobs = append(jobs, newJob(func(j *domain.Job) {
+				j.Type = types[i%3]
+				j.Status = statuses[i%3]
+				j.Priority = i % 2 // empates para probar el desempate por id
+				j.CreatedAt = at(i)
+				j.UpdatedAt = at(10 - i)
+				j.ScheduledAt = ptr(at(20 + (i*7)%6))
+			}))
+		}
+		insertJobs(t, uow, jobs...)
+
+		for _, field := range domain.JobSortFields {
+			for _, desc := range []bool{false, true} {
+				sortParam := field
+				if desc {
+					sortParam = "-" + field
+				}
+				t.Run(sortParam, func(t *testing.T) {
+					want := sortedJobs(jobs, field, desc)
+					got, err := uow.Job().Get(ctx, domain.JobSearchParams{SearchParams: utils.SearchParams{Sort: &sortParam}})
+					if err != nil {
+						t.Fatalf("get: %v", err)
+					}
+					if !slices.Equal(jobIDs(got), jobIDs(want)) {
+						t.Fatalf("got %s, want %s", describe(jobIDs(got)), describe(jobIDs(want)))
+					}
+				})
+			}
+		}
+
+		t.Run("DefaultIsCreatedAt", func(t *testing.T) {
+			got, err := uow.Job().Get(ctx, domain.JobSearchParams{})
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			if want := sortedJobs(jobs, "created_at", false); !slices.Equal(jobIDs(got), jobIDs(want)) {
+				t.Fatalf("got %s, want %s", describe(jobIDs(got)), describe(jobIDs(want)))
+			}
+		})
+
+		t.Run("UnknownField", func(t *testing.T) {
+			_, err := uow.Job().Get(ctx, domain.JobSearchParams{SearchParams: utils.SearchParams{Sort: ptr("callback_url")}})
+			expectErr(t, err, domain.ErrInvalid, "sort by unknown field")
+		})
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		uow := newUoW(t)
+		var jobs []domain.Job
+		for i := range 7 {
+			jobs = append(jobs, newJob(func(j *domain.Job) {
+				j.CreatedAt = at(i)
+				j.Priority = i % 3
+				// Mitad con scheduled_at NULL: van después de los programados
+				if i%2 == 0 {
+					j.ScheduledAt = ptr(at(10 - i))
+				}
+			}))
+		}
+		insertJobs(t, uow, jobs...)
+
+		t.Run("Pages", func(t *testing.T) {
+			want := sortedJobs(jobs, "created_at", false)
+			var got []domain.Job
+			for page := uint(1); page <= 3; page++ {
+				chunk, err := uow.Job().Get(ctx, domain.JobSearchParams{SearchParams: utils.SearchParams{Page: &page, Limit: ptr(uint(3))}})
+				if err != nil {
+					t.Fatalf("page %d: %v", page, err)
+				}
+				if wantLen := min(3, len(want)-int(page-1)*3); len(chunk) != wantLen {
+					t.Fatalf("page %d: got %d jobs, want %d", page, len(chunk), wantLen)
+				}
+				got = append(got, chunk...)
+			}
+			if !slices.Equal(jobIDs(got), jobIDs(want)) {
+				t.Fatalf("got %s, want %s", describe(jobIDs(got)), describe(jobIDs(want)))
+			}
+		})
+
+		for _, sortParam := range []string{"created_at", "-created_at", "priority", "-priority", "scheduled_at", "-scheduled_at"} {
+			t.Run("Cursor_"+sortParam, func(t *testing.T) {
+				field, desc := utils.ParseSort(sortParam)
+				want := sortedJobs(jobs, field, desc)
+
+				var got []domain.Job
+				var cursor *string
+				for range len(jobs) {
+					chunk, err := uow.Job().Get(ctx, domain.JobSearchParams{SearchParams: utils.SearchParams{
+						Sort:   &sortParam,
+						Limit:  ptr(uint(2)),
+						Cursor: cursor,
+					}})
+					if err != nil {
+						t.Fatalf("get: %v", err)
+					}
+					got = append(got, chunk...)
+					if len(chunk) < 2 {
+						break
+					}
+					last := chunk[len(chunk)-1]
+					cursor = ptr(utils.EncodeCursor(utils.Cursor{Sort: sortParam, Value: last.SortValue(field), ID: last.ID.String()}))
+				}
+				if !slices.Equal(jobIDs(got), jobIDs(want)) {
+					t.Fatalf("got %s, want %s", describe(jobIDs(got)), describe(jobIDs(want)))
+				}
+			})
+		}
+
+		t.Run("InvalidCursor", func(t *testing.T) {
+			_, err := uow.Job().Get(ctx, domain.JobSearchParams{SearchParams: utils.SearchParams{Cursor: ptr("not-a-cursor")}})
+			expectErr(t, err, domain.ErrInvalid, "get with an invalid cursor")
+		})
+
+		// El valor del cursor es de la columna con la que se generó: con
+		// otro orden no se puede comparar
+		t.Run("CursorFromOtherSort", func(t *testing.T) {
+			first := sortedJobs(jobs, "created_at", false)[0]
+			cursor := utils.EncodeCursor(utils.Cursor{Sort: "created_at", Value: first.SortValue("created_at"), ID: first.ID.String()})
+			for _, sortParam := range []string{"priority", "-created_at"} {
+				_, err := uow.Job().Get(ctx, domain.JobSearchParams{SearchParams: utils.SearchParams{Sort: &sortParam, Cursor: &cursor}})
+				expectErr(t, err, domain.ErrInvalid, "get with a cursor from sort created_at and sort "+sortParam)
+			}
+		})
+	})
+
+	t.Run("PerTenantLimit", func(t *testing.T) {
+		uow := newUoW(t)
+		var acme []domain.Job
+		for i := range 3 {
+			acme = append(acme, newJob(func(j *domain.Job) { j.Tenant = "acme"; j.CreatedAt = at(i) }))
+		}
+		globex := newJob(func(j *domain.Job) { j.Tenant = "globex"; j.CreatedAt = at(5) })
+		insertJobs(t, uow, append(acme, globex)...)
+
+		jobs, err := uow.Job().Get(ctx, domain.JobSearchParams{PerTenantLimit: ptr(uint(2))})
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		// Los más antiguos de cada tenant
+		if want := []uuid.UUID{acme[0].ID, acme[1].ID, globex.ID}; !sameIDs(jobIDs(jobs), want) {
+			t.Fatalf("got %s, want %s", describe(jobIDs(jobs)), describe(want))
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob()
+		queued := newJob(func(j *domain.Job) { j.Status = domain.JobStatusQueued })
+		insertJobs(t, uow, job, queued)
+
+		current := getJob(t, uow, job.ID)
+		edited := current
+		edited.Priority = 9
+		edited.Payload = []byte(`{"edited":true}`)
+		edited.UpdatedAt = at(60)
+		if err := uow.Job().Update(ctx, edited, current.UpdatedAt); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+		got := getJob(t, uow, job.ID)
+		if got.Priority != 9 || !jsonEqual(got.Payload, edited.Payload) || !got.UpdatedAt.Equal(at(60)) {
+			t.Fatalf("update not applied: %+v", got)
+		}
+
+		// La versión leída antes del update ya no vale
+		expectErr(t, uow.Job().Update(ctx, edited, current.UpdatedAt), domain.ErrPreconditionFailed, "update with a stale version")
+
+		// Un job encolado ya no es editable
+		q := getJob(t, uow, queued.ID)
+		expectErr(t, uow.Job().Update(ctx, q, q.UpdatedAt), domain.ErrPreconditionFailed, "update a queued job")
+	})
+
+	t.Run("QueuedTenants", func(t *testing.T) {
+		uow := newUoW(t)
+		insertJobs(t, uow,
+			newJob(func(j *domain.Job) { j.Tenant = "acme"; j.Status = domain.JobStatusQueued }),
+			newJob(func(j *domain.Job) { j.Tenant = "acme"; j.Status = domain.JobStatusQueued }),
+			newJob(func(j *domain.Job) { j.Tenant = "globex"; j.Status = domain.JobStatusPending }),
+			newJob(func(j *domain.Job) { j.Status = domain.JobStatusQueued }),
+			// Solo un reintento en la cola: el tenant se consume igual
+			newJob(func(j *domain.Job) { j.Tenant = "initech"; j.Status = domain.JobStatusFailed }),
+			// El reintento diferido no está en la cola hasta que lo publique el dispatcher
+			newJob(func(j *domain.Job) { j.Tenant = "umbrella"; j.Status = domain.JobStatusFailed; j.RetryDeferred = true }),
+		)
+
+		tenants, err := uow.Job().QueuedTenants(ctx)
+		if err != nil {
+			t.Fatalf("queued tenants: %v", err)
+		}
+		sort.Strings(tenants)
+		if want := []string{"acme", domain.DefaultTenant, "initech"}; !slices.Equal(tenants, want) {
+			t.Fatalf("got %v, want %v", tenants, want)
+		}
+	})
+}
+
+// sortedJobs ordena como los repositorios: por el campo y después por id,
+// ambos en la misma dirección.
+func sortedJobs(jobs []domain.Job, field string, desc bool) []domain.Job {
+	sorted := slices.Clone(jobs)
+	slices.SortStableFunc(sorted, func(a, b domain.Job) int {
+		c := compareJobField(a, b, field)
+		if c == 0 {
+			c = strings.Compare(a.ID.String(), b.ID.String())
+		}
+		if desc {
+			return -c
+		}
+		return c
+	})
+	return sorted
+}
+
+func compareJobField(a, b domain.Job, field string) int {
+	switch field {
+	case "updated_at":
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	case "scheduled_at":
+		// NULL ordena después de cualquier valor, como en Postgres
+		switch {
+		case a.ScheduledAt == nil && b.ScheduledAt == nil:
+			return 0
+		case a.ScheduledAt == nil:
+			return 1
+		case b.ScheduledAt == nil:
+			return -1
+		}
+		return a.ScheduledAt.Compare(*b.ScheduledAt)
+	case "priority":
+		return a.Priority - b.Priority
+	case "type":
+		return strings.Compare(a.Type, b.Type)
+	case "status":
+		return strings.Compare(string(a.Status), string(b.Status))
+	default:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	}
+}
@@ -0,0 +1,168 @@
+// Package portstest implements conformance suites for the adapters of the
+// ports in internal/core/ports.
+//
+// Each Test* function takes a *testing.T and a factory for the adapter under
+// test, and checks the behaviour the services rely on and the Postgres and
+// RabbitMQ adapters provide: status transitions, locks, search filters,
+// ordering, pagination, tenant scoping and queue delivery. An adapter runs
+// them from its own tests:
+//
+//	func TestConformance(t *testing.T) {
+//		portstest.TestRepositories(t, func(t *testing.T) ports.IUnitOfWork {
+//			return memory.NewDataStore(memory.NewStore(nil), nil)
+//		})
+//	}
+//
+// TestEventBroker and TestRabbitMQClient do the same for ports.IEventBroker
+// and ports.IRabbitMQClient.
+//
+// Factories must return an adapter over empty storage on every call, since
+// each subtest counts and orders everything it can see. Against Postgres that
+// means a fresh schema or truncated tables; against RabbitMQ, purged queues.
+package portstest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UnitOfWorkFactory devuelve un unit of work sobre un almacenamiento vacío.
+type UnitOfWorkFactory func(t *testing.T) ports.IUnitOfWork
+
+// TestRepositories corre las suites de todos los repositorios del unit of work.
+func TestRepositories(t *testing.T, newUoW UnitOfWorkFactory) {
+	t.Run("UnitOfWork", func(t *testing.T) { TestUnitOfWork(t, newUoW) })
+	t.Run("Job", func(t *testing.T) { TestJobRepository(t, newUoW) })
+	t.Run("JobBatch", func(t *testing.T) { TestJobBatchRepository(t, newUoW) })
+	t.Run("Attempt", func(t *testing.T) { TestAttemptRepository(t, newUoW) })
+	t.Run("Event", func(t *testing.T) { TestEventRepository(t, newUoW) })
+	t.Run("RateLimit", func(t *testing.T) { TestRateLimitRepository(t, newUoW) })
+	t.Run("ConcurrencyLimit", func(t *testing.T) { TestConcurrencyLimitRepository(t, newUoW) })
+	t.Run("CircuitBreaker", func(t *testing.T) { TestCircuitBreakerRepository(t, newUoW) })
+	t.Run("Webhook", func(t *testing.T) { TestWebhookRepositories(t, newUoW) })
+	t.Run("Stats", func(t *testing.T) { TestStatsRepository(t, newUoW) })
+	t.Run("APIKey", func(t *testing.T) { TestAPIKeyRepository(t, newUoW) })
+	t.Run("TenantQuota", func(t *testing.T) { TestTenantQuotaRepository(t, newUoW) })
+}
+
+// epoch es una hora fija en el pasado, sin fracciones de segundo para que los
+// timestamps vuelvan iguales a como se guardaron (postgres guarda microsegundos).
+var epoch = time.Now().UTC().Add(-24 * time.Hour).Truncate(time.Second)
+
+// at devuelve epoch más n minutos.
+func at(n int) time.Time {
+	return epoch.Add(time.Duration(n) * time.Minute)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// tenantCtx simula un request autenticado con una key del tenant.
+func tenantCtx(tenant string) context.Context {
+	return domain.WithAPIKey(context.Background(), &domain.APIKey{Tenant: tenant})
+}
+
+// newJob arma un job pending del tenant por defecto creado en at(0).
+func newJob(mods ...func(*domain.Job)) domain.Job {
+	created := at(0)
+	job := domain.Job{
+		ID:          uuid.New(),
+		Tenant:      domain.DefaultTenant,
+		Type:        "email",
+		CallbackURL: "https://example.com/callback",
+		Payload:     []byte(`{}`),
+		Status:      domain.JobStatusPending,
+		ScheduledAt: &created,
+		CreatedAt:   created,
+		UpdatedAt:   created,
+	}
+	for _, mod := range mods {
+		mod(&job)
+	}
+	return job
+}
+
+// insertJobs guarda los jobs fuera de una transacción.
+func insertJobs(t *testing.T, uow ports.IUnitOfWork, jobs ...domain.Job) {
+	t.Helper()
+	for _, job := range jobs {
+		if err := uow.Job().Insert(context.Background(), job); err != nil {
+			t.Fatalf("insert job %s: %v", job.ID, err)
+		}
+	}
+}
+
+// getJob lee el job sin scope de tenant.
+func getJob(t *testing.T, uow ports.IUnitOfWork, id uuid.UUID) domain.Job {
+	t.Helper()
+	job, err := uow.Job().GetOne(context.Background(), domain.JobSearchParams{ID: &id})
+	if err != nil {
+		t.Fatalf("get job %s: %v", id, err)
+	}
+	return *job
+}
+
+// atomic corre fn en una transacción y falla el test si devuelve error.
+func atomic(t *testing.T, uow ports.IUnitOfWork, fn ports.FAtomicCallback) {
+	t.Helper()
+	if err := uow.Atomic(context.Background(), fn); err != nil {
+		t.Fatalf("atomic: %v", err)
+	}
+}
+
+// jobIDs devuelve los ids de los jobs en orden.
+func jobIDs(jobs []domain.Job) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(jobs))
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+	return ids
+}
+
+// sameIDs compara los ids sin importar el orden.
+func sameIDs(got, want []uuid.UUID) bool {
+	a := slices.Clone(got)
+	b := slices.Clone(want)
+	cmp := func(x, y uuid.UUID) int { return strings.Compare(x.String(), y.String()) }
+	slices.SortFunc(a, cmp)
+	slices.SortFunc(b, cmp)
+	return slices.Equal(a, b)
+}
+
+// jsonEqual compara dos documentos JSON por valor: postgres normaliza los jsonb.
+func jsonEqual(a, b []byte) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// expectErr falla el test si err no es del tipo target.
+func expectErr(t *testing.T, err, target error, what string) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Fatalf("%s: got error %v, want %v", what, err, target)
+	}
+}
+
+// describe formatea ids para los mensajes de error.
+func describe(ids []uuid.UUID) string {
+	s := make([]string, 0, len(ids))
+	for _, id := range ids {
+		s = append(s, id.String()[:8])
+	}
+	return fmt.Sprint(s)
+}
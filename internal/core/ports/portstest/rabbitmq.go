@@ -0,0 +1,284 @@
+package portstest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RabbitMQFactory prepara colas vacías para el test y devuelve cómo abrir
+// clientes sobre ellas. Todos los clientes que abre comparten las colas,
+// como varios procesos conectados al mismo broker.
+type RabbitMQFactory func(t *testing.T) func() ports.IRabbitMQClient
+
+// consumer corre Consume de un cliente en una goroutine.
+type consumer struct {
+	client ports.IRabbitMQClient
+	msgs   chan domain.RabbitJobMessage
+	done   chan error
+}
+
+func startConsumer(client ports.IRabbitMQClient) *consumer {
+	c := &consumer{
+		client: client,
+		msgs:   make(chan domain.RabbitJobMessage, 100),
+		done:   make(chan error, 1),
+	}
+	go func() {
+		c.done <- client.Consume(func(msg domain.RabbitJobMessage) { c.msgs <- msg })
+	}()
+	return c
+}
+
+// receive espera n mensajes y falla si alguno no llega a tiempo.
+func (c *consumer) receive(t *testing.T, n int) []domain.RabbitJobMessage {
+	t.Helper()
+	var got []domain.RabbitJobMessage
+	for len(got) < n {
+		select {
+		case msg := <-c.msgs:
+			got = append(got, msg)
+		case <-time.After(deliveryTimeout):
+			t.Fatalf("received %d messages, want %d", len(got), n)
+		}
+	}
+	return got
+}
+
+// expectNothing falla si llega algún mensaje durante quietPeriod.
+func (c *consumer) expectNothing(t *testing.T) {
+	t.Helper()
+	select {
+	case msg := <-c.msgs:
+		t.Fatalf("unexpected message for job %s", msg.JobID)
+	case <-time.After(quietPeriod):
+	}
+}
+
+// stop cierra el cliente y espera a que Consume termine sin error.
+func (c *consumer) stop(t *testing.T) {
+	t.Helper()
+	if err := c.client.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	select {
+	case err := <-c.done:
+		if err != nil {
+			t.Fatalf("consume returned %v after close, want nil", err)
+		}
+	case <-time.After(deliveryTimeout):
+		t.Fatal("consume did not return after close")
+	}
+}
+
+func newMessages(tenant string, n int) []domain.RabbitJobMessage {
+	msgs := make([]domain.RabbitJobMessage, n)
+	for i := range msgs {
+		msgs[i] = domain.RabbitJobMessage{
+			JobID:       uuid.New(),
+			Tenant:      tenant,
+			Type:        "email",
+			CallbackURL: "https://example.com/callback",
+			Payload:     json.RawMessage(fmt.Sprintf(`{"n":%d}`, i)),
+			Attempt:     1,
+		}
+	}
+	return msgs
+}
+
+func publish(t *testing.T, client ports.IRabbitMQClient, msgs ...domain.RabbitJobMessage) {
+	t.Helper()
+	for _, msg := range msgs {
+		if err := client.Publish(msg); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+}
+
+func messageIDs(msgs []domain.RabbitJobMessage) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(msgs))
+	for _, m := range msgs {
+		ids = append(ids, m.JobID)
+	}
+	return ids
+}
+
+// TestRabbitMQClient checks the queue semantics the dispatcher and the worker
+// rely on: per-queue FIFO delivery without duplicates, tenant queues consumed
+// only after ConsumeTenant, messages surviving the client that published them
+// and a clean shutdown.
+func TestRabbitMQClient(t *testing.T, newFactory RabbitMQFactory) {
+	t.Run("PublishConsumeOrder", func(t *testing.T) {
+		connect := newFactory(t)
+		client := connect()
+		c := startConsumer(client)
+		defer c.stop(t)
+
+		msgs := newMessages(domain.DefaultTenant, 10)
+		publish(t, client, msgs...)
+
+		got := c.receive(t, len(msgs))
+		if !slices.Equal(messageIDs(got), messageIDs(msgs)) {
+			t.Fatalf("got %s, want publish order %s", describe(messageIDs(got)), describe(messageIDs(msgs)))
+		}
+		if !jsonEqual(got[3].Payload, msgs[3].Payload) || got[3].Type != "email" || got[3].Attempt != 1 {
+			t.Fatalf("message changed in transit: got %+v, want %+v", got[3], msgs[3])
+		}
+		c.expectNothing(t)
+	})
+
+	t.Run("PublishBeforeConsume", func(t *testing.T) {
+		connect := newFactory(t)
+		client := connect()
+		msgs := newMessages(domain.DefaultTenant, 3)
+		publish(t, client, msgs...)
+
+		c := startConsumer(client)
+		defer c.stop(t)
+		if got := c.receive(t, len(msgs)); !slices.Equal(messageIDs(got), messageIDs(msgs)) {
+			t.Fatalf("got %s, want %s", describe(messageIDs(got)), describe(messageIDs(msgs)))
+		}
+	})
+
+	t.Run("TenantQueues", func(t *testing.T) {
+		connect := newFactory(t)
+		client := connect()
+		c := startConsumer(client)
+		defer c.stop(t)
+
+		acme := newMessages("acme", 3)
+		base := newMessages(domain.DefaultTenant, 2)
+		publish(t, client, acme...)
+		publish(t, client, base...)
+
+		// Sin ConsumeTenant solo llega la cola base
+		if got := c.receive(t, len(base)); !slices.Equal(messageIDs(got), messageIDs(base)) {
+			t.Fatalf("got %s, want the base queue %s", describe(messageIDs(got)), describe(messageIDs(base)))
+		}
+		c.expectNothing(t)
+
+		for range 2 {
+			if err := client.ConsumeTenant("acme"); err != nil {
+				t.Fatalf("consume tenant: %v", err)
+			}
+		}
+		if got := c.receive(t, len(acme)); !slices.Equal(messageIDs(got), messageIDs(acme)) {
+			t.Fatalf("got %s, want the tenant queue %s", describe(messageIDs(got)), describe(messageIDs(acme)))
+		}
+		// ConsumeTenant repetido no duplica entregas
+		c.expectNothing(t)
+	})
+
+	t.Run("CompetingConsumers", func(t *testing.T) {
+		connect := newFactory(t)
+		first, second := startConsumer(connect()), startConsumer(connect())
+		defer first.stop(t)
+		defer second.stop(t)
+
+		msgs := newMessages(domain.DefaultTenant, 20)
+		publish(t, first.client, msgs...)
+
+		var got []uuid.UUID
+		for len(got) < len(msgs) {
+			select {
+			case msg := <-first.msgs:
+				got = append(got, msg.JobID)
+			case msg := <-second.msgs:
+				got = append(got, msg.JobID)
+			case <-time.After(deliveryTimeout):
+				t.Fatalf("received %d messages, want %d", len(got), len(msgs))
+			}
+		}
+		if !sameIDs(got, messageIDs(msgs)) {
+			t.Fatalf("got %s, want each message once: %s", describe(got), describe(messageIDs(msgs)))
+		}
+		first.expectNothing(t)
+		second.expectNothing(t)
+	})
+
+	t.Run("RedeliveryToNextClient", func(t *testing.T) {
+		connect := newFactory(t)
+		publisher := connect()
+		msgs := newMessages(domain.DefaultTenant, 3)
+		publish(t, publisher, msgs...)
+		if err := publisher.Close(); err != nil {
+			t.Fatalf("close: %v", err)
+		}
+
+		// Los mensajes que nadie consumió sobreviven al cliente que los publicó
+		c := startConsumer(connect())
+		defer c.stop(t)
+		if got := c.receive(t, len(msgs)); !slices.Equal(messageIDs(got), messageIDs(msgs)) {
+			t.Fatalf("got %s, want %s", describe(messageIDs(got)), describe(messageIDs(msgs)))
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		connect := newFactory(t)
+		client := connect()
+		c := startConsumer(client)
+		publish(t, client, newMessages(domain.DefaultTenant, 1)...)
+		c.receive(t, 1)
+		c.stop(t)
+
+		if err := client.Publish(newMessages(domain.DefaultTenant, 1)[0]); err == nil {
+			t.Fatal("publish after close succeeded")
+		}
+	})
+
+	t.Run("QueueDepth", func(t *testing.T) {
+		connect := newFactory(t)
+		client := connect()
+		inspector, ok := client.(ports.IQueueInspector)
+		if !ok {
+			_ = client.Close()
+			t.Skip("client does not implement ports.IQueueInspector")
+		}
+
+		publish(t, client, newMessages(domain.DefaultTenant, 3)...)
+		publish(t, client, newMessages("acme", 1)...)
+		base := waitDepth(t, inspector, context.Background(), func(d *domain.QueueDepth) bool {
+			return d.Messages == 3 && d.Consumers == 0
+		})
+		tenant := waitDepth(t, inspector, tenantCtx("acme"), func(d *domain.QueueDepth) bool {
+			return d.Messages == 1
+		})
+		if tenant.Name != base.Name+".acme" {
+			t.Fatalf("tenant queue: got %q, want %q", tenant.Name, base.Name+".acme")
+		}
+
+		c := startConsumer(client)
+		defer c.stop(t)
+		c.receive(t, 3)
+		waitDepth(t, inspector, context.Background(), func(d *domain.QueueDepth) bool {
+			return d.Messages == 0 && d.Consumers == 1
+		})
+	})
+}
+
+// waitDepth consulta la cola hasta que cumple cond; el broker puede tardar en
+// reflejar publicaciones y consumers.
+func waitDepth(t *testing.T, inspector ports.IQueueInspector, ctx context.Context, cond func(*domain.QueueDepth) bool) *domain.QueueDepth {
+	t.Helper()
+	deadline := time.Now().Add(deliveryTimeout)
+	for {
+		depth, err := inspector.QueueDepth(ctx)
+		if err != nil {
+			t.Fatalf("queue depth: %v", err)
+		}
+		if cond(depth) {
+			return depth
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("queue depth: got %+v", depth)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,170 @@
+package portstest
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"slices"
+	"testing"
+	"time"
+)
+
+// newRateLimit arma un bucket lleno que casi no se rellena durante el test.
+func newRateLimit(scope domain.RateLimitScope, key string, burst int) domain.RateLimit {
+	limit, err := domain.NewRateLimit(domain.RateLimitInput{
+		Scope:         scope,
+		Key:           key,
+		RatePerSecond: 0.001,
+		Burst:         burst,
+	}, at(0))
+	if err != nil {
+		panic(err)
+	}
+	return *limit
+}
+
+func rateLimitKeys(limits []domain.RateLimit) []domain.RateLimitKey {
+	keys := make([]domain.RateLimitKey, 0, len(limits))
+	for _, l := range limits {
+		keys = append(keys, domain.RateLimitKey{Scope: l.Scope, Key: l.Key})
+	}
+	return keys
+}
+
+// TestRateLimitRepository checks the bucket configuration and that Acquire
+// takes a token from every bucket or from none of them.
+func TestRateLimitRepository(t *testing.T, newUoW UnitOfWorkFactory) {
+	ctx := context.Background()
+
+	upsert := func(t *testing.T, uow ports.IUnitOfWork, limits ...domain.RateLimit) {
+		t.Helper()
+		for _, l := range limits {
+			if err := uow.RateLimit().Upsert(ctx, l); err != nil {
+				t.Fatalf("upsert %s/%s: %v", l.Scope, l.Key, err)
+			}
+		}
+	}
+	acquire := func(t *testing.T, uow ports.IUnitOfWork, keys ...domain.RateLimitKey) time.Duration {
+		t.Helper()
+		var wait time.Duration
+		atomic(t, uow, func(tx ports.IUnitOfWork) error {
+			var err error
+			wait, err = tx.RateLimit().Acquire(ctx, keys)
+			return err
+		})
+		return wait
+	}
+	tokens := func(t *testing.T, uow ports.IUnitOfWork, key domain.RateLimitKey) float64 {
+		t.Helper()
+		got, err := uow.RateLimit().Get(ctx, domain.RateLimitSearchParams{Scope: &key.Scope, Key: &key.Key})
+		if err != nil || len(got) != 1 {
+			t.Fatalf("get %s/%s: got %d (%v), want 1", key.Scope, key.Key, len(got), err)
+		}
+		return got[0].Tokens
+	}
+
+	t.Run("UpsertAndGet", func(t *testing.T) {
+		uow := newUoW(t)
+		email := newRateLimit(domain.RateLimitScopeType, "email", 5)
+		report := newRateLimit(domain.RateLimitScopeType, "report", 5)
+		host := newRateLimit(domain.RateLimitScopeHost, "example.com", 5)
+		upsert(t, uow, report, host, email)
+
+		cases := []struct {
+			name   string
+			params domain.RateLimitSearchParams
+			want   []domain.RateLimit
+		}{
+			{"AllSortedByScopeAndKey", domain.RateLimitSearchParams{}, []domain.RateLimit{host, email, report}},
+			{"Scope", domain.RateLimitSearchParams{Scope: ptr(domain.RateLimitScopeType)}, []domain.RateLimit{email, report}},
+			{"Key", domain.RateLimitSearchParams{Key: ptr("example.com")}, []domain.RateLimit{host}},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				got, err := uow.RateLimit().Get(ctx, c.params)
+				if err != nil {
+					t.Fatalf("get: %v", err)
+				}
+				if !slices.Equal(rateLimitKeys(got), rateLimitKeys(c.want)) {
+					t.Fatalf("got %v, want %v", rateLimitKeys(got), rateLimitKeys(c.want))
+				}
+			})
+		}
+
+		if err := uow.RateLimit().Delete(ctx, domain.RateLimitKey{Scope: host.Scope, Key: host.Key}); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		got, err := uow.RateLimit().Get(ctx, domain.RateLimitSearchParams{})
+		if err != nil || !slices.Equal(rateLimitKeys(got), rateLimitKeys([]domain.RateLimit{email, report})) {
+			t.Fatalf("after delete: got %v (%v)", rateLimitKeys(got), err)
+		}
+	})
+
+	t.Run("UpsertKeepsTokens", func(t *testing.T) {
+		uow := newUoW(t)
+		limit := newRateLimit(domain.RateLimitScopeType, "email", 5)
+		key := domain.RateLimitKey{Scope: limit.Scope, Key: limit.Key}
+		upsert(t, uow, limit)
+		for range 2 {
+			if wait := acquire(t, uow, key); wait != 0 {
+				t.Fatalf("acquire: got wait %v, want 0", wait)
+			}
+		}
+
+		// Reconfigurar el bucket no lo vuelve a llenar...
+		upsert(t, uow, newRateLimit(domain.RateLimitScopeType, "email", 10))
+		if got := tokens(t, uow, key); got < 3 || got >= 3.5 {
+			t.Fatalf("tokens after raising burst: got %v, want 3", got)
+		}
+		// ...pero los tokens nunca superan el nuevo burst
+		upsert(t, uow, newRateLimit(domain.RateLimitScopeType, "email", 1))
+		if got := tokens(t, uow, key); got != 1 {
+			t.Fatalf("tokens after lowering burst: got %v, want 1", got)
+		}
+	})
+
+	t.Run("Acquire", func(t *testing.T) {
+		uow := newUoW(t)
+		upsert(t, uow, newRateLimit(domain.RateLimitScopeType, "email", 2))
+		key := domain.RateLimitKey{Scope: domain.RateLimitScopeType, Key: "email"}
+
+		for i := range 2 {
+			if wait := acquire(t, uow, key); wait != 0 {
+				t.Fatalf("acquire %d: got wait %v, want 0", i, wait)
+			}
+		}
+		if wait := acquire(t, uow, key); wait <= 0 {
+			t.Fatalf("acquire on an empty bucket: got wait %v, want > 0", wait)
+		}
+
+		unknown := domain.RateLimitKey{Scope: domain.RateLimitScopeHost, Key: "unknown.example.com"}
+		if wait := acquire(t, uow, unknown); wait != 0 {
+			t.Fatalf("acquire without limits: got wait %v, want 0", wait)
+		}
+
+		if _, err := uow.RateLimit().Acquire(ctx, []domain.RateLimitKey{key}); err == nil {
+			t.Fatal("acquire outside a transaction succeeded")
+		}
+	})
+
+	t.Run("AcquireAllOrNothing", func(t *testing.T) {
+		uow := newUoW(t)
+		upsert(t, uow,
+			newRateLimit(domain.RateLimitScopeType, "email", 1),
+			newRateLimit(domain.RateLimitScopeTenant, "acme", 5),
+		)
+		typeKey := domain.RateLimitKey{Scope: domain.RateLimitScopeType, Key: "email"}
+		tenantKey := domain.RateLimitKey{Scope: domain.RateLimitScopeTenant, Key: "acme"}
+
+		if wait := acquire(t, uow, typeKey, tenantKey); wait != 0 {
+			t.Fatalf("first acquire: got wait %v, want 0", wait)
+		}
+		if wait := acquire(t, uow, tenantKey, typeKey); wait <= 0 {
+			t.Fatalf("second acquire: got wait %v, want > 0", wait)
+		}
+		// El bucket del tenant no pierde el token aunque el del tipo estuviera vacío
+		if got := tokens(t, uow, tenantKey); got < 4 || got >= 4.5 {
+			t.Fatalf("tenant tokens: got %v, want 4", got)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package portstest
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"slices"
+	"testing"
+)
+
+// TestStatsRepository checks the aggregates behind /stats: job counts, the
+// oldest ready job, throughput and duration percentiles, all scoped by tenant.
+func TestStatsRepository(t *testing.T, newUoW UnitOfWorkFactory) {
+	ctx := context.Background()
+
+	t.Run("CountJobs", func(t *testing.T) {
+		uow := newUoW(t)
+		insertJobs(t, uow,
+			newJob(),
+			newJob(),
+			newJob(func(j *domain.Job) { j.Status = domain.JobStatusFailed }),
+			newJob(func(j *domain.Job) { j.Type = "report"; j.Tenant = "acme" }),
+		)
+
+		got, err := uow.Stats().CountJobs(ctx)
+		if err != nil {
+			t.Fatalf("count jobs: %v", err)
+		}
+		want := []domain.JobCount{
+			{Type: "email", Status: domain.JobStatusFailed, Count: 1},
+			{Type: "email", Status: domain.JobStatusPending, Count: 2},
+			{Type: "report", Status: domain.JobStatusPending, Count: 1},
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+
+		got, err = uow.Stats().CountJobs(tenantCtx("acme"))
+		if err != nil || !slices.Equal(got, want[2:]) {
+			t.Fatalf("scoped count: got %v (%v), want %v", got, err, want[2:])
+		}
+	})
+
+	t.Run("OldestPendingSince", func(t *testing.T) {
+		uow := newUoW(t)
+		none, err := uow.Stats().OldestPendingSince(ctx, at(10))
+		if err != nil || none != nil {
+			t.Fatalf("empty store: got %v (%v), want nil", none, err)
+		}
+
+		scheduled := func(minute int, mods ...func(*domain.Job)) domain.Job {
+			return newJob(append(mods, func(j *domain.Job) { j.ScheduledAt = ptr(at(minute)) })...)
+		}
+		insertJobs(t, uow,
+			scheduled(5),
+			scheduled(3, func(j *domain.Job) { j.Tenant = "acme" }),
+			scheduled(1, func(j *domain.Job) { j.Status = domain.JobStatusQueued }),
+			scheduled(20),
+		)
+
+		got, err := uow.Stats().OldestPendingSince(ctx, at(10))
+		if err != nil || got == nil || !got.Equal(at(3)) {
+			t.Fatalf("got %v (%v), want %v", got, err, at(3))
+		}
+		got, err = uow.Stats().OldestPendingSince(tenantCtx(domain.DefaultTenant), at(10))
+		if err != nil || got == nil || !got.Equal(at(5)) {
+			t.Fatalf("scoped: got %v (%v), want %v", got, err, at(5))
+		}
+	})
+
+	t.Run("ThroughputAndDurations", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob()
+		acme := newJob(func(j *domain.Job) { j.Tenant = "acme" })
+		insertJobs(t, uow, job, acme)
+
+		failed := func(a *domain.Attempt) { a.Status = domain.AttemptStatusFailed }
+		duration := func(ms int64) func(*domain.Attempt) {
+			return func(a *domain.Attempt) { a.DurationMs = ptr(ms) }
+		}
+		insertAttempts(t, uow,
+			// Anterior a la ventana
+			newAttempt(job.ID, 1, 0, duration(10000)),
+			newAttempt(job.ID, 2, 10, duration(100)),
+			newAttempt(job.ID, 3, 11, duration(200), failed),
+			newAttempt(job.ID, 4, 12, duration(300)),
+			newAttempt(job.ID, 5, 13, duration(400), failed),
+			newAttempt(acme.ID, 1, 14, duration(500)),
+		)
+		since := at(5)
+
+		throughput, err := uow.Stats().Throughput(ctx, since)
+		if err != nil {
+			t.Fatalf("throughput: %v", err)
+		}
+		if throughput.Succeeded != 3 || throughput.Failed != 2 || !throughput.WindowStartedAt.Equal(since) {
+			t.Fatalf("throughput: got %+v, want 3 succeeded and 2 failed", throughput)
+		}
+		scoped, err := uow.Stats().Throughput(tenantCtx("acme"), since)
+		if err != nil || scoped.Succeeded != 1 || scoped.Failed != 0 {
+			t.Fatalf("scoped throughput: got %+v (%v), want 1 succeeded", scoped, err)
+		}
+
+		durations, err := uow.Stats().Durations(ctx, since)
+		if err != nil {
+			t.Fatalf("durations: %v", err)
+		}
+		// Percentiles interpolados entre 100, 200, 300, 400 y 500 ms
+		if durations.Samples != 5 || durations.P50Ms == nil || *durations.P50Ms != 300 || durations.P95Ms == nil || *durations.P95Ms != 480 {
+			t.Fatalf("durations: got %+v, want 5 samples, p50 300 and p95 480", durations)
+		}
+
+		empty, err := uow.Stats().Durations(ctx, at(60))
+		if err != nil || empty.Samples != 0 || empty.P50Ms != nil || empty.P95Ms != nil {
+			t.Fatalf("durations without samples: got %+v (%v)", empty, err)
+		}
+	})
+}
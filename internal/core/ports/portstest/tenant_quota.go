@@ -0,0 +1,91 @@
+package portstest
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"testing"
+)
+
+// TestTenantQuotaRepository checks that Pending counts the pending and queued
+// jobs of the tenant and that Reserve enforces MaxPendingJobs.
+func TestTenantQuotaRepository(t *testing.T, newUoW UnitOfWorkFactory) {
+	ctx := context.Background()
+
+	newQuota := func(t *testing.T, tenant string, max int) domain.TenantQuota {
+		t.Helper()
+		quota, err := domain.NewTenantQuota(domain.TenantQuotaInput{Tenant: tenant, MaxPendingJobs: max}, at(0))
+		if err != nil {
+			t.Fatalf("new tenant quota: %v", err)
+		}
+		return *quota
+	}
+	reserve := func(t *testing.T, uow ports.IUnitOfWork, tenant string, n int) bool {
+		t.Helper()
+		var ok bool
+		atomic(t, uow, func(tx ports.IUnitOfWork) error {
+			var err error
+			ok, err = tx.TenantQuota().Reserve(ctx, tenant, n)
+			return err
+		})
+		return ok
+	}
+
+	t.Run("UpsertGetDelete", func(t *testing.T) {
+		uow := newUoW(t)
+		for _, q := range []domain.TenantQuota{newQuota(t, "globex", 5), newQuota(t, "acme", 1), newQuota(t, "acme", 3)} {
+			if err := uow.TenantQuota().Upsert(ctx, q); err != nil {
+				t.Fatalf("upsert: %v", err)
+			}
+		}
+		insertJobs(t, uow,
+			newJob(func(j *domain.Job) { j.Tenant = "acme" }),
+			newJob(func(j *domain.Job) { j.Tenant = "acme"; j.Status = domain.JobStatusQueued }),
+			newJob(func(j *domain.Job) { j.Tenant = "acme"; j.Status = domain.JobStatusCompleted }),
+		)
+
+		got, err := uow.TenantQuota().Get(ctx, domain.TenantQuotaSearchParams{})
+		if err != nil || len(got) != 2 {
+			t.Fatalf("get: got %d (%v), want 2", len(got), err)
+		}
+		if got[0].Tenant != "acme" || got[0].MaxPendingJobs != 3 || got[0].Pending != 2 || got[1].Tenant != "globex" || got[1].Pending != 0 {
+			t.Fatalf("got %+v, want acme (max 3, 2 pending) then globex", got)
+		}
+
+		scoped, err := uow.TenantQuota().Get(tenantCtx("globex"), domain.TenantQuotaSearchParams{})
+		if err != nil || len(scoped) != 1 || scoped[0].Tenant != "globex" {
+			t.Fatalf("scoped get: got %+v (%v), want only globex", scoped, err)
+		}
+
+		if err := uow.TenantQuota().Delete(ctx, "globex"); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		expectErr(t, uow.TenantQuota().Delete(ctx, "globex"), domain.ErrNotFound, "delete twice")
+	})
+
+	t.Run("Reserve", func(t *testing.T) {
+		uow := newUoW(t)
+		if !reserve(t, uow, "acme", 1000) {
+			t.Fatal("reserve without quota was refused")
+		}
+
+		if err := uow.TenantQuota().Upsert(ctx, newQuota(t, "acme", 3)); err != nil {
+			t.Fatalf("upsert: %v", err)
+		}
+		insertJobs(t, uow,
+			newJob(func(j *domain.Job) { j.Tenant = "acme" }),
+			newJob(func(j *domain.Job) { j.Tenant = "acme"; j.Status = domain.JobStatusDead }),
+			newJob(),
+		)
+
+		if !reserve(t, uow, "acme", 2) {
+			t.Fatal("reserve up to the quota was refused")
+		}
+		if reserve(t, uow, "acme", 3) {
+			t.Fatal("reserve over the quota was granted")
+		}
+		if _, err := uow.TenantQuota().Reserve(ctx, "acme", 1); err == nil {
+			t.Fatal("reserve outside a transaction succeeded")
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package portstest
+
+import (
+	"context"
+	"errors"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestUnitOfWork checks the transactional semantics of Atomic: writes are
+// visible inside the transaction, kept on commit and undone on error or panic,
+// and a nested Atomic joins the outer transaction.
+func TestUnitOfWork(t *testing.T, newUoW UnitOfWorkFactory) {
+	ctx := context.Background()
+	errAbort := errors.New("abort")
+
+	t.Run("Commit", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob()
+
+		atomic(t, uow, func(tx ports.IUnitOfWork) error {
+			if err := tx.Job().Insert(ctx, job); err != nil {
+				return err
+			}
+			// Dentro de la transacción se ven sus propias escrituras
+			if _, err := tx.Job().GetOne(ctx, domain.JobSearchParams{ID: &job.ID}); err != nil {
+				t.Errorf("job not visible inside its transaction: %v", err)
+			}
+			return tx.Event().Insert(ctx, domain.NewJobSucceededEvent(job.ID, at(0)))
+		})
+
+		getJob(t, uow, job.ID)
+		events, err := uow.Event().Get(ctx, domain.EventSearchParams{JobID: &job.ID})
+		if err != nil || len(events) != 1 {
+			t.Fatalf("events after commit: got %d (%v), want 1", len(events), err)
+		}
+	})
+
+	t.Run("RollbackOnError", func(t *testing.T) {
+		uow := newUoW(t)
+		existing := newJob()
+		insertJobs(t, uow, existing)
+		job := newJob()
+
+		err := uow.Atomic(ctx, func(tx ports.IUnitOfWork) error {
+			if err := tx.Job().Insert(ctx, job); err != nil {
+				return err
+			}
+			if err := tx.Event().Insert(ctx, domain.NewJobSucceededEvent(job.ID, at(0))); err != nil {
+				return err
+			}
+			if err := tx.Job().MarkQueued(ctx, existing.ID); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		expectErr(t, err, errAbort, "atomic")
+
+		_, err = uow.Job().GetOne(ctx, domain.JobSearchParams{ID: &job.ID})
+		expectErr(t, err, domain.ErrNotFound, "job inserted in a rolled back transaction")
+		if got := getJob(t, uow, existing.ID).Status; got != domain.JobStatusPending {
+			t.Fatalf("status after rollback: got %s, want pending", got)
+		}
+		events, err := uow.Event().Get(ctx, domain.EventSearchParams{})
+		if err != nil || len(events) != 0 {
+			t.Fatalf("events after rollback: got %d (%v), want 0", len(events), err)
+		}
+	})
+
+	t.Run("RollbackOnPanic", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob()
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("Atomic swallowed the panic")
+				}
+			}()
+			_ = uow.Atomic(ctx, func(tx ports.IUnitOfWork) error {
+				if err := tx.Job().Insert(ctx, job); err != nil {
+					return err
+				}
+				panic("boom")
+			})
+		}()
+
+		_, err := uow.Job().GetOne(ctx, domain.JobSearchParams{ID: &job.ID})
+		expectErr(t, err, domain.ErrNotFound, "job inserted before a panic")
+	})
+
+	t.Run("NestedJoinsOuter", func(t *testing.T) {
+		uow := newUoW(t)
+		outer, inner := newJob(), newJob()
+
+		err := uow.Atomic(ctx, func(tx ports.IUnitOfWork) error {
+			if err := tx.Job().Insert(ctx, outer); err != nil {
+				return err
+			}
+			if err := tx.Atomic(ctx, func(tx ports.IUnitOfWork) error {
+				return tx.Job().Insert(ctx, inner)
+			}); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		expectErr(t, err, errAbort, "atomic")
+
+		// El Atomic anidado no hace commit propio: el rollback externo lo deshace
+		for _, id := range []uuid.UUID{outer.ID, inner.ID} {
+			_, err := uow.Job().GetOne(ctx, domain.JobSearchParams{ID: &id})
+			expectErr(t, err, domain.ErrNotFound, "job after outer rollback")
+		}
+	})
+}
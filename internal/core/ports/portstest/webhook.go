@@ -0,0 +1,312 @@
+package portstest
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newSubscription(t *testing.T, input domain.WebhookSubscriptionInput) domain.WebhookSubscription {
+	t.Helper()
+	if input.URL == "" {
+		input.URL = "https://hooks.example.com/jobs"
+	}
+	s, err := domain.NewWebhookSubscription(input, at(0))
+	if err != nil {
+		t.Fatalf("new webhook subscription: %v", err)
+	}
+	return *s
+}
+
+func insertSubscriptions(t *testing.T, uow ports.IUnitOfWork, subscriptions ...domain.WebhookSubscription) {
+	t.Helper()
+	for _, s := range subscriptions {
+		if err := uow.WebhookSubscription().Insert(context.Background(), s); err != nil {
+			t.Fatalf("insert subscription: %v", err)
+		}
+	}
+}
+
+// publishEvent inserta el evento y encola sus entregas en la misma transacción,
+// como hace el servicio.
+func publishEvent(t *testing.T, uow ports.IUnitOfWork, event domain.Event) {
+	t.Helper()
+	atomic(t, uow, func(tx ports.IUnitOfWork) error {
+		if err := tx.Event().Insert(context.Background(), event); err != nil {
+			return err
+		}
+		return tx.WebhookDelivery().Enqueue(context.Background(), event.ID)
+	})
+}
+
+func subscriptionIDs(subscriptions []domain.WebhookSubscription) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+func deliverySubscriptionIDs(deliveries []domain.WebhookDelivery) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(deliveries))
+	for _, d := range deliveries {
+		ids = append(ids, d.SubscriptionID)
+	}
+	return ids
+}
+
+// TestWebhookRepositories checks subscriptions and deliveries: which
+// subscriptions an event matches, the claim lease and the delivery filters.
+func TestWebhookRepositories(t *testing.T, newUoW UnitOfWorkFactory) {
+	ctx := context.Background()
+
+	t.Run("Subscriptions", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob(func(j *domain.Job) { j.Tenant = "acme" })
+		insertJobs(t, uow, job)
+		global := newSubscription(t, domain.WebhookSubscriptionInput{Tenant: "acme"})
+		ofJob := newSubscription(t, domain.WebhookSubscriptionInput{Tenant: "acme", JobID: &job.ID})
+		ofType := newSubscription(t, domain.WebhookSubscriptionInput{JobType: ptr("report")})
+		insertSubscriptions(t, uow, global, ofJob, ofType)
+
+		cases := []struct {
+			name   string
+			ctx    context.Context
+			params domain.WebhookSubscriptionSearchParams
+			want   []uuid.UUID
+		}{
+			{"All", ctx, domain.WebhookSubscriptionSearchParams{}, []uuid.UUID{global.ID, ofJob.ID, ofType.ID}},
+			{"ID", ctx, domain.WebhookSubscriptionSearchParams{ID: &ofJob.ID}, []uuid.UUID{ofJob.ID}},
+			{"Tenant", ctx, domain.WebhookSubscriptionSearchParams{Tenant: ptr("acme")}, []uuid.UUID{global.ID, ofJob.ID}},
+			{"JobID", ctx, domain.WebhookSubscriptionSearchParams{JobID: &job.ID}, []uuid.UUID{ofJob.ID}},
+			{"JobType", ctx, domain.WebhookSubscriptionSearchParams{JobType: ptr("report")}, []uuid.UUID{ofType.ID}},
+			{"TenantScope", tenantCtx(domain.DefaultTenant), domain.WebhookSubscriptionSearchParams{}, []uuid.UUID{ofType.ID}},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				got, err := uow.WebhookSubscription().Get(c.ctx, c.params)
+				if err != nil {
+					t.Fatalf("get: %v", err)
+				}
+				if !sameIDs(subscriptionIDs(got), c.want) {
+					t.Fatalf("got %s, want %s", describe(subscriptionIDs(got)), describe(c.want))
+				}
+			})
+		}
+
+		t.Run("DeleteDeactivates", func(t *testing.T) {
+			if err := uow.WebhookSubscription().Delete(ctx, global.ID); err != nil {
+				t.Fatalf("delete: %v", err)
+			}
+			got, err := uow.WebhookSubscription().Get(ctx, domain.WebhookSubscriptionSearchParams{ID: &global.ID})
+			if err != nil || len(got) != 0 {
+				t.Fatalf("get deleted: got %d (%v), want 0", len(got), err)
+			}
+			expectErr(t, uow.WebhookSubscription().Delete(ctx, global.ID), domain.ErrNotFound, "delete twice")
+			expectErr(t, uow.WebhookSubscription().Delete(ctx, uuid.New()), domain.ErrNotFound, "delete missing")
+		})
+	})
+
+	t.Run("Enqueue", func(t *testing.T) {
+		uow := newUoW(t)
+		email := newJob(func(j *domain.Job) { j.Tenant = "acme" })
+		report := newJob(func(j *domain.Job) { j.Tenant = "acme"; j.Type = "report" })
+		other := newJob(func(j *domain.Job) { j.Tenant = "globex" })
+		insertJobs(t, uow, email, report, other)
+
+		global := newSubscription(t, domain.WebhookSubscriptionInput{Tenant: "acme"})
+		ofJob := newSubscription(t, domain.WebhookSubscriptionInput{Tenant: "acme", JobID: &email.ID})
+		ofType := newSubscription(t, domain.WebhookSubscriptionInput{Tenant: "acme", JobType: ptr("report")})
+		onlyDead := newSubscription(t, domain.WebhookSubscriptionInput{Tenant: "acme", EventTypes: []domain.EventType{domain.EventJobDead}})
+		deleted := newSubscription(t, domain.WebhookSubscriptionInput{Tenant: "acme"})
+		insertSubscriptions(t, uow, global, ofJob, ofType, onlyDead, deleted)
+		if err := uow.WebhookSubscription().Delete(ctx, deleted.ID); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+
+		cases := []struct {
+			name  string
+			event domain.Event
+			want  []uuid.UUID
+		}{
+			{"JobSubscription", domain.NewJobSucceededEvent(email.ID, at(0)), []uuid.UUID{global.ID, ofJob.ID}},
+			{"TypeSubscription", domain.NewJobFailedEvent(report.ID, "boom", at(0)), []uuid.UUID{global.ID, ofType.ID}},
+			{"EventTypes", domain.NewJobDeadEvent(report.ID, at(0)), []uuid.UUID{global.ID, ofType.ID, onlyDead.ID}},
+			{"OtherTenant", domain.NewJobSucceededEvent(other.ID, at(0)), nil},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				publishEvent(t, uow, c.event)
+				got, err := uow.WebhookDelivery().Get(ctx, domain.WebhookDeliverySearchParams{})
+				if err != nil {
+					t.Fatalf("get: %v", err)
+				}
+				var matched []domain.WebhookDelivery
+				for _, d := range got {
+					if d.EventID == c.event.ID {
+						matched = append(matched, d)
+					}
+				}
+				if !sameIDs(deliverySubscriptionIDs(matched), c.want) {
+					t.Fatalf("got %s, want %s", describe(deliverySubscriptionIDs(matched)), describe(c.want))
+				}
+				for _, d := range matched {
+					if d.Status != domain.WebhookDeliveryPending || d.JobID != c.event.JobID || d.EventType != c.event.Type ||
+						d.MaxAttempts != domain.DefaultWebhookMaxAttempts {
+						t.Fatalf("delivery: got %+v", d)
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("ClaimAndUpdate", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob()
+		insertJobs(t, uow, job)
+		subscription := newSubscription(t, domain.WebhookSubscriptionInput{Secret: "s3cret"})
+		insertSubscriptions(t, uow, subscription)
+		event := domain.NewJobSucceededEvent(job.ID, at(0))
+		publishEvent(t, uow, event)
+
+		claimed, err := uow.WebhookDelivery().Claim(ctx, 10, time.Hour)
+		if err != nil || len(claimed) != 1 {
+			t.Fatalf("claim: got %d (%v), want 1", len(claimed), err)
+		}
+		pending := claimed[0]
+		if pending.Event.ID != event.ID || pending.Secret != "s3cret" || pending.Delivery.SubscriptionID != subscription.ID {
+			t.Fatalf("claimed %+v", pending)
+		}
+
+		// El lease saca la entrega de los siguientes Claim
+		again, err := uow.WebhookDelivery().Claim(ctx, 10, time.Hour)
+		if err != nil || len(again) != 0 {
+			t.Fatalf("claim during the lease: got %d (%v), want 0", len(again), err)
+		}
+
+		delivered := pending.Delivery
+		delivered.Status = domain.WebhookDeliveryDelivered
+		delivered.Attempts = 1
+		delivered.NextAttemptAt = nil
+		delivered.LastHTTPStatus = ptr(204)
+		delivered.DeliveredAt = ptr(at(1))
+		delivered.UpdatedAt = at(1)
+		if err := uow.WebhookDelivery().Update(ctx, delivered); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+
+		got, err := uow.WebhookDelivery().Get(ctx, domain.WebhookDeliverySearchParams{ID: &delivered.ID})
+		if err != nil || len(got) != 1 {
+			t.Fatalf("get: got %d (%v), want 1", len(got), err)
+		}
+		d := got[0]
+		if d.Status != domain.WebhookDeliveryDelivered || d.Attempts != 1 || d.NextAttemptAt != nil ||
+			d.LastHTTPStatus == nil || *d.LastHTTPStatus != 204 || d.DeliveredAt == nil || !d.DeliveredAt.Equal(at(1)) {
+			t.Fatalf("got %+v", d)
+		}
+	})
+
+	t.Run("ClaimLimit", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob()
+		insertJobs(t, uow, job)
+		insertSubscriptions(t, uow, newSubscription(t, domain.WebhookSubscriptionInput{}))
+		for range 3 {
+			publishEvent(t, uow, domain.NewJobFailedEvent(job.ID, "boom", at(0)))
+		}
+
+		first, err := uow.WebhookDelivery().Claim(ctx, 2, time.Hour)
+		if err != nil || len(first) != 2 {
+			t.Fatalf("first claim: got %d (%v), want 2", len(first), err)
+		}
+		second, err := uow.WebhookDelivery().Claim(ctx, 2, time.Hour)
+		if err != nil || len(second) != 1 {
+			t.Fatalf("second claim: got %d (%v), want 1", len(second), err)
+		}
+		if second[0].Delivery.ID == first[0].Delivery.ID || second[0].Delivery.ID == first[1].Delivery.ID {
+			t.Fatal("a delivery was claimed twice")
+		}
+	})
+
+	t.Run("DeleteKillsPending", func(t *testing.T) {
+		uow := newUoW(t)
+		job := newJob()
+		insertJobs(t, uow, job)
+		subscription := newSubscription(t, domain.WebhookSubscriptionInput{})
+		insertSubscriptions(t, uow, subscription)
+		publishEvent(t, uow, domain.NewJobSucceededEvent(job.ID, at(0)))
+
+		if err := uow.WebhookSubscription().Delete(ctx, subscription.ID); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		got, err := uow.WebhookDelivery().Get(ctx, domain.WebhookDeliverySearchParams{SubscriptionID: &subscription.ID})
+		if err != nil || len(got) != 1 || got[0].Status != domain.WebhookDeliveryDead {
+			t.Fatalf("deliveries of a deleted subscription: got %+v (%v), want one dead", got, err)
+		}
+		claimed, err := uow.WebhookDelivery().Claim(ctx, 10, time.Hour)
+		if err != nil || len(claimed) != 0 {
+			t.Fatalf("claim: got %d (%v), want 0", len(claimed), err)
+		}
+	})
+
+	t.Run("DeliveryFilters", func(t *testing.T) {
+		uow := newUoW(t)
+		email := newJob(func(j *domain.Job) { j.Tenant = "acme" })
+		report := newJob()
+		insertJobs(t, uow, email, report)
+		acme := newSubscription(t, domain.WebhookSubscriptionInput{Tenant: "acme"})
+		global := newSubscription(t, domain.WebhookSubscriptionInput{})
+		insertSubscriptions(t, uow, acme, global)
+		publishEvent(t, uow, domain.NewJobSucceededEvent(email.ID, at(0)))
+		publishEvent(t, uow, domain.NewJobFailedEvent(report.ID, "boom", at(0)))
+		publishEvent(t, uow, domain.NewJobDeadEvent(report.ID, at(0)))
+
+		all, err := uow.WebhookDelivery().Get(ctx, domain.WebhookDeliverySearchParams{})
+		if err != nil || len(all) != 3 {
+			t.Fatalf("get all: got %d (%v), want 3", len(all), err)
+		}
+		dead := domain.WebhookDeliveryDead
+		for _, d := range all {
+			if d.EventType == domain.EventJobDead {
+				d.Status = dead
+				d.NextAttemptAt = nil
+				d.UpdatedAt = at(1)
+				if err := uow.WebhookDelivery().Update(ctx, d); err != nil {
+					t.Fatalf("update: %v", err)
+				}
+			}
+		}
+
+		cases := []struct {
+			name   string
+			ctx    context.Context
+			params domain.WebhookDeliverySearchParams
+			want   int
+		}{
+			{"All", ctx, domain.WebhookDeliverySearchParams{}, 3},
+			{"ID", ctx, domain.WebhookDeliverySearchParams{ID: &all[0].ID}, 1},
+			{"Tenant", ctx, domain.WebhookDeliverySearchParams{Tenant: ptr("acme")}, 1},
+			{"SubscriptionID", ctx, domain.WebhookDeliverySearchParams{SubscriptionID: &global.ID}, 2},
+			{"JobID", ctx, domain.WebhookDeliverySearchParams{JobID: &report.ID}, 2},
+			{"EventType", ctx, domain.WebhookDeliverySearchParams{EventType: ptr(domain.EventJobFailed)}, 1},
+			{"Status", ctx, domain.WebhookDeliverySearchParams{Status: &dead}, 1},
+			{"TenantScope", tenantCtx("acme"), domain.WebhookDeliverySearchParams{}, 1},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				got, err := uow.WebhookDelivery().Get(c.ctx, c.params)
+				if err != nil || len(got) != c.want {
+					t.Fatalf("get: got %d (%v), want %d", len(got), err, c.want)
+				}
+				count, err := uow.WebhookDelivery().Count(c.ctx, c.params)
+				if err != nil || count != c.want {
+					t.Fatalf("count: got %d (%v), want %d", count, err, c.want)
+				}
+			})
+		}
+	})
+}
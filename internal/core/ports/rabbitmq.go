@@ -5,5 +5,7 @@ import "job_scheduler_go_rabbitmq/internal/core/domain"
 type IRabbitMQClient interface {
 	Publish(msg domain.RabbitJobMessage) error
 	Consume(handler func(domain.RabbitJobMessage)) error
+	// ConsumeTenant suma la cola del tenant a las que atiende Consume. Es idempotente.
+	ConsumeTenant(tenant string) error
 	Close() error
 }
@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type IRateLimitHandler interface {
+	RegisterRouter(router *mux.Router)
+	List() http.HandlerFunc
+	Put() http.HandlerFunc
+	Delete() http.HandlerFunc
+}
+
+type IRateLimitService interface {
+	List(ctx context.Context, params domain.RateLimitSearchParams) ([]domain.RateLimit, error)
+	Put(ctx context.Context, input domain.RateLimitInput) (*domain.RateLimit, error)
+	Delete(ctx context.Context, key domain.RateLimitKey) error
+}
+
+type IRateLimitRepository interface {
+	Upsert(ctx context.Context, limit domain.RateLimit) error
+	Get(ctx context.Context, params domain.RateLimitSearchParams) ([]domain.RateLimit, error)
+	Delete(ctx context.Context, key domain.RateLimitKey) error
+
+	// Acquire toma un token de cada bucket configurado entre keys. Si alguno no
+	// tiene tokens no consume nada y devuelve cuánto hay que esperar.
+	// Debe ejecutarse dentro de una transacción.
+	Acquire(ctx context.Context, keys []domain.RateLimitKey) (time.Duration, error)
+}
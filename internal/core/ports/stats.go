@@ -0,0 +1,32 @@
+package ports
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type IStatsHandler interface {
+	RegisterRouter(router *mux.Router)
+	Get() http.HandlerFunc
+}
+
+type IStatsService interface {
+	Get(ctx context.Context) (*domain.JobStats, error)
+}
+
+type IStatsRepository interface {
+	CountJobs(ctx context.Context) ([]domain.JobCount, error)
+	// OldestPendingSince devuelve el scheduled_at del job pendiente listo para correr más antiguo.
+	OldestPendingSince(ctx context.Context, now time.Time) (*time.Time, error)
+	Throughput(ctx context.Context, since time.Time) (domain.ThroughputStats, error)
+	Durations(ctx context.Context, since time.Time) (domain.DurationStats, error)
+}
+
+// IQueueInspector consulta el estado de la cola del broker.
+type IQueueInspector interface {
+	QueueDepth(ctx context.Context) (*domain.QueueDepth, error)
+}
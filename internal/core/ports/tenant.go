@@ -0,0 +1,33 @@
+package ports
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type ITenantQuotaHandler interface {
+	RegisterRouter(router *mux.Router)
+	List() http.HandlerFunc
+	Put() http.HandlerFunc
+	Delete() http.HandlerFunc
+}
+
+type ITenantQuotaService interface {
+	List(ctx context.Context, params domain.TenantQuotaSearchParams) ([]domain.TenantQuota, error)
+	Put(ctx context.Context, input domain.TenantQuotaInput) (*domain.TenantQuota, error)
+	Delete(ctx context.Context, tenant string) error
+}
+
+type ITenantQuotaRepository interface {
+	Upsert(ctx context.Context, quota domain.TenantQuota) error
+	Get(ctx context.Context, params domain.TenantQuotaSearchParams) ([]domain.TenantQuota, error)
+	Delete(ctx context.Context, tenant string) error
+
+	// Reserve bloquea la cuota del tenant y devuelve false si n jobs más
+	// superarían MaxPendingJobs. Sin cuota configurada siempre devuelve true.
+	// Debe ejecutarse dentro de la transacción que inserta los jobs.
+	Reserve(ctx context.Context, tenant string, n int) (bool, error)
+}
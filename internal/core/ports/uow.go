@@ -6,8 +6,17 @@ type FAtomicCallback func(d IUnitOfWork) error
 
 type IUnitOfWork interface {
 	Job() IJobRepository
+	JobBatch() IJobBatchRepository
 	Attempt() IAttemptRepository
 	Event() IEventRepository
+	RateLimit() IRateLimitRepository
+	ConcurrencyLimit() IConcurrencyLimitRepository
+	CircuitBreaker() ICircuitBreakerRepository
+	WebhookSubscription() IWebhookSubscriptionRepository
+	WebhookDelivery() IWebhookDeliveryRepository
+	Stats() IStatsRepository
+	APIKey() IAPIKeyRepository
+	TenantQuota() ITenantQuotaRepository
 	// DeadLetter() IDeadLetterRepository
 	Atomic(ctx context.Context, fn FAtomicCallback) error
 }
@@ -0,0 +1,54 @@
+package ports
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/utils"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type IWebhookHandler interface {
+	RegisterRouter(router *mux.Router)
+	CreateSubscription() http.HandlerFunc
+	ListSubscriptions() http.HandlerFunc
+	DeleteSubscription() http.HandlerFunc
+	ListDeliveries() http.HandlerFunc
+}
+
+type IWebhookService interface {
+	CreateSubscription(ctx context.Context, input domain.WebhookSubscriptionInput) (*domain.CreatedWebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, params domain.WebhookSubscriptionSearchParams) ([]domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	ListDeliveries(ctx context.Context, params domain.WebhookDeliverySearchParams) (*utils.PaginatorWrapper[[]domain.WebhookDelivery], error)
+}
+
+type IWebhookDeliveryService interface {
+	// DeliverDue envía las entregas vencidas y devuelve cuántas procesó.
+	DeliverDue(ctx context.Context, limit int) (int, error)
+}
+
+type IWebhookSubscriptionRepository interface {
+	Insert(ctx context.Context, subscription domain.WebhookSubscription) error
+	Get(ctx context.Context, params domain.WebhookSubscriptionSearchParams) ([]domain.WebhookSubscription, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type IWebhookDeliveryRepository interface {
+	// Enqueue crea una entrega por cada suscripción activa que coincide con el
+	// evento. Debe llamarse en la misma transacción que inserta el evento.
+	Enqueue(ctx context.Context, eventID uuid.UUID) error
+	// Claim reserva hasta limit entregas vencidas durante lease para que ningún
+	// otro proceso las envíe a la vez.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]domain.PendingWebhook, error)
+	Update(ctx context.Context, delivery domain.WebhookDelivery) error
+	Get(ctx context.Context, params domain.WebhookDeliverySearchParams) ([]domain.WebhookDelivery, error)
+	Count(ctx context.Context, params domain.WebhookDeliverySearchParams) (int, error)
+}
+
+type IWebhookSender interface {
+	Send(ctx context.Context, webhook domain.PendingWebhook) domain.ExecutionResult
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyService struct {
+	uow ports.IUnitOfWork
+	// bootstrapHash es el hash de API_ADMIN_KEY; permite crear las primeras keys
+	bootstrapHash string
+	clock         ports.IClock
+	logger        *slog.Logger
+}
+
+// NewAPIKeyService creates the service. bootstrapKey is an admin token that is
+// accepted without being stored (empty disables it), clock may be nil to use
+// the system clock and logger may be nil to use slog.Default().
+func NewAPIKeyService(uow ports.IUnitOfWork, bootstrapKey string, clock ports.IClock, logger *slog.Logger) *APIKeyService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	s := &APIKeyService{uow: uow, clock: clockOrSystem(clock), logger: logger}
+	if bootstrapKey != "" {
+		s.bootstrapHash = domain.HashAPIKey(bootstrapKey)
+	}
+	return s
+}
+
+var _ ports.IAPIKeyService = (*APIKeyService)(nil)
+
+// Create implements ports.IAPIKeyService.
+func (s *APIKeyService) Create(ctx context.Context, input domain.APIKeyInput) (*domain.CreatedAPIKey, error) {
+	// Una key de tenant solo crea keys de su mismo tenant
+	if tenant := domain.TenantFrom(ctx); tenant != nil {
+		if input.Tenant != "" && input.Tenant != *tenant {
+			return nil, fmt.Errorf("cannot create keys for tenant %q: %w", input.Tenant, domain.ErrForbidden)
+		}
+		input.Tenant = *tenant
+	}
+
+	key, err := domain.NewAPIKey(input, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.uow.APIKey().Insert(ctx, key.APIKey); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// List implements ports.IAPIKeyService.
+// Incluye las keys revocadas para poder auditar jobs viejos.
+func (s *APIKeyService) List(ctx context.Context) ([]domain.APIKey, error) {
+	keys, err := s.uow.APIKey().Get(ctx, domain.APIKeySearchParams{IncludeRevoked: true})
+	if err != nil {
+		return nil, err
+	}
+	if keys == nil {
+		keys = []domain.APIKey{}
+	}
+
+	return keys, nil
+}
+
+// Revoke implements ports.IAPIKeyService.
+func (s *APIKeyService) Revoke(ctx context.Context, id uuid.UUID) error {
+	// El GetOne aplica el filtro de tenant: no se pueden revocar keys ajenas
+	if _, err := s.uow.APIKey().GetOne(ctx, domain.APIKeySearchParams{ID: &id}); err != nil {
+		return err
+	}
+	return s.uow.APIKey().Revoke(ctx, id, s.clock.Now())
+}
+
+// Authenticate implements ports.IAPIKeyService.
+func (s *APIKeyService) Authenticate(ctx context.Context, token string) (*domain.APIKey, error) {
+	if token == "" {
+		return nil, fmt.Errorf("missing api key: %w", domain.ErrUnauthorized)
+	}
+
+	hash := domain.HashAPIKey(token)
+
+	if s.bootstrapHash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(s.bootstrapHash)) == 1 {
+		return &domain.APIKey{
+			Name:   "bootstrap",
+			Prefix: "bootstrap",
+			Scopes: []domain.APIKeyScope{domain.APIKeyScopeAdmin},
+		}, nil
+	}
+
+	key, err := s.uow.APIKey().GetOne(ctx, domain.APIKeySearchParams{Hash: &hash})
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("invalid api key: %w", domain.ErrUnauthorized)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// last_used_at es informativo: un error acá no rechaza el request
+	if err := s.uow.APIKey().TouchLastUsed(ctx, key.ID, s.clock.Now()); err != nil {
+		s.logger.WarnContext(ctx, "touch last_used_at failed", "prefix", key.Prefix, "error", err)
+	}
+
+	return key, nil
+}
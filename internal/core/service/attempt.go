@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/utils"
+
+	"github.com/google/uuid"
+)
+
+type AttemptService struct {
+	uow ports.IUnitOfWork
+}
+
+func NewAttemptService(uow ports.IUnitOfWork) *AttemptService {
+	return &AttemptService{uow: uow}
+}
+
+var _ ports.IAttemptService = (*AttemptService)(nil)
+
+// ListByJob implements ports.IAttemptService.
+func (s *AttemptService) ListByJob(ctx context.Context, jobID uuid.UUID) ([]domain.Attempt, error) {
+	if _, err := s.uow.Job().GetOne(ctx, domain.JobSearchParams{ID: &jobID}); err != nil {
+		return nil, err
+	}
+
+	sort := "attempt_number"
+	attempts, err := s.uow.Attempt().Get(ctx, domain.AttemptSearchParams{
+		JobID:        &jobID,
+		SearchParams: utils.SearchParams{Sort: &sort},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if attempts == nil {
+		attempts = []domain.Attempt{}
+	}
+
+	return attempts, nil
+}
+
+// List implements ports.IAttemptService.
+func (s *AttemptService) List(ctx context.Context, params domain.AttemptSearchParams) (*utils.PaginatorWrapper[[]domain.Attempt], error) {
+	limit := pageLimit(params.SearchParams)
+
+	total, err := s.uow.Attempt().Count(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Se pide un elemento extra para saber si hay una página siguiente
+	fetch := limit + 1
+	params.Limit = &fetch
+
+	attempts, err := s.uow.Attempt().Get(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginate(attempts, total, params.SearchParams, limit, func(a domain.Attempt, field string) utils.Cursor {
+		return utils.Cursor{Value: a.SortValue(field), ID: a.ID.String()}
+	}), nil
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+)
+
+type BatchService struct {
+	uow ports.IUnitOfWork
+}
+
+func NewBatchService(uow ports.IUnitOfWork) *BatchService {
+	return &BatchService{uow: uow}
+}
+
+var _ ports.IBatchService = (*BatchService)(nil)
+
+// GetStatus implements ports.IBatchService.
+func (s *BatchService) GetStatus(ctx context.Context, params domain.JobBatchSearchParams) (*domain.BatchStatus, error) {
+	batch, err := s.uow.JobBatch().GetOne(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.uow.JobBatch().CountJobs(ctx, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewBatchStatus(*batch, counts), nil
+}
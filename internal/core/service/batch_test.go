@@ -0,0 +1,140 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"testing"
+)
+
+func batchItems(inputs ...domain.CreateJobInput) iter.Seq2[domain.CreateJobInput, error] {
+	return func(yield func(domain.CreateJobInput, error) bool) {
+		for _, input := range inputs {
+			if !yield(input, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (e *testEnv) countJobs(t *testing.T, params domain.JobSearchParams) int {
+	t.Helper()
+	total, err := e.uow.Job().Count(context.Background(), params)
+	if err != nil {
+		t.Fatalf("count jobs: %v", err)
+	}
+	return total
+}
+
+// En all-or-nothing un item inválido descarta el lote entero: no queda
+// ningún job ni suscripción, y la respuesta no entrega ids ni secretos.
+func TestCreateBatchAllOrNothing(t *testing.T) {
+	notify := func(in *domain.CreateJobInput) { in.NotifyURL = ptrTo("https://hooks.example.com/job") }
+	invalid := func(in *domain.CreateJobInput) { in.Type = "" }
+
+	tests := []struct {
+		name         string
+		allOrNothing bool
+		wantCreated  int
+	}{
+		{"all-or-nothing", true, 0},
+		{"parcial", false, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			ctx := context.Background()
+
+			result, err := env.jobs.CreateBatch(ctx, batchItems(jobInput(notify), jobInput(invalid), jobInput()),
+				domain.BulkCreateOptions{AllOrNothing: tt.allOrNothing})
+			if err != nil {
+				t.Fatalf("create batch: %v", err)
+			}
+
+			if result.RolledBack != tt.allOrNothing || result.Created != tt.wantCreated || result.Rejected != 1 || result.Total != 3 {
+				t.Fatalf("result = rolled_back %v, created %d, rejected %d, total %d; want %v, %d, 1, 3",
+					result.RolledBack, result.Created, result.Rejected, result.Total, tt.allOrNothing, tt.wantCreated)
+			}
+			if result.Items[1].Error == nil || result.Items[1].Error.Code != "validation_failed" {
+				t.Fatalf("item 1 error = %+v, want validation_failed", result.Items[1].Error)
+			}
+			if got := env.countJobs(t, domain.JobSearchParams{}); got != tt.wantCreated {
+				t.Fatalf("stored %d jobs, want %d", got, tt.wantCreated)
+			}
+			subscriptions, err := env.uow.WebhookSubscription().Get(ctx, domain.WebhookSubscriptionSearchParams{})
+			if err != nil {
+				t.Fatalf("list subscriptions: %v", err)
+			}
+
+			first := result.Items[0]
+			if tt.allOrNothing {
+				if first.JobID != nil || first.NotifySecret != nil {
+					t.Fatalf("rolled back item 0 = %+v, want no job id nor notify secret", first)
+				}
+				if len(subscriptions) != 0 {
+					t.Fatalf("stored %d subscriptions, want 0", len(subscriptions))
+				}
+				return
+			}
+			if first.JobID == nil || first.NotifySecret == nil {
+				t.Fatalf("item 0 = %+v, want a job id and a notify secret", first)
+			}
+			if len(subscriptions) != 1 || subscriptions[0].Secret != *first.NotifySecret {
+				t.Fatalf("got %d subscriptions, want the one of item 0", len(subscriptions))
+			}
+		})
+	}
+}
+
+// El callback del lote se encola una sola vez, cuando el último miembro
+// llega a un estado final, aunque llegue un mensaje duplicado.
+func TestBatchCompletionCallback(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	noRetries := func(in *domain.CreateJobInput) { in.MaxRetries = 0 }
+	result, err := env.jobs.CreateBatch(ctx, batchItems(jobInput(), jobInput(noRetries)),
+		domain.BulkCreateOptions{CallbackURL: ptrTo("https://api.example.com/batch-done")})
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+	if result.Created != 2 {
+		t.Fatalf("created %d jobs, want 2", result.Created)
+	}
+
+	batch := func(t *testing.T) *domain.JobBatch {
+		t.Helper()
+		batch, err := env.uow.JobBatch().GetOne(ctx, domain.JobBatchSearchParams{ID: &result.ID})
+		if err != nil {
+			t.Fatalf("get batch: %v", err)
+		}
+		return batch
+	}
+
+	env.process(t, env.requeue(t, *result.Items[0].JobID))
+	if b := batch(t); b.CompletedAt != nil || b.CallbackJobID != nil {
+		t.Fatalf("batch completed with one member still pending: %+v", b)
+	}
+
+	env.exec.respond(domain.ExecutionResult{HTTPStatus: 500, Error: errors.New("callback failed with status 500")})
+	last := env.requeue(t, *result.Items[1].JobID)
+	env.process(t, last)
+	env.process(t, last)
+
+	b := batch(t)
+	if b.CompletedAt == nil || b.CallbackJobID == nil || b.Succeeded != 1 || b.Failed != 1 {
+		t.Fatalf("batch = %+v, want completed with 1 succeeded, 1 failed and a callback job", b)
+	}
+	if got := env.countJobs(t, domain.JobSearchParams{}); got != 3 {
+		t.Fatalf("stored %d jobs, want the 2 members and 1 callback", got)
+	}
+
+	callback := env.job(t, *b.CallbackJobID)
+	if callback.CallbackURL != "https://api.example.com/batch-done" || callback.Status != domain.JobStatusPending {
+		t.Fatalf("callback job = %s to %s, want pending to the batch callback_url", callback.Status, callback.CallbackURL)
+	}
+	if callback.BatchID != nil {
+		t.Fatalf("callback job belongs to batch %s, want none", callback.BatchID)
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+)
+
+type CircuitBreakerService struct {
+	uow ports.IUnitOfWork
+}
+
+func NewCircuitBreakerService(uow ports.IUnitOfWork) *CircuitBreakerService {
+	return &CircuitBreakerService{uow: uow}
+}
+
+var _ ports.ICircuitBreakerService = (*CircuitBreakerService)(nil)
+
+// List implements ports.ICircuitBreakerService.
+func (s *CircuitBreakerService) List(ctx context.Context, params domain.CircuitBreakerSearchParams) ([]domain.CircuitBreaker, error) {
+	return s.uow.CircuitBreaker().Get(ctx, params)
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"time"
+)
+
+// systemClock se usa cuando no se configura un reloj.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// clockOrSystem devuelve clock, o el reloj del sistema si es nil.
+func clockOrSystem(clock ports.IClock) ports.IClock {
+	if clock == nil {
+		return systemClock{}
+	}
+	return clock
+}
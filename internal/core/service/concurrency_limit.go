@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+)
+
+type ConcurrencyLimitService struct {
+	uow   ports.IUnitOfWork
+	clock ports.IClock
+}
+
+// NewConcurrencyLimitService creates the service. clock may be nil to use the system clock.
+func NewConcurrencyLimitService(uow ports.IUnitOfWork, clock ports.IClock) *ConcurrencyLimitService {
+	return &ConcurrencyLimitService{uow: uow, clock: clockOrSystem(clock)}
+}
+
+var _ ports.IConcurrencyLimitService = (*ConcurrencyLimitService)(nil)
+
+// List implements ports.IConcurrencyLimitService.
+func (s *ConcurrencyLimitService) List(ctx context.Context, params domain.ConcurrencyLimitSearchParams) ([]domain.ConcurrencyLimit, error) {
+	return s.uow.ConcurrencyLimit().Get(ctx, params)
+}
+
+// Put implements ports.IConcurrencyLimitService.
+func (s *ConcurrencyLimitService) Put(ctx context.Context, input domain.ConcurrencyLimitInput) (*domain.ConcurrencyLimit, error) {
+	if err := requireOperator(ctx); err != nil {
+		return nil, err
+	}
+
+	limit, err := domain.NewConcurrencyLimit(input, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.uow.ConcurrencyLimit().Upsert(ctx, *limit); err != nil {
+		return nil, err
+	}
+
+	return limit, nil
+}
+
+// Delete implements ports.IConcurrencyLimitService.
+func (s *ConcurrencyLimitService) Delete(ctx context.Context, jobType string) error {
+	if err := requireOperator(ctx); err != nil {
+		return err
+	}
+	return s.uow.ConcurrencyLimit().Delete(ctx, jobType)
+}
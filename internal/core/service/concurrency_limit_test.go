@@ -0,0 +1,66 @@
+package service_test
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/internal/core/service"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Sin slot libre el job vuelve a pending con un delay corto y sin consumir un
+// intento; al terminar, el worker libera el slot que tomó.
+func TestProcessJobMessageConcurrencyLimit(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	limits := service.NewConcurrencyLimitService(env.uow, env.clock)
+	if _, err := limits.Put(ctx, domain.ConcurrencyLimitInput{JobType: "email", MaxConcurrent: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Otro worker ocupa el único slot del tipo
+	other := uuid.New()
+	err := env.uow.Atomic(ctx, func(uow ports.IUnitOfWork) error {
+		_, err := uow.ConcurrencyLimit().AcquireSlot(ctx, "email", other)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := env.queueJob(t, jobInput())
+	env.process(t, msg)
+
+	job := env.job(t, msg.JobID)
+	if job.Status != domain.JobStatusPending || job.Attempts != 0 {
+		t.Fatalf("job = %s with %d attempts, want pending with 0", job.Status, job.Attempts)
+	}
+	if want := env.clock.Now().Add(domain.ConcurrencyRetryDelay); job.ScheduledAt == nil || !job.ScheduledAt.Equal(want) {
+		t.Fatalf("scheduled_at = %v, want %s", job.ScheduledAt, want)
+	}
+	if len(env.exec.calls) != 0 {
+		t.Fatalf("callback executed %d times without a slot", len(env.exec.calls))
+	}
+
+	// Con el slot libre el job corre y devuelve el slot al terminar
+	if err := env.uow.ConcurrencyLimit().ReleaseSlot(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+	env.clock.Advance(domain.ConcurrencyRetryDelay)
+	msg = env.requeue(t, msg.JobID)
+	env.process(t, msg)
+
+	if job := env.job(t, msg.JobID); job.Status != domain.JobStatusCompleted {
+		t.Fatalf("job status = %s, want completed", job.Status)
+	}
+	got, err := limits.List(ctx, domain.ConcurrencyLimitSearchParams{JobType: ptrTo("email")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Running != 0 {
+		t.Fatalf("limits = %+v, want the slot released", got)
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"time"
+)
+
+// eventBacklogLimit es el máximo de eventos que se reenvían al reanudar un stream.
+const eventBacklogLimit uint = 1000
+
+// eventHoldBack es cuánto puede tardar en confirmarse la transacción de un
+// evento. seq se toma al insertar pero se ve recién en el commit, así que un
+// evento con seq menor puede aparecer después de uno mayor.
+const eventHoldBack = 5 * time.Second
+
+// eventRecheckInterval es cada cuánto se vuelve a consultar la base por los
+// eventos que el canal en vivo no entregó (reconexión del LISTEN, reanudación).
+const eventRecheckInterval = 2 * time.Second
+
+type EventStreamService struct {
+	uow    ports.IUnitOfWork
+	broker ports.IEventBroker
+	clock  ports.IClock
+}
+
+// NewEventStreamService creates the service. clock may be nil to use the system clock.
+func NewEventStreamService(uow ports.IUnitOfWork, broker ports.IEventBroker, clock ports.IClock) *EventStreamService {
+	return &EventStreamService{uow: uow, broker: broker, clock: clockOrSystem(clock)}
+}
+
+var _ ports.IEventStreamService = (*EventStreamService)(nil)
+
+// Stream implements ports.IEventStreamService.
+func (s *EventStreamService) Stream(ctx context.Context, filter domain.EventStreamFilter, afterSeq *int64) (<-chan domain.StreamEvent, error) {
+	// El listener ve los eventos de todos los tenants; se filtran por el del request
+	filter.Tenant = domain.TenantFrom(ctx)
+
+	if filter.JobID != nil {
+		if _, err := s.uow.Job().GetOne(ctx, domain.JobSearchParams{ID: filter.JobID}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Suscribirse antes de leer el backlog para no perder eventos entre ambos pasos
+	live, unsubscribe := s.broker.Subscribe(filter)
+
+	var backlog []domain.StreamEvent
+	if afterSeq != nil {
+		var err error
+		backlog, err = s.eventsAfter(ctx, filter, *afterSeq)
+		if err != nil {
+			unsubscribe()
+			return nil, err
+		}
+	}
+
+	out := make(chan domain.StreamEvent)
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+
+		cursor := newStreamCursor(afterSeq)
+		send := func(events ...domain.StreamEvent) bool {
+			for _, event := range events {
+				// Los eventos del backlog también pueden llegar por el canal en vivo
+				if !cursor.take(event.Seq) {
+					continue
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		}
+
+		if !send(backlog...) {
+			return
+		}
+		cursor.settle(backlog, s.clock.Now())
+
+		ticker := time.NewTicker(eventRecheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-live:
+				if !ok || !send(event) {
+					return
+				}
+			case <-ticker.C:
+				if cursor.watermark == nil {
+					continue
+				}
+				// Un error se reintenta en el próximo tick
+				events, err := s.eventsAfter(ctx, filter, *cursor.watermark)
+				if err != nil {
+					continue
+				}
+				if !send(events...) {
+					return
+				}
+				cursor.settle(events, s.clock.Now())
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (s *EventStreamService) eventsAfter(ctx context.Context, filter domain.EventStreamFilter, seq int64) ([]domain.StreamEvent, error) {
+	params := filter.SearchParams()
+	params.AfterSeq = &seq
+	limit := eventBacklogLimit
+	params.Limit = &limit
+
+	return s.uow.Event().GetStream(ctx, params)
+}
+
+// streamCursor lleva los eventos enviados por un stream. Como los eventos
+// pueden confirmarse fuera de orden no alcanza con el último seq enviado: se
+// recuerda cada seq enviado por encima de watermark, el seq hasta el cual ya
+// no puede aparecer nada nuevo, y la base se vuelve a consultar desde ahí.
+type streamCursor struct {
+	watermark *int64 // nil hasta conocer el primer evento, si el stream no se reanudó
+	sent      map[int64]struct{}
+}
+
+func newStreamCursor(afterSeq *int64) *streamCursor {
+	c := &streamCursor{sent: map[int64]struct{}{}}
+	if afterSeq != nil {
+		watermark := *afterSeq
+		c.watermark = &watermark
+	}
+	return c
+}
+
+// take reports whether seq still has to be sent, and records it as sent.
+func (c *streamCursor) take(seq int64) bool {
+	if _, ok := c.sent[seq]; ok {
+		return false
+	}
+	if c.watermark == nil {
+		watermark := seq - 1
+		c.watermark = &watermark
+	}
+	c.sent[seq] = struct{}{}
+	return true
+}
+
+// settle avanza watermark sobre los eventos consultados (ordenados por seq)
+// que tienen más de eventHoldBack: cualquier seq menor ya se confirmó o no va
+// a aparecer. Se detiene en el primero más nuevo.
+func (c *streamCursor) settle(events []domain.StreamEvent, now time.Time) {
+	if c.watermark == nil {
+		return
+	}
+	for _, event := range events {
+		if event.CreatedAt.After(now.Add(-eventHoldBack)) {
+			break
+		}
+		if event.Seq > *c.watermark {
+			*c.watermark = event.Seq
+		}
+	}
+	for seq := range c.sent {
+		if seq <= *c.watermark {
+			delete(c.sent, seq)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+)
+
+func streamEvent(seq int64, createdAt time.Time) domain.StreamEvent {
+	return domain.StreamEvent{Event: domain.Event{Seq: seq, CreatedAt: createdAt}}
+}
+
+func TestStreamCursorOutOfOrder(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-2 * eventHoldBack)
+	recent := now.Add(-eventHoldBack / 2)
+
+	tests := []struct {
+		name          string
+		afterSeq      *int64
+		take          []int64 // seqs en el orden en que llegan
+		wantSent      []int64
+		settle        []domain.StreamEvent
+		wantWatermark int64
+	}{
+		{
+			name:          "un seq menor confirmado después se envía igual",
+			take:          []int64{11, 10, 12},
+			wantSent:      []int64{11, 10, 12},
+			wantWatermark: 10,
+		},
+		{
+			name:          "los duplicados del backlog y el canal en vivo se envían una vez",
+			afterSeq:      ptrTo(int64(5)),
+			take:          []int64{6, 7, 6, 7, 8},
+			wantSent:      []int64{6, 7, 8},
+			wantWatermark: 5,
+		},
+		{
+			name:          "watermark avanza sobre los eventos viejos",
+			afterSeq:      ptrTo(int64(5)),
+			take:          []int64{6, 8},
+			wantSent:      []int64{6, 8},
+			settle:        []domain.StreamEvent{streamEvent(6, old), streamEvent(8, old)},
+			wantWatermark: 8,
+		},
+		{
+			name:          "watermark no pasa un evento reciente",
+			afterSeq:      ptrTo(int64(5)),
+			take:          []int64{6, 8, 9},
+			wantSent:      []int64{6, 8, 9},
+			settle:        []domain.StreamEvent{streamEvent(6, old), streamEvent(8, recent), streamEvent(9, old)},
+			wantWatermark: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newStreamCursor(tt.afterSeq)
+
+			var sent []int64
+			for _, seq := range tt.take {
+				if c.take(seq) {
+					sent = append(sent, seq)
+				}
+			}
+			if len(sent) != len(tt.wantSent) {
+				t.Fatalf("sent %v, want %v", sent, tt.wantSent)
+			}
+			for i := range sent {
+				if sent[i] != tt.wantSent[i] {
+					t.Fatalf("sent %v, want %v", sent, tt.wantSent)
+				}
+			}
+
+			c.settle(tt.settle, now)
+			if c.watermark == nil || *c.watermark != tt.wantWatermark {
+				t.Fatalf("watermark = %v, want %d", c.watermark, tt.wantWatermark)
+			}
+		})
+	}
+}
+
+// Lo que queda por debajo de watermark se olvida; lo que está por encima
+// sigue deduplicándose contra la próxima consulta.
+func TestStreamCursorSettlePrunes(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := newStreamCursor(ptrTo(int64(0)))
+	c.take(1)
+	c.take(2)
+
+	c.settle([]domain.StreamEvent{streamEvent(1, now.Add(-time.Hour)), streamEvent(2, now)}, now)
+
+	if _, ok := c.sent[1]; ok {
+		t.Error("seq 1 is below the watermark and should be pruned")
+	}
+	if c.take(2) {
+		t.Error("seq 2 is above the watermark and was already sent")
+	}
+}
+
+func ptrTo[T any](v T) *T { return &v }
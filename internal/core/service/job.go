@@ -2,24 +2,47 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"job_scheduler_go_rabbitmq/internal/core/domain"
 	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/utils"
+	"log/slog"
 	"sort"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type JobService struct {
-	uow    ports.IUnitOfWork
-	exec   ports.IJobExecutor
-	rabbit ports.IRabbitMQClient
+	uow     ports.IUnitOfWork
+	exec    ports.IJobExecutor
+	rabbit  ports.IRabbitMQClient
+	policy  *domain.CallbackPolicy
+	clock   ports.IClock
+	metrics ports.IJobMetrics
+	logger  *slog.Logger
 }
 
-func NewJobService(uow ports.IUnitOfWork, exec ports.IJobExecutor, rabbit ports.IRabbitMQClient) *JobService {
+// NewJobService creates the service. clock may be nil to use the system clock,
+// metrics may be nil to disable metrics and logger may be nil to use
+// slog.Default().
+func NewJobService(uow ports.IUnitOfWork, exec ports.IJobExecutor, rabbit ports.IRabbitMQClient, policy *domain.CallbackPolicy, clock ports.IClock, metrics ports.IJobMetrics, logger *slog.Logger) *JobService {
+	if metrics == nil {
+		metrics = noopJobMetrics{}
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
 	return &JobService{
-		uow:    uow,
-		exec:   exec,
-		rabbit: rabbit,
+		uow:     uow,
+		exec:    exec,
+		rabbit:  rabbit,
+		policy:  policy,
+		clock:   clockOrSystem(clock),
+		metrics: metrics,
+		logger:  logger,
 	}
 }
 
@@ -32,8 +55,51 @@ func (s *JobService) ProcessJobMessage(
 ) error {
 
 	var retryMsg *domain.RabbitJobMessage
+	// Estado final del intento, para las métricas (vacío si no se ejecutó)
+	var outcome domain.JobStatus
 
-	err := s.uow.Atomic(ctx, func(uow ports.IUnitOfWork) error {
+	// Idempotencia: un mensaje duplicado, viejo o de un job ya terminado no
+	// consume tokens ni slots. La transacción lo vuelve a verificar, por si
+	// otro worker toma el job entretanto
+	runnable, err := s.isRunnable(ctx, msg.JobID)
+	if err != nil {
+		return err
+	}
+	if !runnable {
+		s.logger.InfoContext(ctx, "job not runnable, skipping message")
+		return nil
+	}
+
+	// Límite de concurrencia del tipo: sin slot libre el job se reencola con un
+	// pequeño delay sin consumir un intento
+	acquired, err := s.acquireSlot(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return s.deferJob(ctx, msg.JobID, s.clock.Now().Add(domain.ConcurrencyRetryDelay), "concurrency limit reached")
+	}
+	// El slot se libera antes de publicar el reintento, para que no compita
+	// con su propio intento anterior; el defer cubre los returns tempranos
+	released := false
+	release := func() {
+		if !released {
+			released = true
+			s.releaseSlot(ctx, msg.JobID)
+		}
+	}
+	defer release()
+
+	// Rate limit por tipo / host: si no hay tokens el job se difiere sin consumir un intento
+	wait, err := s.acquireRateLimit(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		return s.deferJob(ctx, msg.JobID, s.clock.Now().Add(wait), "rate limit exceeded")
+	}
+
+	err = s.uow.Atomic(ctx, func(uow ports.IUnitOfWork) error {
 
 		//  Load job
 		job, err := uow.Job().GetOne(ctx, domain.JobSearchParams{
@@ -43,26 +109,53 @@ func (s *JobService) ProcessJobMessage(
 			return err
 		}
 
-		// Idempotencia
-		if job.Status != domain.JobStatusQueued {
+		// Idempotencia: mensaje duplicado o job ya procesado. Un job failed sí
+		// se ejecuta: el mensaje es su reintento
+		if !job.Status.CanTransitionTo(domain.JobStatusRunning) {
 			return nil
 		}
 
+		// El número de intento sale de la fila: un job diferido o reprogramado
+		// vuelve a publicarse con un mensaje nuevo
+		attemptNumber := job.Attempts + 1
+
 		//  Mark running
 		if err := uow.Job().MarkRunning(ctx, job.ID); err != nil {
 			return err
 		}
 
 		//  Ejecutar callback (lado técnico)
+		startedAt := s.clock.Now()
 		result := s.exec.Execute(ctx, job)
+		finishedAt := s.clock.Now()
+		workerID := domain.WorkerIDFrom(ctx)
+
+		// Circuito abierto para el host: no se consume un intento y el job se
+		// reprograma para después del cool-down
+		var openErr *domain.CircuitOpenError
+		if errors.As(result.Error, &openErr) {
+			if err := uow.Job().Reschedule(ctx, job.ID, openErr.RetryAt); err != nil {
+				return err
+			}
+
+			return s.insertEvent(
+				ctx,
+				uow,
+				domain.NewJobDeferredEvent(job.ID, "circuit open for "+openErr.Host, openErr.RetryAt, finishedAt),
+			)
+		}
+
 		// SUCCESS
 		if result.Error == nil {
 			attempt := domain.NewAttempt(
 				job.ID,
-				msg.Attempt,
+				attemptNumber,
 				domain.AttemptStatusSuccess,
 				nil,
-				&result.HTTPStatus,
+				httpStatusOrNil(result.HTTPStatus),
+				startedAt,
+				finishedAt,
+				workerID,
 			)
 
 			if err := uow.Attempt().Insert(ctx, attempt); err != nil {
@@ -72,10 +165,16 @@ func (s *JobService) ProcessJobMessage(
 			if err := uow.Job().MarkCompleted(ctx, job.ID); err != nil {
 				return err
 			}
+			outcome = domain.JobStatusCompleted
+
+			if err := s.recordBatchResult(ctx, uow, job, true); err != nil {
+				return err
+			}
 
-			if err := uow.Event().Insert(
+			if err := s.insertEvent(
 				ctx,
-				domain.NewJobSucceededEvent(job.ID),
+				uow,
+				domain.NewJobSucceededEvent(job.ID, finishedAt),
 			); err != nil {
 				return err
 			}
@@ -88,31 +187,37 @@ func (s *JobService) ProcessJobMessage(
 
 		attempt := domain.NewAttempt(
 			job.ID,
-			msg.Attempt,
+			attemptNumber,
 			domain.AttemptStatusFailed,
 			&errMsg,
-			&result.HTTPStatus,
+			httpStatusOrNil(result.HTTPStatus),
+			startedAt,
+			finishedAt,
+			workerID,
 		)
 
 		if err := uow.Attempt().Insert(ctx, attempt); err != nil {
 			return err
 		}
 
-		if msg.Attempt < job.MaxRetries {
+		// Un callback no permitido no se arregla reintentando
+		if attemptNumber < job.MaxRetries && !errors.Is(result.Error, domain.ErrCallbackNotAllowed) {
 			if err := uow.Job().MarkFailed(ctx, job.ID, errMsg, nil); err != nil {
 				return err
 			}
 
-			if err := uow.Event().Insert(
+			if err := s.insertEvent(
 				ctx,
-				domain.NewJobFailedEvent(job.ID, errMsg),
+				uow,
+				domain.NewJobFailedEvent(job.ID, errMsg, finishedAt),
 			); err != nil {
 				return err
 			}
 
 			next := msg
-			next.Attempt++
+			next.Attempt = attemptNumber + 1
 			retryMsg = &next
+			outcome = domain.JobStatusFailed
 			return nil
 		}
 
@@ -120,16 +225,34 @@ func (s *JobService) ProcessJobMessage(
 		if err := uow.Job().MarkDead(ctx, job.ID, "max retries exceeded"); err != nil {
 			return err
 		}
+		outcome = domain.JobStatusDead
+
+		if err := s.recordBatchResult(ctx, uow, job, false); err != nil {
+			return err
+		}
 
-		return uow.Event().Insert(
+		return s.insertEvent(
 			ctx,
-			domain.NewJobDeadEvent(job.ID),
+			uow,
+			domain.NewJobDeadEvent(job.ID, finishedAt),
 		)
 	})
 
 	if err != nil {
+		// Otro worker (mensaje duplicado) o un request cambió el estado del job
+		// entretanto: la transacción se deshizo entera, intento y eventos incluidos
+		if isTransitionConflict(err) {
+			s.logger.WarnContext(ctx, "job changed state concurrently, skipping message", "error", err)
+			return nil
+		}
 		return err
 	}
+	release()
+
+	if outcome != "" {
+		s.metrics.JobFinished(msg.Type, outcome)
+		s.logger.InfoContext(ctx, "job attempt finished", "status", outcome)
+	}
 
 	if retryMsg != nil {
 		return s.rabbit.Publish(*retryMsg)
@@ -138,25 +261,223 @@ func (s *JobService) ProcessJobMessage(
 	return nil
 }
 
+// insertEvent guarda el evento y encola los webhooks de las suscripciones que
+// lo escuchan, en la misma transacción.
+func (s *JobService) insertEvent(ctx context.Context, uow ports.IUnitOfWork, event domain.Event) error {
+	if err := uow.Event().Insert(ctx, event); err != nil {
+		return err
+	}
+	return uow.WebhookDelivery().Enqueue(ctx, event.ID)
+}
+
+// isRunnable indica si el job puede pasar a running, sin bloquear la fila.
+func (s *JobService) isRunnable(ctx context.Context, jobID uuid.UUID) (bool, error) {
+	job, err := s.uow.Job().GetOne(ctx, domain.JobSearchParams{
+		ID: &jobID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return job.Status.CanTransitionTo(domain.JobStatusRunning), nil
+}
+
+// acquireSlot reserva un slot del semáforo de concurrencia del tipo del job.
+func (s *JobService) acquireSlot(ctx context.Context, msg domain.RabbitJobMessage) (bool, error) {
+	var acquired bool
+
+	err := s.uow.Atomic(ctx, func(uow ports.IUnitOfWork) error {
+		var err error
+		acquired, err = uow.ConcurrencyLimit().AcquireSlot(ctx, msg.Type, msg.JobID)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return acquired, nil
+}
+
+// releaseSlot libera el slot del job. Si falla, el slot expira solo al vencer su lease.
+func (s *JobService) releaseSlot(ctx context.Context, jobID uuid.UUID) {
+	if err := s.uow.ConcurrencyLimit().ReleaseSlot(ctx, jobID); err != nil {
+		s.logger.WarnContext(ctx, "failed to release concurrency slot", "error", err)
+	}
+}
+
+// acquireRateLimit toma un token de los buckets que aplican al job en una
+// transacción corta, para no mantener los buckets bloqueados durante el callback.
+func (s *JobService) acquireRateLimit(ctx context.Context, msg domain.RabbitJobMessage) (time.Duration, error) {
+	var wait time.Duration
+
+	err := s.uow.Atomic(ctx, func(uow ports.IUnitOfWork) error {
+		var err error
+		wait, err = uow.RateLimit().Acquire(ctx, domain.RateLimitKeysFor(msg.Tenant, msg.Type, msg.CallbackURL))
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return wait, nil
+}
+
+// deferJob posterga el job para que el dispatcher lo vuelva a publicar a partir
+// de runAt. Un job encolado vuelve a pending; un reintento sigue failed, así
+// conserva su intento y no se confunde con un job nuevo.
+func (s *JobService) deferJob(ctx context.Context, jobID uuid.UUID, runAt time.Time, reason string) error {
+	s.logger.InfoContext(ctx, "job deferred", "reason", reason, "run_at", runAt)
+
+	err := s.uow.Atomic(ctx, func(uow ports.IUnitOfWork) error {
+		job, err := uow.Job().GetOne(ctx, domain.JobSearchParams{
+			ID: &jobID,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Idempotencia: solo se difiere un job que espera a un worker
+		switch job.Status {
+		case domain.JobStatusQueued:
+			err = uow.Job().Reschedule(ctx, job.ID, runAt)
+		case domain.JobStatusFailed:
+			err = uow.Job().DeferRetry(ctx, job.ID, runAt)
+		default:
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.insertEvent(
+			ctx,
+			uow,
+			domain.NewJobDeferredEvent(job.ID, reason, runAt, s.clock.Now()),
+		)
+	})
+	if isTransitionConflict(err) {
+		s.logger.WarnContext(ctx, "job changed state concurrently, not deferred", "error", err)
+		return nil
+	}
+	return err
+}
+
+// isTransitionConflict reports whether err is a job state transition rejected
+// because the job was no longer in a valid source status.
+func isTransitionConflict(err error) bool {
+	var transitionErr *domain.TransitionError
+	return errors.As(err, &transitionErr)
+}
+
 // Create implements ports.IJobService.
-func (s *JobService) Create(ctx context.Context, input domain.CreateJobInput) (*domain.Job, error) {
-	job, err := domain.NewJob(input)
+func (s *JobService) Create(ctx context.Context, input domain.CreateJobInput) (*domain.CreatedJob, error) {
+	job, subscription, err := s.prepareJob(ctx, input, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	err = s.uow.Atomic(ctx, func(d ports.IUnitOfWork) error {
+		ok, err := d.TenantQuota().Reserve(ctx, job.Tenant, 1)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("tenant %s reached its pending jobs quota: %w", job.Tenant, domain.ErrQuotaExceeded)
+		}
+
 		err = d.Job().Insert(ctx, *job)
 		if err != nil {
 			return err
 		}
+		if subscription != nil {
+			return d.WebhookSubscription().Insert(ctx, *subscription)
+		}
 		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return job, nil
+	s.metrics.JobCreated(job.Type)
+
+	created := &domain.CreatedJob{Job: *job}
+	if subscription != nil {
+		created.NotifySecret = &subscription.Secret
+	}
+	return created, nil
+}
+
+// prepareJob aplica los permisos de la API key del request, construye el job y
+// valida sus URLs. urlCache evita repetir la validación (y su resolución DNS)
+// de la misma URL dentro de un lote; puede ser nil.
+func (s *JobService) prepareJob(ctx context.Context, input domain.CreateJobInput, urlCache map[string]error) (*domain.Job, *domain.WebhookSubscription, error) {
+	// Las keys de un tenant solo crean jobs en su tenant
+	if tenant := domain.TenantFrom(ctx); tenant != nil {
+		if input.Tenant != "" && input.Tenant != *tenant {
+			return nil, nil, fmt.Errorf("cannot create jobs in tenant %q: %w", input.Tenant, domain.ErrForbidden)
+		}
+		input.Tenant = *tenant
+	}
+
+	// La API key del request queda registrada en el job y limita los tipos permitidos
+	if key := domain.APIKeyFrom(ctx); key != nil {
+		if !key.AllowsJobType(input.Type) {
+			return nil, nil, fmt.Errorf("api key %s cannot create jobs of type %q: %w", key.Prefix, input.Type, domain.ErrForbidden)
+		}
+		if key.ID != uuid.Nil {
+			input.CreatedByKeyID = &key.ID
+		}
+	}
+
+	job, err := domain.NewJob(input, s.clock.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.validateURL(ctx, job.CallbackURL, urlCache); err != nil {
+		return nil, nil, err
+	}
+
+	// notify_url crea una suscripción de webhook ligada solo a este job
+	var subscription *domain.WebhookSubscription
+	if input.NotifyURL != nil {
+		subscription, err = domain.NewWebhookSubscription(domain.WebhookSubscriptionInput{
+			Tenant:     job.Tenant,
+			URL:        *input.NotifyURL,
+			JobID:      &job.ID,
+			EventTypes: domain.DefaultWebhookEvents,
+		}, job.CreatedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := s.validateURL(ctx, subscription.URL, urlCache); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return job, subscription, nil
+}
+
+// validateURL pasa la URL por la política de callbacks, si hay una configurada.
+func (s *JobService) validateURL(ctx context.Context, rawURL string, cache map[string]error) error {
+	if s.policy == nil {
+		return nil
+	}
+	if cache != nil {
+		if err, ok := cache[rawURL]; ok {
+			return err
+		}
+	}
+
+	err := s.policy.Validate(ctx, rawURL)
+	if cache != nil {
+		cache[rawURL] = err
+	}
+	return err
+}
+
+// QueuedTenants implements ports.IJobExecutionService.
+func (s *JobService) QueuedTenants(ctx context.Context) ([]string, error) {
+	return s.uow.Job().QueuedTenants(ctx)
 }
 
 // GetOne implements ports.IJobService.
@@ -168,8 +489,99 @@ func (s *JobService) GetOne(ctx context.Context, params domain.JobSearchParams)
 	return job, nil
 }
 
+// Update implements ports.IJobService.
+func (s *JobService) Update(ctx context.Context, jobID uuid.UUID, input domain.UpdateJobInput) (*domain.Job, error) {
+	if input.IfMatch == "" && input.UpdatedAt == nil {
+		return nil, fmt.Errorf("send If-Match with the job ETag or updated_at: %w", domain.ErrPreconditionRequired)
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	if input.CallbackURL != nil {
+		if err := s.validateURL(ctx, *input.CallbackURL, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	var job *domain.Job
+	err := s.uow.Atomic(ctx, func(d ports.IUnitOfWork) error {
+		var err error
+		job, err = d.Job().GetOne(ctx, domain.JobSearchParams{ID: &jobID})
+		if err != nil {
+			return err
+		}
+
+		if !input.Matches(*job) {
+			return fmt.Errorf("job %s has changed, current ETag is %s: %w", job.ID, job.ETag(), domain.ErrPreconditionFailed)
+		}
+		if !job.Editable() {
+			return fmt.Errorf("job %s is %s and can no longer be edited: %w", job.ID, job.Status, domain.ErrConflict)
+		}
+
+		prevUpdatedAt := job.UpdatedAt
+		now := s.clock.Now()
+		changes := job.Apply(input, now)
+		if len(changes) == 0 {
+			return nil
+		}
+
+		if err := d.Job().Update(ctx, *job, prevUpdatedAt); err != nil {
+			return err
+		}
+
+		// El reintento de un job failed ya está en la cola con la hora vieja:
+		// el job vuelve a pending para que el dispatcher lo publique a la hora
+		// nueva, y el worker descarta ese mensaje
+		if _, ok := changes["scheduled_at"]; ok && job.Status == domain.JobStatusFailed {
+			if err := d.Job().Reschedule(ctx, job.ID, *job.ScheduledAt); err != nil {
+				return err
+			}
+			changes["status"] = domain.JobChange{From: job.Status, To: domain.JobStatusPending}
+
+			if job, err = d.Job().GetOne(ctx, domain.JobSearchParams{ID: &jobID}); err != nil {
+				return err
+			}
+		}
+
+		return s.insertEvent(ctx, d, domain.NewJobUpdatedEvent(job.ID, changes, now))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// List implements ports.IJobService.
+// Soporta paginación por página (Page/Limit) o por cursor (Cursor/Limit).
+func (s *JobService) List(ctx context.Context, params domain.JobSearchParams) (*utils.PaginatorWrapper[[]domain.Job], error) {
+	limit := pageLimit(params.SearchParams)
+
+	total, err := s.uow.Job().Count(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Se pide un elemento extra para saber si hay una página siguiente
+	fetch := limit + 1
+	params.Limit = &fetch
+
+	jobs, err := s.uow.Job().Get(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginate(jobs, total, params.SearchParams, limit, func(j domain.Job, field string) utils.Cursor {
+		return utils.Cursor{Value: j.SortValue(field), ID: j.ID.String()}
+	}), nil
+}
+
 // GetTimeline implements ports.IJobService.
 func (s *JobService) GetTimeline(ctx context.Context, jobID uuid.UUID) ([]domain.Event, error) {
+	if _, err := s.uow.Job().GetOne(ctx, domain.JobSearchParams{ID: &jobID}); err != nil {
+		return nil, err
+	}
+
 	events, err := s.uow.Event().Get(ctx, domain.EventSearchParams{
 		JobID: &jobID,
 	})
@@ -194,13 +606,27 @@ func (s *JobService) GetTimeline(ctx context.Context, jobID uuid.UUID) ([]domain
 		} else {
 			t = domain.EventJobFailed
 			msg = "attempt failed"
+			if a.ErrorMessage != nil {
+				msg += ": " + *a.ErrorMessage
+			}
 		}
 
+		// Se conserva el detalle del intento en la metadata del evento
+		metadata, _ := json.Marshal(map[string]any{
+			"attempt_id":     a.ID,
+			"attempt_number": a.AttemptNumber,
+			"http_status":    a.HTTPStatus,
+			"error":          a.ErrorMessage,
+			"duration_ms":    a.DurationMs,
+			"worker_id":      a.WorkerID,
+		})
+
 		events = append(events, domain.Event{
 			ID:        uuid.New(),
 			JobID:     jobID,
 			Type:      t,
 			Message:   msg,
+			Metadata:  metadata,
 			CreatedAt: a.CreatedAt,
 		})
 	}
@@ -211,3 +637,11 @@ func (s *JobService) GetTimeline(ctx context.Context, jobID uuid.UUID) ([]domain
 
 	return events, nil
 }
+
+// httpStatusOrNil evita guardar 0 cuando no hubo respuesta HTTP.
+func httpStatusOrNil(status int) *int {
+	if status == 0 {
+		return nil
+	}
+	return &status
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// errBatchRejected fuerza el rollback de un lote all-or-nothing con items inválidos.
+var errBatchRejected = errors.New("batch has rejected items")
+
+// batchItem es un item válido a la espera de que se inserte su chunk.
+type batchItem struct {
+	index        int
+	job          domain.Job
+	subscription *domain.WebhookSubscription
+}
+
+// CreateBatch implements ports.IJobService.
+// Los items se validan uno a uno y se insertan con COPY en chunks de
+// domain.BulkChunkSize. Sin AllOrNothing cada chunk va en su propia
+// transacción; con AllOrNothing todo el lote va en una sola y un item
+// rechazado descarta el lote entero.
+func (s *JobService) CreateBatch(ctx context.Context, items iter.Seq2[domain.CreateJobInput, error], opts domain.BulkCreateOptions) (*domain.BulkCreateResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.CallbackURL != nil {
+		if err := s.validateURL(ctx, *opts.CallbackURL, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	tenant := domain.DefaultTenant
+	if t := domain.TenantFrom(ctx); t != nil {
+		tenant = *t
+	}
+	var createdBy *uuid.UUID
+	if key := domain.APIKeyFrom(ctx); key != nil && key.ID != uuid.Nil {
+		createdBy = &key.ID
+	}
+
+	result := &domain.BulkCreateResult{
+		JobBatch: domain.NewJobBatch(tenant, createdBy, opts, s.clock.Now()),
+		Items:    []domain.BulkItemResult{},
+	}
+
+	// El lote se registra fuera de la transacción de los jobs para que quede
+	// constancia de él aunque se descarte
+	if err := s.uow.JobBatch().Insert(ctx, result.JobBatch); err != nil {
+		return nil, err
+	}
+
+	urlCache := map[string]error{}
+	var pending []batchItem
+	var created []domain.Job
+
+	reject := func(index int, err error) {
+		result.Items[index].JobID = nil
+		result.Items[index].Error = domain.NewBulkItemError(err)
+		result.Rejected++
+	}
+
+	// flush inserta el chunk pendiente. Si supera la cuota del tenant el chunk
+	// entero se rechaza y el resto del lote continúa.
+	flush := func(uow ports.IUnitOfWork) error {
+		chunk := pending
+		pending = nil
+		if len(chunk) == 0 {
+			return nil
+		}
+		// En all-or-nothing ya no se inserta nada tras el primer rechazo, solo se
+		// siguen validando items para informar de todos los errores
+		if opts.AllOrNothing && result.Rejected > 0 {
+			return nil
+		}
+
+		err := s.insertChunk(ctx, uow, result.ID, chunk)
+		if errors.Is(err, domain.ErrQuotaExceeded) {
+			for _, it := range chunk {
+				reject(it.index, err)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		result.Created += len(chunk)
+		for _, it := range chunk {
+			created = append(created, it.job)
+			if it.subscription != nil {
+				result.Items[it.index].NotifySecret = &it.subscription.Secret
+			}
+		}
+		return nil
+	}
+
+	process := func(uow ports.IUnitOfWork) error {
+		for input, decodeErr := range items {
+			index := len(result.Items)
+			if index >= domain.MaxBulkJobs {
+				result.Items = append(result.Items, domain.BulkItemResult{Index: index})
+				reject(index, fmt.Errorf("batches are limited to %d jobs: %w", domain.MaxBulkJobs, domain.ErrInvalid))
+				break
+			}
+
+			result.Items = append(result.Items, domain.BulkItemResult{Index: index})
+			if decodeErr != nil {
+				reject(index, decodeErr)
+				continue
+			}
+
+			input.BatchID = &result.ID
+			job, subscription, err := s.prepareJob(ctx, input, urlCache)
+			if err != nil {
+				reject(index, err)
+				continue
+			}
+
+			result.Items[index].JobID = &job.ID
+			pending = append(pending, batchItem{index: index, job: *job, subscription: subscription})
+			if len(pending) >= domain.BulkChunkSize {
+				if err := flush(uow); err != nil {
+					return err
+				}
+			}
+		}
+		if err := flush(uow); err != nil {
+			return err
+		}
+
+		if opts.AllOrNothing && result.Rejected > 0 {
+			return errBatchRejected
+		}
+		return nil
+	}
+
+	var err error
+	if opts.AllOrNothing {
+		err = s.uow.Atomic(ctx, process)
+	} else {
+		err = process(s.uow)
+	}
+
+	switch {
+	case errors.Is(err, errBatchRejected):
+		result.RolledBack = true
+		result.Created = 0
+		created = nil
+		for i := range result.Items {
+			result.Items[i].JobID = nil
+			result.Items[i].NotifySecret = nil
+		}
+	case err != nil:
+		return nil, err
+	}
+
+	// Sellado: desde aquí el lote puede completarse. Si todos sus jobs ya
+	// terminaron mientras llegaban items, se completa ahora
+	result.Total = len(result.Items)
+	result.UpdatedAt = s.clock.Now().UTC()
+	err = s.uow.Atomic(ctx, func(uow ports.IUnitOfWork) error {
+		batch, err := uow.JobBatch().Seal(ctx, result.JobBatch)
+		if err != nil {
+			return err
+		}
+		if err := s.completeBatch(ctx, uow, batch); err != nil {
+			return err
+		}
+		result.JobBatch = *batch
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range created {
+		s.metrics.JobCreated(job.Type)
+	}
+
+	s.logger.InfoContext(ctx, "job batch created",
+		"batch_id", result.ID,
+		"total", result.Total,
+		"created", result.Created,
+		"rejected", result.Rejected,
+		"rolled_back", result.RolledBack,
+	)
+
+	return result, nil
+}
+
+// insertChunk reserva la cuota de cada tenant del chunk e inserta sus jobs y
+// suscripciones en una transacción, sumándolos al lote en la misma.
+func (s *JobService) insertChunk(ctx context.Context, uow ports.IUnitOfWork, batchID uuid.UUID, chunk []batchItem) error {
+	perTenant := map[string]int{}
+	jobs := make([]domain.Job, 0, len(chunk))
+	for _, it := range chunk {
+		perTenant[it.job.Tenant]++
+		jobs = append(jobs, it.job)
+	}
+
+	// Orden fijo para que dos lotes concurrentes no se bloqueen mutuamente
+	tenants := make([]string, 0, len(perTenant))
+	for tenant := range perTenant {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+
+	return uow.Atomic(ctx, func(d ports.IUnitOfWork) error {
+		for _, tenant := range tenants {
+			ok, err := d.TenantQuota().Reserve(ctx, tenant, perTenant[tenant])
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("tenant %s has no room for %d more pending jobs: %w", tenant, perTenant[tenant], domain.ErrQuotaExceeded)
+			}
+		}
+
+		if err := d.Job().InsertMany(ctx, jobs); err != nil {
+			return err
+		}
+		if err := d.JobBatch().AddCreated(ctx, batchID, len(jobs)); err != nil {
+			return err
+		}
+		for _, it := range chunk {
+			if it.subscription != nil {
+				if err := d.WebhookSubscription().Insert(ctx, *it.subscription); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// recordBatchResult cuenta un job terminado (completed o dead) en su lote y
+// completa el lote si era el último. Corre en la transacción que cambia el
+// estado del job.
+func (s *JobService) recordBatchResult(ctx context.Context, uow ports.IUnitOfWork, job *domain.Job, succeeded bool) error {
+	if job.BatchID == nil {
+		return nil
+	}
+
+	batch, err := uow.JobBatch().RecordResult(ctx, *job.BatchID, succeeded)
+	if err != nil {
+		return err
+	}
+
+	return s.completeBatch(ctx, uow, batch)
+}
+
+// completeBatch marca el lote como terminado si todos sus jobs llegaron a un
+// estado final y, si tiene callback, encola el job que lo notifica. El UPDATE
+// condicional de MarkCompleted garantiza que el callback se encola una sola vez.
+func (s *JobService) completeBatch(ctx context.Context, uow ports.IUnitOfWork, batch *domain.JobBatch) error {
+	if !batch.Done() || batch.CompletedAt != nil {
+		return nil
+	}
+
+	done := *batch
+	now := s.clock.Now().UTC()
+	done.CompletedAt = &now
+
+	var callback *domain.Job
+	if done.CallbackURL != nil {
+		job, err := domain.NewBatchCompletedJob(done, now)
+		if err != nil {
+			return err
+		}
+		callback = job
+		done.CallbackJobID = &job.ID
+	}
+
+	ok, err := uow.JobBatch().MarkCompleted(ctx, done.ID, done.CallbackJobID, now)
+	if err != nil || !ok {
+		return err
+	}
+	*batch = done
+
+	s.logger.InfoContext(ctx, "job batch completed",
+		"batch_id", done.ID,
+		"outcome", done.Outcome(),
+		"succeeded", done.Succeeded,
+		"failed", done.Failed,
+	)
+
+	if callback != nil {
+		return uow.Job().Insert(ctx, *callback)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/service"
+	"slices"
+	"testing"
+	"time"
+)
+
+// El número de intento sale de Job.Attempts: un reintento diferido por rate
+// limit y vuelto a publicar por el dispatcher no arranca otra vez en 1.
+func TestAttemptNumberSurvivesDeferredRetry(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	limits := service.NewRateLimitService(env.uow, env.clock)
+	if _, err := limits.Put(ctx, domain.RateLimitInput{Scope: domain.RateLimitScopeType, Key: "email", RatePerSecond: 1, Burst: 1}); err != nil {
+		t.Fatal(err)
+	}
+	env.exec.respond(domain.ExecutionResult{HTTPStatus: 503, Error: errors.New("callback failed with status 503")})
+
+	msg := env.queueJob(t, jobInput())
+	env.process(t, msg)
+
+	job := env.job(t, msg.JobID)
+	if job.Status != domain.JobStatusFailed || job.Attempts != 1 {
+		t.Fatalf("job = %s with %d attempts, want failed with 1", job.Status, job.Attempts)
+	}
+	published := env.queue.messages()
+	if len(published) != 1 || published[0].Attempt != 2 {
+		t.Fatalf("published %+v, want the retry as attempt 2", published)
+	}
+
+	// El bucket está vacío: el reintento se difiere sin consumir un intento
+	env.process(t, published[0])
+	job = env.job(t, msg.JobID)
+	if job.Status != domain.JobStatusFailed || !job.RetryDeferred || job.Attempts != 1 {
+		t.Fatalf("job = %s deferred=%v with %d attempts, want a deferred failed job with 1", job.Status, job.RetryDeferred, job.Attempts)
+	}
+
+	// El dispatcher lo vuelve a publicar con un mensaje nuevo
+	env.clock.Advance(time.Second)
+	env.process(t, env.requeue(t, msg.JobID))
+
+	attempts, err := env.uow.Attempt().Get(ctx, domain.AttemptSearchParams{JobID: &msg.JobID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var numbers []int
+	for _, a := range attempts {
+		numbers = append(numbers, a.AttemptNumber)
+	}
+	if !slices.Equal(numbers, []int{1, 2}) {
+		t.Fatalf("attempt numbers = %v, want [1 2]", numbers)
+	}
+	if job := env.job(t, msg.JobID); job.Status != domain.JobStatusCompleted || job.Attempts != 2 {
+		t.Fatalf("job = %s with %d attempts, want completed with 2", job.Status, job.Attempts)
+	}
+}
@@ -0,0 +1,9 @@
+package service
+
+import "job_scheduler_go_rabbitmq/internal/core/domain"
+
+// noopJobMetrics se usa cuando no se configuran métricas.
+type noopJobMetrics struct{}
+
+func (noopJobMetrics) JobCreated(string)                    {}
+func (noopJobMetrics) JobFinished(string, domain.JobStatus) {}
@@ -0,0 +1,53 @@
+package service
+
+import "job_scheduler_go_rabbitmq/utils"
+
+const (
+	defaultPageLimit uint = 20
+	maxPageLimit     uint = 100
+)
+
+// pageLimit devuelve el límite efectivo de una búsqueda paginada.
+func pageLimit(params utils.SearchParams) uint {
+	if params.Limit != nil && *params.Limit > 0 {
+		return min(*params.Limit, maxPageLimit)
+	}
+	return defaultPageLimit
+}
+
+// paginate arma la respuesta paginada. items debe traer hasta limit+1
+// elementos: el extra solo indica que existe una página siguiente.
+func paginate[T any](items []T, total int, params utils.SearchParams, limit uint, cursorOf func(item T, field string) utils.Cursor) *utils.PaginatorWrapper[[]T] {
+	result := &utils.PaginatorWrapper[[]T]{
+		Total:      uint(total),
+		TotalPages: utils.TotalPages(uint(total), limit),
+		Limit:      limit,
+	}
+
+	if uint(len(items)) > limit {
+		items = items[:limit]
+
+		field, desc := "created_at", false
+		if params.Sort != nil && *params.Sort != "" {
+			field, desc = utils.ParseSort(*params.Sort)
+		}
+		cursor := cursorOf(items[len(items)-1], field)
+		cursor.Sort = utils.FormatSort(field, desc)
+		next := utils.EncodeCursor(cursor)
+		result.NextCursor = &next
+	}
+
+	if params.Cursor == nil || *params.Cursor == "" {
+		result.Page = 1
+		if params.Page != nil && *params.Page > 0 {
+			result.Page = *params.Page
+		}
+	}
+
+	if items == nil {
+		items = []T{}
+	}
+	result.Data = items
+
+	return result
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+)
+
+type RateLimitService struct {
+	uow   ports.IUnitOfWork
+	clock ports.IClock
+}
+
+// NewRateLimitService creates the service. clock may be nil to use the system clock.
+func NewRateLimitService(uow ports.IUnitOfWork, clock ports.IClock) *RateLimitService {
+	return &RateLimitService{uow: uow, clock: clockOrSystem(clock)}
+}
+
+var _ ports.IRateLimitService = (*RateLimitService)(nil)
+
+// List implements ports.IRateLimitService.
+// Los tokens se devuelven recalculados al momento de la consulta.
+func (s *RateLimitService) List(ctx context.Context, params domain.RateLimitSearchParams) ([]domain.RateLimit, error) {
+	limits, err := s.uow.RateLimit().Get(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	for i := range limits {
+		limits[i].Refill(now)
+	}
+
+	return limits, nil
+}
+
+// Put implements ports.IRateLimitService.
+func (s *RateLimitService) Put(ctx context.Context, input domain.RateLimitInput) (*domain.RateLimit, error) {
+	if err := requireOperator(ctx); err != nil {
+		return nil, err
+	}
+
+	limit, err := domain.NewRateLimit(input, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.uow.RateLimit().Upsert(ctx, *limit); err != nil {
+		return nil, err
+	}
+
+	return limit, nil
+}
+
+// Delete implements ports.IRateLimitService.
+func (s *RateLimitService) Delete(ctx context.Context, key domain.RateLimitKey) error {
+	if err := requireOperator(ctx); err != nil {
+		return err
+	}
+	return s.uow.RateLimit().Delete(ctx, key)
+}
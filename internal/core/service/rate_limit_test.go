@@ -0,0 +1,36 @@
+package service_test
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/service"
+	"testing"
+)
+
+// Un mensaje duplicado o de un job ya terminado no toma tokens del rate
+// limit: si los tomara, frenaría a los jobs que sí tienen que correr.
+func TestProcessJobMessageSkipsNonRunnable(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	limits := service.NewRateLimitService(env.uow, env.clock)
+	if _, err := limits.Put(ctx, domain.RateLimitInput{Scope: domain.RateLimitScopeType, Key: "email", RatePerSecond: 1, Burst: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	first := env.queueJob(t, jobInput())
+	second := env.queueJob(t, jobInput())
+
+	env.process(t, first)
+	env.process(t, first) // duplicado: el job ya está completed
+	env.process(t, second)
+
+	for _, msg := range []domain.RabbitJobMessage{first, second} {
+		if job := env.job(t, msg.JobID); job.Status != domain.JobStatusCompleted {
+			t.Fatalf("job %s status = %s, want completed", msg.JobID, job.Status)
+		}
+	}
+	if len(env.exec.calls) != 2 {
+		t.Fatalf("callback executed %d times, want 2", len(env.exec.calls))
+	}
+}
@@ -0,0 +1,151 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/internal/core/service"
+	"job_scheduler_go_rabbitmq/internal/infra/clock"
+	"job_scheduler_go_rabbitmq/internal/infra/driven/memory"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// testEnv es el JobService sobre el store en memoria, con un executor y una
+// cola falsos.
+type testEnv struct {
+	uow   ports.IUnitOfWork
+	clock *clock.Fake
+	exec  *fakeExecutor
+	queue *fakeQueue
+	jobs  *service.JobService
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	clk := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	uow := memory.NewDataStore(memory.NewStore(clk), nil)
+	exec := &fakeExecutor{}
+	queue := &fakeQueue{}
+	return &testEnv{
+		uow:   uow,
+		clock: clk,
+		exec:  exec,
+		queue: queue,
+		jobs:  service.NewJobService(uow, exec, queue, nil, clk, nil, nil),
+	}
+}
+
+func jobInput(mods ...func(*domain.CreateJobInput)) domain.CreateJobInput {
+	input := domain.CreateJobInput{
+		Type:        "email",
+		CallbackURL: "https://api.example.com/callback",
+		Payload:     json.RawMessage(`{}`),
+		MaxRetries:  3,
+	}
+	for _, mod := range mods {
+		mod(&input)
+	}
+	return input
+}
+
+// queueJob crea el job y lo encola como el dispatcher; devuelve su mensaje.
+func (e *testEnv) queueJob(t *testing.T, input domain.CreateJobInput) domain.RabbitJobMessage {
+	t.Helper()
+	ctx := context.Background()
+	created, err := e.jobs.Create(ctx, input)
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	return e.requeue(t, created.ID)
+}
+
+// requeue publica otra vez un job pending (o failed con el reintento
+// diferido), como el dispatcher; devuelve su mensaje.
+func (e *testEnv) requeue(t *testing.T, id uuid.UUID) domain.RabbitJobMessage {
+	t.Helper()
+	ctx := context.Background()
+	if err := e.uow.Job().LockJob(ctx, id, "dispatcher-test", time.Minute); err != nil {
+		t.Fatalf("lock job: %v", err)
+	}
+	if err := e.uow.Job().MarkQueued(ctx, id); err != nil {
+		t.Fatalf("queue job: %v", err)
+	}
+	return domain.NewRabbitJobMessageFromJob(e.job(t, id))
+}
+
+// process entrega msg al worker y falla si devuelve error.
+func (e *testEnv) process(t *testing.T, msg domain.RabbitJobMessage) {
+	t.Helper()
+	if err := e.jobs.ProcessJobMessage(context.Background(), msg); err != nil {
+		t.Fatalf("process job %s: %v", msg.JobID, err)
+	}
+}
+
+func (e *testEnv) job(t *testing.T, id uuid.UUID) domain.Job {
+	t.Helper()
+	job, err := e.uow.Job().GetOne(context.Background(), domain.JobSearchParams{ID: &id})
+	if err != nil {
+		t.Fatalf("get job %s: %v", id, err)
+	}
+	return *job
+}
+
+// fakeExecutor devuelve los resultados en orden; sin resultados pendientes,
+// el callback responde 200.
+type fakeExecutor struct {
+	mu      sync.Mutex
+	results []domain.ExecutionResult
+	calls   []uuid.UUID
+}
+
+func (f *fakeExecutor) Execute(ctx context.Context, job *domain.Job) domain.ExecutionResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, job.ID)
+	if len(f.results) == 0 {
+		return domain.ExecutionResult{HTTPStatus: 200}
+	}
+	result := f.results[0]
+	f.results = f.results[1:]
+	return result
+}
+
+func (f *fakeExecutor) respond(results ...domain.ExecutionResult) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results = append(f.results, results...)
+}
+
+// fakeQueue guarda los mensajes publicados.
+type fakeQueue struct {
+	mu        sync.Mutex
+	published []domain.RabbitJobMessage
+}
+
+var _ ports.IRabbitMQClient = (*fakeQueue)(nil)
+
+func (q *fakeQueue) Publish(msg domain.RabbitJobMessage) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.published = append(q.published, msg)
+	return nil
+}
+
+func (q *fakeQueue) Consume(func(domain.RabbitJobMessage)) error { return nil }
+func (q *fakeQueue) ConsumeTenant(string) error                  { return nil }
+func (q *fakeQueue) Close() error                                { return nil }
+
+func (q *fakeQueue) messages() []domain.RabbitJobMessage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]domain.RabbitJobMessage(nil), q.published...)
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"log/slog"
+)
+
+type StatsService struct {
+	uow    ports.IUnitOfWork
+	queue  ports.IQueueInspector
+	clock  ports.IClock
+	logger *slog.Logger
+}
+
+// NewStatsService creates the service. queue may be nil when the broker is not
+// available, clock may be nil to use the system clock and logger may be nil to
+// use slog.Default().
+func NewStatsService(uow ports.IUnitOfWork, queue ports.IQueueInspector, clock ports.IClock, logger *slog.Logger) *StatsService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &StatsService{uow: uow, queue: queue, clock: clockOrSystem(clock), logger: logger}
+}
+
+var _ ports.IStatsService = (*StatsService)(nil)
+
+// Get implements ports.IStatsService.
+func (s *StatsService) Get(ctx context.Context) (*domain.JobStats, error) {
+	now := s.clock.Now()
+
+	counts, err := s.uow.Stats().CountJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats := domain.NewJobStats(counts, now)
+
+	oldest, err := s.uow.Stats().OldestPendingSince(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	if oldest != nil {
+		age := now.Sub(*oldest).Seconds()
+		stats.OldestPendingAgeSeconds = &age
+	}
+
+	for _, window := range domain.StatsWindows {
+		throughput, err := s.uow.Stats().Throughput(ctx, now.Add(-window))
+		if err != nil {
+			return nil, err
+		}
+		throughput.Finish(window)
+		stats.Throughput = append(stats.Throughput, throughput)
+	}
+
+	durations, err := s.uow.Stats().Durations(ctx, now.Add(-domain.StatsDurationWindow))
+	if err != nil {
+		return nil, err
+	}
+	durations.Window = domain.StatsDurationWindow.String()
+	stats.Durations = durations
+
+	// La cola es informativa: si el broker no responde se devuelve el resto igual
+	if s.queue == nil {
+		msg := "queue inspector not configured"
+		stats.QueueError = &msg
+	} else if depth, err := s.queue.QueueDepth(ctx); err != nil {
+		s.logger.WarnContext(ctx, "failed to inspect queue", "error", err)
+		msg := "queue unavailable"
+		stats.QueueError = &msg
+	} else {
+		stats.Queue = depth
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,68 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/service"
+	"testing"
+	"time"
+)
+
+// Las estadísticas cuentan los jobs por estado y tipo, el atraso del pending
+// más viejo y los intentos de cada ventana.
+func TestStats(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	sms := func(in *domain.CreateJobInput) { in.Type = "sms" }
+	if _, err := env.jobs.Create(ctx, jobInput()); err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	env.process(t, env.queueJob(t, jobInput()))
+	env.exec.respond(domain.ExecutionResult{HTTPStatus: 503, Error: errors.New("callback failed with status 503")})
+	env.process(t, env.queueJob(t, jobInput(sms)))
+
+	env.clock.Advance(90 * time.Second)
+	stats, err := service.NewStatsService(env.uow, nil, env.clock, nil).Get(ctx)
+	if err != nil {
+		t.Fatalf("get stats: %v", err)
+	}
+
+	if stats.Total != 3 {
+		t.Fatalf("total = %d, want 3", stats.Total)
+	}
+	wantStatus := map[domain.JobStatus]int{domain.JobStatusPending: 1, domain.JobStatusCompleted: 1, domain.JobStatusFailed: 1}
+	for status, want := range wantStatus {
+		if got := stats.ByStatus[status]; got != want {
+			t.Fatalf("by_status[%s] = %d, want %d", status, got, want)
+		}
+	}
+	byType := map[string]int{}
+	for _, ts := range stats.ByType {
+		byType[ts.Type] = ts.Total
+	}
+	if byType["email"] != 2 || byType["sms"] != 1 {
+		t.Fatalf("by_type = %v, want email 2 and sms 1", byType)
+	}
+
+	if stats.OldestPendingAgeSeconds == nil || *stats.OldestPendingAgeSeconds != 90 {
+		t.Fatalf("oldest_pending_age_seconds = %v, want 90", stats.OldestPendingAgeSeconds)
+	}
+
+	if len(stats.Throughput) != len(domain.StatsWindows) {
+		t.Fatalf("got %d throughput windows, want %d", len(stats.Throughput), len(domain.StatsWindows))
+	}
+	for _, tp := range stats.Throughput {
+		if tp.Succeeded != 1 || tp.Failed != 1 || tp.FailureRate != 0.5 {
+			t.Fatalf("throughput %s = %+v, want 1 succeeded, 1 failed, failure rate 0.5", tp.Window, tp)
+		}
+	}
+	if stats.Durations.Samples != 2 {
+		t.Fatalf("duration samples = %d, want 2", stats.Durations.Samples)
+	}
+
+	if stats.Queue != nil || stats.QueueError == nil {
+		t.Fatalf("queue = %v, queue_error = %v, want only an error without inspector", stats.Queue, stats.QueueError)
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+)
+
+type TenantQuotaService struct {
+	uow   ports.IUnitOfWork
+	clock ports.IClock
+}
+
+// NewTenantQuotaService creates the service. clock may be nil to use the system clock.
+func NewTenantQuotaService(uow ports.IUnitOfWork, clock ports.IClock) *TenantQuotaService {
+	return &TenantQuotaService{uow: uow, clock: clockOrSystem(clock)}
+}
+
+var _ ports.ITenantQuotaService = (*TenantQuotaService)(nil)
+
+// List implements ports.ITenantQuotaService.
+func (s *TenantQuotaService) List(ctx context.Context, params domain.TenantQuotaSearchParams) ([]domain.TenantQuota, error) {
+	return s.uow.TenantQuota().Get(ctx, params)
+}
+
+// Put implements ports.ITenantQuotaService.
+func (s *TenantQuotaService) Put(ctx context.Context, input domain.TenantQuotaInput) (*domain.TenantQuota, error) {
+	if err := requireOperator(ctx); err != nil {
+		return nil, err
+	}
+
+	quota, err := domain.NewTenantQuota(input, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.uow.TenantQuota().Upsert(ctx, *quota); err != nil {
+		return nil, err
+	}
+
+	return quota, nil
+}
+
+// Delete implements ports.ITenantQuotaService.
+func (s *TenantQuotaService) Delete(ctx context.Context, tenant string) error {
+	if err := requireOperator(ctx); err != nil {
+		return err
+	}
+	return s.uow.TenantQuota().Delete(ctx, tenant)
+}
+
+// requireOperator rechaza a las keys de un tenant: cuotas y límites son
+// compartidos por todos los equipos y los fija el operador del deployment.
+func requireOperator(ctx context.Context) error {
+	if tenant := domain.TenantFrom(ctx); tenant != nil {
+		return fmt.Errorf("keys of tenant %s cannot change shared limits: %w", *tenant, domain.ErrForbidden)
+	}
+	return nil
+}
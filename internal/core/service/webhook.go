@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/utils"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+type WebhookService struct {
+	uow    ports.IUnitOfWork
+	sender ports.IWebhookSender
+	policy *domain.CallbackPolicy
+	clock  ports.IClock
+	logger *slog.Logger
+}
+
+// NewWebhookService creates the service. sender may be nil when the process
+// only manages subscriptions (API), policy may be nil to skip URL checks,
+// clock may be nil to use the system clock and logger may be nil to use
+// slog.Default().
+func NewWebhookService(uow ports.IUnitOfWork, sender ports.IWebhookSender, policy *domain.CallbackPolicy, clock ports.IClock, logger *slog.Logger) *WebhookService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &WebhookService{
+		uow:    uow,
+		sender: sender,
+		policy: policy,
+		clock:  clockOrSystem(clock),
+		logger: logger,
+	}
+}
+
+var _ ports.IWebhookService = (*WebhookService)(nil)
+var _ ports.IWebhookDeliveryService = (*WebhookService)(nil)
+
+// CreateSubscription implements ports.IWebhookService.
+func (s *WebhookService) CreateSubscription(ctx context.Context, input domain.WebhookSubscriptionInput) (*domain.CreatedWebhookSubscription, error) {
+	// La suscripción solo recibe eventos del tenant del request
+	if tenant := domain.TenantFrom(ctx); tenant != nil {
+		input.Tenant = *tenant
+	}
+
+	subscription, err := domain.NewWebhookSubscription(input, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if s.policy != nil {
+		if err := s.policy.Validate(ctx, subscription.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	if subscription.JobID != nil {
+		if _, err := s.uow.Job().GetOne(ctx, domain.JobSearchParams{ID: subscription.JobID}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.uow.WebhookSubscription().Insert(ctx, *subscription); err != nil {
+		return nil, err
+	}
+
+	return &domain.CreatedWebhookSubscription{WebhookSubscription: *subscription, Secret: subscription.Secret}, nil
+}
+
+// ListSubscriptions implements ports.IWebhookService.
+func (s *WebhookService) ListSubscriptions(ctx context.Context, params domain.WebhookSubscriptionSearchParams) ([]domain.WebhookSubscription, error) {
+	subscriptions, err := s.uow.WebhookSubscription().Get(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if subscriptions == nil {
+		subscriptions = []domain.WebhookSubscription{}
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteSubscription implements ports.IWebhookService.
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	return s.uow.Atomic(ctx, func(uow ports.IUnitOfWork) error {
+		// El Get aplica el filtro de tenant: no se pueden borrar suscripciones ajenas
+		subscriptions, err := uow.WebhookSubscription().Get(ctx, domain.WebhookSubscriptionSearchParams{ID: &id})
+		if err != nil {
+			return err
+		}
+		if len(subscriptions) == 0 {
+			return fmt.Errorf("webhook subscription %s: %w", id, domain.ErrNotFound)
+		}
+		return uow.WebhookSubscription().Delete(ctx, id)
+	})
+}
+
+// ListDeliveries implements ports.IWebhookService.
+func (s *WebhookService) ListDeliveries(ctx context.Context, params domain.WebhookDeliverySearchParams) (*utils.PaginatorWrapper[[]domain.WebhookDelivery], error) {
+	limit := pageLimit(params.SearchParams)
+
+	total, err := s.uow.WebhookDelivery().Count(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Se pide un elemento extra para saber si hay una página siguiente
+	fetch := limit + 1
+	params.Limit = &fetch
+
+	deliveries, err := s.uow.WebhookDelivery().Get(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return paginate(deliveries, total, params.SearchParams, limit, func(d domain.WebhookDelivery, field string) utils.Cursor {
+		return utils.Cursor{Value: d.SortValue(field), ID: d.ID.String()}
+	}), nil
+}
+
+// DeliverDue implements ports.IWebhookDeliveryService.
+// Las entregas se reservan con un lease y se envían fuera de transacción, así
+// un endpoint lento no mantiene filas bloqueadas.
+func (s *WebhookService) DeliverDue(ctx context.Context, limit int) (int, error) {
+	pending, err := s.uow.WebhookDelivery().Claim(ctx, limit, domain.WebhookDeliveryLease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, webhook := range pending {
+		result := s.sender.Send(ctx, webhook)
+
+		delivery := webhook.Delivery
+		delivery.RecordResult(result, s.clock.Now())
+
+		if err := s.uow.WebhookDelivery().Update(ctx, delivery); err != nil {
+			// La entrega vuelve a quedar vencida cuando expira el lease
+			s.logger.ErrorContext(ctx, "failed to update delivery", "delivery_id", delivery.ID, "error", err)
+			continue
+		}
+
+		if result.Error != nil {
+			s.logger.WarnContext(ctx, "delivery attempt failed", "delivery_id", delivery.ID, "attempt", delivery.Attempts, "error", result.Error)
+		}
+	}
+
+	return len(pending), nil
+}
@@ -0,0 +1,117 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/service"
+	"sync"
+	"testing"
+)
+
+// Una entrega fallida se reintenta con backoff, y una entrega reservada por
+// otro notifier no se vuelve a enviar hasta que vence su lease.
+func TestDeliverDue(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	sender := &fakeSender{}
+	webhooks := service.NewWebhookService(env.uow, sender, nil, env.clock, nil)
+
+	subscription, err := webhooks.CreateSubscription(ctx, domain.WebhookSubscriptionInput{URL: "https://hooks.example.com/jobs"})
+	if err != nil {
+		t.Fatalf("create subscription: %v", err)
+	}
+
+	deliverDue := func(t *testing.T, want int) {
+		t.Helper()
+		sent, err := webhooks.DeliverDue(ctx, 10)
+		if err != nil {
+			t.Fatalf("deliver due: %v", err)
+		}
+		if sent != want {
+			t.Fatalf("sent %d deliveries, want %d", sent, want)
+		}
+	}
+	delivery := func(t *testing.T) domain.WebhookDelivery {
+		t.Helper()
+		page, err := webhooks.ListDeliveries(ctx, domain.WebhookDeliverySearchParams{})
+		if err != nil {
+			t.Fatalf("list deliveries: %v", err)
+		}
+		if len(page.Data) != 1 {
+			t.Fatalf("got %d deliveries, want 1", len(page.Data))
+		}
+		return page.Data[0]
+	}
+
+	env.process(t, env.queueJob(t, jobInput()))
+
+	sender.respond(domain.ExecutionResult{HTTPStatus: 500, Error: errors.New("webhook failed with status 500")})
+	deliverDue(t, 1)
+	got := delivery(t)
+	if got.Status != domain.WebhookDeliveryPending || got.Attempts != 1 {
+		t.Fatalf("after a 500 delivery is %s with %d attempts, want pending with 1", got.Status, got.Attempts)
+	}
+	if want := env.clock.Now().Add(domain.WebhookRetryDelay(1)); got.NextAttemptAt == nil || !got.NextAttemptAt.Equal(want) {
+		t.Fatalf("next_attempt_at = %v, want %v", got.NextAttemptAt, want)
+	}
+	deliverDue(t, 0)
+
+	// Otro notifier reserva la entrega y se cae antes de registrar el resultado
+	env.clock.Advance(domain.WebhookRetryDelay(1))
+	if _, err := env.uow.WebhookDelivery().Claim(ctx, 10, domain.WebhookDeliveryLease); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	deliverDue(t, 0)
+
+	env.clock.Advance(domain.WebhookDeliveryLease)
+	deliverDue(t, 1)
+	if got := delivery(t); got.Status != domain.WebhookDeliveryDelivered || got.Attempts != 2 {
+		t.Fatalf("delivery is %s with %d attempts, want delivered with 2", got.Status, got.Attempts)
+	}
+
+	sent := sender.sent()
+	if len(sent) != 2 {
+		t.Fatalf("sender got %d webhooks, want 2", len(sent))
+	}
+	for _, webhook := range sent {
+		if webhook.Secret != subscription.Secret {
+			t.Fatalf("webhook signed with %q, want the subscription secret", webhook.Secret)
+		}
+		if webhook.Event.Type != domain.EventJobSucceeded {
+			t.Fatalf("event type = %s, want %s", webhook.Event.Type, domain.EventJobSucceeded)
+		}
+	}
+}
+
+// fakeSender devuelve los resultados en orden; sin resultados pendientes, el
+// endpoint responde 200.
+type fakeSender struct {
+	mu       sync.Mutex
+	results  []domain.ExecutionResult
+	webhooks []domain.PendingWebhook
+}
+
+func (f *fakeSender) Send(ctx context.Context, webhook domain.PendingWebhook) domain.ExecutionResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.webhooks = append(f.webhooks, webhook)
+	if len(f.results) == 0 {
+		return domain.ExecutionResult{HTTPStatus: 200}
+	}
+	result := f.results[0]
+	f.results = f.results[1:]
+	return result
+}
+
+func (f *fakeSender) respond(results ...domain.ExecutionResult) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results = append(f.results, results...)
+}
+
+func (f *fakeSender) sent() []domain.PendingWebhook {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]domain.PendingWebhook(nil), f.webhooks...)
+}
@@ -0,0 +1,59 @@
+// Package clock implements ports.IClock: the system clock used in production
+// and a fake clock that tests move by hand.
+package clock
+
+import (
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"sync"
+	"time"
+)
+
+// System devuelve la hora del sistema.
+type System struct{}
+
+var _ ports.IClock = System{}
+
+func NewSystem() System {
+	return System{}
+}
+
+// Now implements ports.IClock.
+func (System) Now() time.Time {
+	return time.Now()
+}
+
+// Fake es un reloj detenido que solo avanza con Advance o Set. Es seguro
+// usarlo desde varias goroutines.
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+var _ ports.IClock = (*Fake)(nil)
+
+// NewFake crea un reloj detenido en start.
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+// Now implements ports.IClock.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance adelanta el reloj d y devuelve la nueva hora.
+func (f *Fake) Advance(d time.Duration) time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	return f.now
+}
+
+// Set fija el reloj en t.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = t
+}
@@ -0,0 +1,190 @@
+package executor
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/internal/infra/clock"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// hostBreaker guarda el estado del circuito de un host.
+type hostBreaker struct {
+	state    domain.CircuitState
+	failures int
+	openedAt *time.Time
+	retryAt  *time.Time
+	inFlight int // pruebas en curso en half-open
+}
+
+// CircuitBreakers keeps one circuit breaker per callback host. State lives in
+// memory and is per process: workers do not share it. Every transition is also
+// published to the store under nodeID so the API can show it, so nodeID must
+// be unique per process.
+type CircuitBreakers struct {
+	mu       sync.Mutex
+	settings domain.CircuitBreakerSettings
+	hosts    map[string]*hostBreaker
+	store    ports.ICircuitBreakerRepository
+	nodeID   string
+	clock    ports.IClock
+	logger   *slog.Logger
+}
+
+// NewCircuitBreakers creates the breakers registry. store may be nil, clock may
+// be nil to use the system clock and logger may be nil to use slog.Default().
+func NewCircuitBreakers(settings domain.CircuitBreakerSettings, store ports.ICircuitBreakerRepository, nodeID string, clk ports.IClock, logger *slog.Logger) *CircuitBreakers {
+	if settings.FailureThreshold < 1 {
+		settings.FailureThreshold = 1
+	}
+	if settings.HalfOpenMaxCalls < 1 {
+		settings.HalfOpenMaxCalls = 1
+	}
+	if clk == nil {
+		clk = clock.NewSystem()
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &CircuitBreakers{
+		settings: settings,
+		hosts:    make(map[string]*hostBreaker),
+		store:    store,
+		nodeID:   nodeID,
+		clock:    clk,
+		logger:   logger,
+	}
+}
+
+// Allow decide si se puede ejecutar contra el host. Devuelve un
+// *domain.CircuitOpenError cuando el circuito corta la ejecución.
+func (c *CircuitBreakers) Allow(host string) error {
+	c.mu.Lock()
+
+	now := c.clock.Now()
+	b := c.get(host)
+	var changed *domain.CircuitBreaker
+	var err error
+
+	// Terminó el cool-down: se deja pasar alguna prueba contra el host
+	if b.state == domain.CircuitStateOpen && !now.Before(*b.retryAt) {
+		b.state = domain.CircuitStateHalfOpen
+		b.inFlight = 0
+		snapshot := c.snapshot(host, b, now)
+		changed = &snapshot
+	}
+
+	switch b.state {
+	case domain.CircuitStateOpen:
+		err = &domain.CircuitOpenError{Host: host, RetryAt: *b.retryAt}
+	case domain.CircuitStateHalfOpen:
+		if b.inFlight >= c.settings.HalfOpenMaxCalls {
+			err = &domain.CircuitOpenError{Host: host, RetryAt: now.Add(c.settings.CoolDown)}
+		} else {
+			b.inFlight++
+		}
+	}
+
+	c.mu.Unlock()
+
+	if changed != nil {
+		c.publish(*changed)
+	}
+	return err
+}
+
+// Record registra el resultado de una ejecución contra el host.
+func (c *CircuitBreakers) Record(host string, success bool) {
+	c.mu.Lock()
+
+	now := c.clock.Now()
+	b := c.get(host)
+	prev := b.state
+
+	if b.state == domain.CircuitStateHalfOpen && b.inFlight > 0 {
+		b.inFlight--
+	}
+
+	if success {
+		b.failures = 0
+		b.state = domain.CircuitStateClosed
+		b.openedAt = nil
+		b.retryAt = nil
+	} else {
+		b.failures++
+		if b.state != domain.CircuitStateClosed || b.failures >= c.settings.FailureThreshold {
+			retryAt := now.Add(c.settings.CoolDown)
+			b.state = domain.CircuitStateOpen
+			b.openedAt = &now
+			b.retryAt = &retryAt
+		}
+	}
+
+	// Solo se publican las transiciones (o la reapertura que extiende el cool-down)
+	changed := prev != b.state || b.state == domain.CircuitStateOpen
+	snapshot := c.snapshot(host, b, now)
+
+	c.mu.Unlock()
+
+	if changed {
+		c.publish(snapshot)
+	}
+}
+
+// Release devuelve la prueba de half-open que tomó Allow sin registrar un
+// resultado, para una ejecución que no llegó al host.
+func (c *CircuitBreakers) Release(host string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if b := c.get(host); b.state == domain.CircuitStateHalfOpen && b.inFlight > 0 {
+		b.inFlight--
+	}
+}
+
+// States returns a snapshot of every known breaker.
+func (c *CircuitBreakers) States() []domain.CircuitBreaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	states := make([]domain.CircuitBreaker, 0, len(c.hosts))
+	for host, b := range c.hosts {
+		states = append(states, c.snapshot(host, b, now))
+	}
+	return states
+}
+
+func (c *CircuitBreakers) get(host string) *hostBreaker {
+	b, ok := c.hosts[host]
+	if !ok {
+		b = &hostBreaker{state: domain.CircuitStateClosed}
+		c.hosts[host] = b
+	}
+	return b
+}
+
+func (c *CircuitBreakers) snapshot(host string, b *hostBreaker, now time.Time) domain.CircuitBreaker {
+	return domain.CircuitBreaker{
+		NodeID:    c.nodeID,
+		Host:      host,
+		State:     b.state,
+		Failures:  b.failures,
+		OpenedAt:  b.openedAt,
+		RetryAt:   b.retryAt,
+		UpdatedAt: now,
+	}
+}
+
+func (c *CircuitBreakers) publish(snapshot domain.CircuitBreaker) {
+	if c.store == nil {
+		return
+	}
+	ctx := context.Background()
+	if err := c.store.Upsert(ctx, snapshot); err != nil {
+		c.logger.ErrorContext(ctx, "failed to publish circuit breaker state", "host", snapshot.Host, "error", err)
+	}
+}
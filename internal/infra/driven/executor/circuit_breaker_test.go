@@ -0,0 +1,95 @@
+package executor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/infra/clock"
+)
+
+// halfOpen devuelve breakers con el circuito de host ya en half-open.
+func halfOpen(t *testing.T, host string) *CircuitBreakers {
+	t.Helper()
+	clk := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	c := NewCircuitBreakers(domain.CircuitBreakerSettings{
+		FailureThreshold: 1,
+		CoolDown:         time.Minute,
+		HalfOpenMaxCalls: 1,
+	}, nil, "node-1", clk, nil)
+
+	c.Record(host, false)
+	if err := c.Allow(host); err == nil {
+		t.Fatal("Allow() during the cool-down should fail")
+	}
+	clk.Advance(time.Minute)
+	if err := c.Allow(host); err != nil {
+		t.Fatalf("Allow() after the cool-down error = %v, want the probe", err)
+	}
+	return c
+}
+
+func TestCircuitBreakersHalfOpenProbe(t *testing.T) {
+	const host = "api.example.com"
+
+	tests := []struct {
+		name      string
+		finish    func(c *CircuitBreakers)
+		wantState domain.CircuitState
+		allowNext bool
+	}{
+		{
+			name:      "sin terminar la prueba no pasa otra",
+			finish:    func(c *CircuitBreakers) {},
+			wantState: domain.CircuitStateHalfOpen,
+			allowNext: false,
+		},
+		{
+			name:      "Release devuelve la prueba sin cerrar el circuito",
+			finish:    func(c *CircuitBreakers) { c.Release(host) },
+			wantState: domain.CircuitStateHalfOpen,
+			allowNext: true,
+		},
+		{
+			name:      "una prueba exitosa cierra el circuito",
+			finish:    func(c *CircuitBreakers) { c.Record(host, true) },
+			wantState: domain.CircuitStateClosed,
+			allowNext: true,
+		},
+		{
+			name:      "una prueba fallida lo vuelve a abrir",
+			finish:    func(c *CircuitBreakers) { c.Record(host, false) },
+			wantState: domain.CircuitStateOpen,
+			allowNext: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := halfOpen(t, host)
+			tt.finish(c)
+
+			if got := c.get(host).state; got != tt.wantState {
+				t.Fatalf("state = %s, want %s", got, tt.wantState)
+			}
+			err := c.Allow(host)
+			var openErr *domain.CircuitOpenError
+			if tt.allowNext && err != nil {
+				t.Fatalf("Allow() error = %v, want allowed", err)
+			}
+			if !tt.allowNext && !errors.As(err, &openErr) {
+				t.Fatalf("Allow() error = %v, want *domain.CircuitOpenError", err)
+			}
+		})
+	}
+}
+
+func TestReleaseOnClosedCircuit(t *testing.T) {
+	c := NewCircuitBreakers(domain.CircuitBreakerSettings{FailureThreshold: 2, CoolDown: time.Minute}, nil, "node-1", nil, nil)
+
+	c.Release("api.example.com")
+	if b := c.get("api.example.com"); b.state != domain.CircuitStateClosed || b.inFlight != 0 {
+		t.Fatalf("Release() on a closed circuit changed it: %+v", b)
+	}
+}
@@ -0,0 +1,77 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"net"
+	"net/http"
+	"time"
+)
+
+// newPolicyClient crea el cliente HTTP de las llamadas salientes. Con policy,
+// las direcciones se validan al conectar (después de resolver DNS) y en cada
+// redirect, así un host no puede apuntar a una IP interna.
+func newPolicyClient(policy *domain.CallbackPolicy, timeout time.Duration) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	if policy == nil {
+		return client
+	}
+
+	dialer := &policyDialer{
+		policy: policy,
+		dialer: &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	client.Transport = transport
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return policy.ValidateURL(req.URL.String())
+	}
+
+	return client
+}
+
+type policyDialer struct {
+	policy *domain.CallbackPolicy
+	dialer *net.Dialer
+}
+
+// DialContext resuelve el host, valida cada dirección contra la policy y se
+// conecta a la IP ya validada para evitar DNS rebinding.
+func (d *policyDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range addrs {
+		if err := d.policy.CheckAddress(host, a.IP); err != nil {
+			return nil, err
+		}
+	}
+
+	var lastErr error
+	for _, a := range addrs {
+		conn, err := d.dialer.DialContext(ctx, network, net.JoinHostPort(a.IP.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+
+	return nil, lastErr
+}
@@ -3,22 +3,47 @@ package executor
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/infra/metrics"
+	"job_scheduler_go_rabbitmq/internal/infra/tracing"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type HTTPExecutor struct {
-	client *http.Client
+	client   *http.Client
+	breakers *CircuitBreakers
+	policy   *domain.CallbackPolicy
 }
 
-func NewHTTPExecutor() *HTTPExecutor {
+// NewHTTPExecutor creates an executor. breakers may be nil to disable the
+// circuit breaker and policy may be nil to disable callback URL checks.
+// timeout bounds each callback; a callback that times out counts as a
+// failure of its host.
+func NewHTTPExecutor(breakers *CircuitBreakers, policy *domain.CallbackPolicy, timeout time.Duration) *HTTPExecutor {
 	return &HTTPExecutor{
-		client: &http.Client{},
+		client:   newPolicyClient(policy, timeout),
+		breakers: breakers,
+		policy:   policy,
 	}
 }
 
 func (e *HTTPExecutor) Execute(ctx context.Context, job *domain.Job) domain.ExecutionResult {
+	if e.policy != nil {
+		if err := e.policy.ValidateURL(job.CallbackURL); err != nil {
+			return domain.ExecutionResult{
+				HTTPStatus: 0,
+				Error:      err,
+			}
+		}
+	}
 
 	req, err := http.NewRequestWithContext(
 		ctx,
@@ -35,8 +60,46 @@ func (e *HTTPExecutor) Execute(ctx context.Context, job *domain.Job) domain.Exec
 
 	req.Header.Set("Content-Type", "application/json")
 
+	// Circuit breaker por host: si está abierto no se llega a hacer el request
+	host := callbackHost(job.CallbackURL)
+	if e.breakers != nil {
+		if err := e.breakers.Allow(host); err != nil {
+			return domain.ExecutionResult{
+				HTTPStatus: 0,
+				Error:      err,
+			}
+		}
+	}
+
+	ctx, span := tracing.Tracer().Start(ctx, "callback "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", host),
+			attribute.String("job.id", job.ID.String()),
+		),
+	)
+	req = req.WithContext(ctx)
+	tracing.InjectHTTP(ctx, req.Header)
+
+	start := time.Now()
 	resp, err := e.client.Do(req)
+	observeExecution(job.Type, start, resp, err)
+	if resp != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	}
+	tracing.End(span, err)
+	// La política cortó la conexión antes de llegar al host: no dice nada de
+	// su salud, pero la prueba de half-open que tomó Allow se devuelve igual
+	if errors.Is(err, domain.ErrCallbackNotAllowed) {
+		e.release(host)
+		return domain.ExecutionResult{
+			HTTPStatus: 0,
+			Error:      err,
+		}
+	}
 	if err != nil {
+		e.record(host, false)
 		return domain.ExecutionResult{
 			HTTPStatus: 0,
 			Error:      err,
@@ -44,6 +107,9 @@ func (e *HTTPExecutor) Execute(ctx context.Context, job *domain.Job) domain.Exec
 	}
 	defer resp.Body.Close()
 
+	// Los 4xx son errores del request, no del host; no abren el circuito
+	e.record(host, resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests)
+
 	// Siempre capturamos el status
 	if resp.StatusCode >= 400 {
 		return domain.ExecutionResult{
@@ -57,3 +123,33 @@ func (e *HTTPExecutor) Execute(ctx context.Context, job *domain.Job) domain.Exec
 		Error:      nil,
 	}
 }
+
+func (e *HTTPExecutor) record(host string, success bool) {
+	if e.breakers != nil {
+		e.breakers.Record(host, success)
+	}
+}
+
+func (e *HTTPExecutor) release(host string) {
+	if e.breakers != nil {
+		e.breakers.Release(host)
+	}
+}
+
+// observeExecution registra la latencia del callback. outcome es "error" si no
+// hubo respuesta o la clase del status (2xx, 4xx, 5xx...).
+func observeExecution(jobType string, start time.Time, resp *http.Response, err error) {
+	outcome := "error"
+	if err == nil {
+		outcome = fmt.Sprintf("%dxx", resp.StatusCode/100)
+	}
+	metrics.ExecutionDuration.WithLabelValues(jobType, outcome).Observe(time.Since(start).Seconds())
+}
+
+func callbackHost(callbackURL string) string {
+	u, err := url.Parse(callbackURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
@@ -0,0 +1,54 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/infra/clock"
+
+	"github.com/google/uuid"
+)
+
+// Un host que nunca responde corta por timeout y cuenta como falla para su
+// circuit breaker.
+func TestHTTPExecutorTimeout(t *testing.T) {
+	hang := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-hang:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(hang)
+
+	clk := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	breakers := NewCircuitBreakers(domain.CircuitBreakerSettings{
+		FailureThreshold: 1,
+		CoolDown:         time.Minute,
+		HalfOpenMaxCalls: 1,
+	}, nil, "node-1", clk, nil)
+	exec := NewHTTPExecutor(breakers, nil, 50*time.Millisecond)
+
+	job := &domain.Job{ID: uuid.New(), Type: "email", CallbackURL: srv.URL + "/hook", Payload: []byte(`{}`)}
+
+	done := make(chan domain.ExecutionResult, 1)
+	go func() { done <- exec.Execute(context.Background(), job) }()
+
+	select {
+	case result := <-done:
+		if result.Error == nil {
+			t.Fatalf("Execute() = %+v, want a timeout error", result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Execute() did not time out")
+	}
+
+	if err := breakers.Allow(callbackHost(job.CallbackURL)); err == nil {
+		t.Fatal("Allow() after a timeout should fail: the breaker should be open")
+	}
+}
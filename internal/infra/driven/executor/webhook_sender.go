@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// webhookTimeout limita cuánto espera el notifier a cada endpoint.
+const webhookTimeout = 10 * time.Second
+
+// WebhookSender posts job events to webhook subscribers. Every request carries
+// X-Webhook-Timestamp and X-Webhook-Signature (see domain.SignWebhook) so the
+// receiver can verify it came from us.
+type WebhookSender struct {
+	client *http.Client
+	policy *domain.CallbackPolicy
+}
+
+var _ ports.IWebhookSender = (*WebhookSender)(nil)
+
+// NewWebhookSender creates a sender. policy may be nil to disable URL checks.
+func NewWebhookSender(policy *domain.CallbackPolicy) *WebhookSender {
+	return &WebhookSender{
+		client: newPolicyClient(policy, webhookTimeout),
+		policy: policy,
+	}
+}
+
+// Send implements ports.IWebhookSender.
+func (s *WebhookSender) Send(ctx context.Context, webhook domain.PendingWebhook) domain.ExecutionResult {
+	delivery := webhook.Delivery
+
+	if s.policy != nil {
+		if err := s.policy.ValidateURL(delivery.URL); err != nil {
+			return domain.ExecutionResult{Error: err}
+		}
+	}
+
+	body, err := json.Marshal(webhook.Event)
+	if err != nil {
+		return domain.ExecutionResult{Error: err}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return domain.ExecutionResult{Error: err}
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Id", delivery.ID.String())
+	req.Header.Set("X-Webhook-Event", string(delivery.EventType))
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", domain.SignWebhook(webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return domain.ExecutionResult{Error: err}
+	}
+	defer resp.Body.Close()
+	// Se descarta el body para poder reutilizar la conexión
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return domain.ExecutionResult{
+			HTTPStatus: resp.StatusCode,
+			Error:      fmt.Errorf("webhook failed with status %d", resp.StatusCode),
+		}
+	}
+
+	return domain.ExecutionResult{HTTPStatus: resp.StatusCode}
+}
@@ -0,0 +1,63 @@
+package executor
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+// El receptor puede verificar la firma recalculando el HMAC de
+// "<timestamp>.<body>" con el secreto de la suscripción.
+func TestWebhookSenderSignature(t *testing.T) {
+	const secret = "whsec_test"
+
+	type request struct {
+		header http.Header
+		body   []byte
+	}
+	received := make(chan request, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- request{header: r.Header, body: body}
+	}))
+	defer srv.Close()
+
+	event := domain.Event{ID: uuid.New(), JobID: uuid.New(), Type: domain.EventJobSucceeded, Message: "job succeeded", CreatedAt: time.Now()}
+	webhook := domain.PendingWebhook{
+		Delivery: domain.WebhookDelivery{ID: uuid.New(), EventType: event.Type, URL: srv.URL + "/hook"},
+		Event:    event,
+		Secret:   secret,
+	}
+
+	result := NewWebhookSender(nil).Send(context.Background(), webhook)
+	if result.Error != nil || result.HTTPStatus != http.StatusOK {
+		t.Fatalf("Send() = %+v, want 200", result)
+	}
+
+	req := <-received
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(req.header.Get("X-Webhook-Timestamp") + "." + string(req.body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := req.header.Get("X-Webhook-Signature"); got != want {
+		t.Fatalf("X-Webhook-Signature = %q, want %q", got, want)
+	}
+
+	var got domain.Event
+	if err := json.Unmarshal(req.body, &got); err != nil || got.ID != event.ID {
+		t.Fatalf("body = %s, want event %s", req.body, event.ID)
+	}
+	if id := req.header.Get("X-Webhook-Id"); id != webhook.Delivery.ID.String() {
+		t.Fatalf("X-Webhook-Id = %q, want %s", id, webhook.Delivery.ID)
+	}
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyRepository struct {
+	store *Store
+	tx    *tx
+}
+
+func NewAPIKeyRepository(store *Store, t *tx) ports.IAPIKeyRepository {
+	return &APIKeyRepository{store: store, tx: t}
+}
+
+// Insert implements ports.IAPIKeyRepository.
+func (r *APIKeyRepository) Insert(ctx context.Context, key domain.APIKey) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, k := range r.store.apiKeys {
+		if k.ID == key.ID || k.Hash == key.Hash {
+			return fmt.Errorf("api key %s already exists: %w", key.ID, domain.ErrConflict)
+		}
+	}
+	put(r.tx, r.store.apiKeys, key.ID, key)
+
+	return nil
+}
+
+// Get implements ports.IAPIKeyRepository.
+func (r *APIKeyRepository) Get(ctx context.Context, params domain.APIKeySearchParams) ([]domain.APIKey, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.search(ctx, params), nil
+}
+
+// GetOne implements ports.IAPIKeyRepository.
+func (r *APIKeyRepository) GetOne(ctx context.Context, params domain.APIKeySearchParams) (*domain.APIKey, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	keys := r.search(ctx, params)
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("api key: %w", domain.ErrNotFound)
+	}
+
+	return &keys[0], nil
+}
+
+// Revoke implements ports.IAPIKeyRepository.
+// Las keys se revocan en lugar de borrarse para que los jobs sigan apuntando a su creador.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key, ok := r.store.apiKeys[id]
+	if !ok || key.RevokedAt != nil {
+		return fmt.Errorf("api key %s: %w", id, domain.ErrNotFound)
+	}
+	key.RevokedAt = &at
+	key.UpdatedAt = at
+	put(r.tx, r.store.apiKeys, id, key)
+
+	return nil
+}
+
+// TouchLastUsed implements ports.IAPIKeyRepository.
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key, ok := r.store.apiKeys[id]
+	if !ok || (key.LastUsedAt != nil && !key.LastUsedAt.Before(at.Add(-time.Minute))) {
+		return nil
+	}
+	key.LastUsedAt = &at
+	put(r.tx, r.store.apiKeys, id, key)
+
+	return nil
+}
+
+// search devuelve las keys que cumplen los filtros, ordenadas por created_at e id. Requiere mu.
+func (r *APIKeyRepository) search(ctx context.Context, params domain.APIKeySearchParams) []domain.APIKey {
+	var keys []domain.APIKey
+	for _, k := range r.store.apiKeys {
+		if !inTenant(ctx, k.Tenant, params.Tenant) {
+			continue
+		}
+		if params.ID != nil && k.ID != *params.ID {
+			continue
+		}
+		if params.Hash != nil && k.Hash != *params.Hash {
+			continue
+		}
+		if !params.IncludeRevoked && k.RevokedAt != nil {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+		}
+		return keys[i].ID.String() < keys[j].ID.String()
+	})
+
+	return keys
+}
@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AttemptRepository struct {
+	store *Store
+	tx    *tx
+}
+
+func NewAttemptRepository(store *Store, t *tx) ports.IAttemptRepository {
+	return &AttemptRepository{store: store, tx: t}
+}
+
+// attemptSortKeys son los campos de orden de domain.AttemptSortFields.
+var attemptSortKeys = map[string]sortKey[domain.Attempt]{
+	"created_at":     {sortTime, func(a domain.Attempt) any { return a.CreatedAt }},
+	"started_at":     {sortTime, func(a domain.Attempt) any { return a.StartedAt }},
+	"attempt_number": {sortInt, func(a domain.Attempt) any { return a.AttemptNumber }},
+}
+
+// Insert implements ports.IAttemptRepository.
+// El tenant se toma del job, como en el INSERT de Postgres.
+func (r *AttemptRepository) Insert(ctx context.Context, attempt domain.Attempt) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, ok := r.store.jobs[attempt.JobID]
+	if !ok {
+		return fmt.Errorf("error al insertar el attempt: job %s: %w", attempt.JobID, domain.ErrNotFound)
+	}
+	if _, ok := r.store.attempts[attempt.ID]; ok {
+		return fmt.Errorf("attempt %s already exists: %w", attempt.ID, domain.ErrConflict)
+	}
+
+	attempt.Tenant = job.Tenant
+	put(r.tx, r.store.attempts, attempt.ID, attempt)
+
+	return nil
+}
+
+// MarkSuccess implements ports.IAttemptRepository.
+func (r *AttemptRepository) MarkSuccess(ctx context.Context, attemptID uuid.UUID) error {
+	r.update(attemptID, func(a *domain.Attempt, now time.Time) {
+		a.Status = domain.AttemptStatusSuccess
+		a.FinishedAt = &now
+	})
+	return nil
+}
+
+// MarkFailed implements ports.IAttemptRepository.
+func (r *AttemptRepository) MarkFailed(ctx context.Context, attemptID uuid.UUID, errMsg string, httpStatus *int) error {
+	r.update(attemptID, func(a *domain.Attempt, now time.Time) {
+		a.Status = domain.AttemptStatusFailed
+		a.ErrorMessage = &errMsg
+		a.FinishedAt = &now
+		a.HTTPStatus = httpStatus
+	})
+	return nil
+}
+
+// update modifica el attempt si existe; como el UPDATE de Postgres, sin
+// attempt no hace nada.
+func (r *AttemptRepository) update(attemptID uuid.UUID, set func(a *domain.Attempt, now time.Time)) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	attempt, ok := r.store.attempts[attemptID]
+	if !ok {
+		return
+	}
+	set(&attempt, r.store.clock.Now())
+	put(r.tx, r.store.attempts, attemptID, attempt)
+}
+
+// Get implements ports.IAttemptRepository.
+func (r *AttemptRepository) Get(ctx context.Context, params domain.AttemptSearchParams) ([]domain.Attempt, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return paginate(r.search(ctx, params), params.SearchParams, attemptSortKeys, func(a domain.Attempt) uuid.UUID { return a.ID })
+}
+
+// Count implements ports.IAttemptRepository.
+func (r *AttemptRepository) Count(ctx context.Context, params domain.AttemptSearchParams) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return len(r.search(ctx, params)), nil
+}
+
+// search devuelve los attempts que cumplen los filtros. Requiere mu.
+func (r *AttemptRepository) search(ctx context.Context, params domain.AttemptSearchParams) []domain.Attempt {
+	var attempts []domain.Attempt
+	for _, a := range r.store.attempts {
+		if !inTenant(ctx, a.Tenant, params.Tenant) {
+			continue
+		}
+		if params.ID != nil && a.ID != *params.ID {
+			continue
+		}
+		if params.JobID != nil && a.JobID != *params.JobID {
+			continue
+		}
+		if params.JobType != nil && r.store.jobs[a.JobID].Type != *params.JobType {
+			continue
+		}
+		if params.Status != nil && a.Status != *params.Status {
+			continue
+		}
+		if params.HTTPStatus != nil && (a.HTTPStatus == nil || *a.HTTPStatus != *params.HTTPStatus) {
+			continue
+		}
+		if params.HTTPStatusClass != nil && (a.HTTPStatus == nil || *a.HTTPStatus/100 != *params.HTTPStatusClass) {
+			continue
+		}
+		if params.WorkerID != nil && (a.WorkerID == nil || *a.WorkerID != *params.WorkerID) {
+			continue
+		}
+		if params.StartedFrom != nil && a.StartedAt.Before(*params.StartedFrom) {
+			continue
+		}
+		if params.StartedTo != nil && !a.StartedAt.Before(*params.StartedTo) {
+			continue
+		}
+		if params.Q != nil && *params.Q != "" && (a.ErrorMessage == nil || !containsFold(*a.ErrorMessage, *params.Q)) {
+			continue
+		}
+		attempts = append(attempts, a)
+	}
+
+	return attempts
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"sort"
+)
+
+type CircuitBreakerRepository struct {
+	store *Store
+	tx    *tx
+}
+
+func NewCircuitBreakerRepository(store *Store, t *tx) ports.ICircuitBreakerRepository {
+	return &CircuitBreakerRepository{store: store, tx: t}
+}
+
+// Upsert implements ports.ICircuitBreakerRepository.
+func (r *CircuitBreakerRepository) Upsert(ctx context.Context, breaker domain.CircuitBreaker) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	put(r.tx, r.store.breakers, breakerKey{NodeID: breaker.NodeID, Host: breaker.Host}, breaker)
+
+	return nil
+}
+
+// Get implements ports.ICircuitBreakerRepository.
+func (r *CircuitBreakerRepository) Get(ctx context.Context, params domain.CircuitBreakerSearchParams) ([]domain.CircuitBreaker, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var breakers []domain.CircuitBreaker
+	for _, cb := range r.store.breakers {
+		if params.NodeID != nil && cb.NodeID != *params.NodeID {
+			continue
+		}
+		if params.Host != nil && cb.Host != *params.Host {
+			continue
+		}
+		if params.State != nil && cb.State != *params.State {
+			continue
+		}
+		breakers = append(breakers, cb)
+	}
+	sort.Slice(breakers, func(i, j int) bool {
+		if breakers[i].Host != breakers[j].Host {
+			return breakers[i].Host < breakers[j].Host
+		}
+		return breakers[i].NodeID < breakers[j].NodeID
+	})
+
+	return breakers, nil
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+type ConcurrencyLimitRepository struct {
+	store *Store
+	tx    *tx
+}
+
+func NewConcurrencyLimitRepository(store *Store, t *tx) ports.IConcurrencyLimitRepository {
+	return &ConcurrencyLimitRepository{store: store, tx: t}
+}
+
+// Upsert implements ports.IConcurrencyLimitRepository.
+func (r *ConcurrencyLimitRepository) Upsert(ctx context.Context, limit domain.ConcurrencyLimit) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if current, ok := r.store.concurrencyLimits[limit.JobType]; ok {
+		limit.CreatedAt = current.CreatedAt
+	}
+	limit.Running = 0
+	put(r.tx, r.store.concurrencyLimits, limit.JobType, limit)
+
+	return nil
+}
+
+// Get implements ports.IConcurrencyLimitRepository.
+func (r *ConcurrencyLimitRepository) Get(ctx context.Context, params domain.ConcurrencyLimitSearchParams) ([]domain.ConcurrencyLimit, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	since := r.store.clock.Now().Add(-domain.ConcurrencySlotLease)
+
+	var limits []domain.ConcurrencyLimit
+	for _, l := range r.store.concurrencyLimits {
+		if params.JobType != nil && l.JobType != *params.JobType {
+			continue
+		}
+		for _, s := range r.store.slots {
+			if s.JobType == l.JobType && !s.AcquiredAt.Before(since) {
+				l.Running++
+			}
+		}
+		limits = append(limits, l)
+	}
+	sort.Slice(limits, func(i, j int) bool { return limits[i].JobType < limits[j].JobType })
+
+	return limits, nil
+}
+
+// Delete implements ports.IConcurrencyLimitRepository.
+func (r *ConcurrencyLimitRepository) Delete(ctx context.Context, jobType string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	remove(r.tx, r.store.concurrencyLimits, jobType)
+
+	return nil
+}
+
+// AcquireSlot implements ports.IConcurrencyLimitRepository.
+func (r *ConcurrencyLimitRepository) AcquireSlot(ctx context.Context, jobType string, jobID uuid.UUID) (bool, error) {
+	if r.tx == nil {
+		return false, errors.New("acquire concurrency slot requires a transaction")
+	}
+
+	// El lock sobre el límite serializa a los workers que compiten por el mismo tipo
+	if _, err := r.store.lockRow(ctx, r.tx, "concurrency_limit:"+jobType); err != nil {
+		return false, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	limit, ok := r.store.concurrencyLimits[jobType]
+	if !ok {
+		// Tipo sin límite configurado
+		return true, nil
+	}
+
+	now := r.store.clock.Now()
+	running := 0
+	for id, s := range r.store.slots {
+		if s.JobType != jobType {
+			continue
+		}
+		// Liberar slots huérfanos de workers que murieron sin soltarlos
+		if s.AcquiredAt.Before(now.Add(-domain.ConcurrencySlotLease)) {
+			remove(r.tx, r.store.slots, id)
+			continue
+		}
+		running++
+	}
+	if running >= limit.MaxConcurrent {
+		return false, nil
+	}
+
+	// Otro worker ya tiene un slot para este mismo job (mensaje duplicado)
+	if _, ok := r.store.slots[jobID]; ok {
+		return false, nil
+	}
+	put(r.tx, r.store.slots, jobID, slot{JobType: jobType, AcquiredAt: now})
+
+	return true, nil
+}
+
+// ReleaseSlot implements ports.IConcurrencyLimitRepository.
+func (r *ConcurrencyLimitRepository) ReleaseSlot(ctx context.Context, jobID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	remove(r.tx, r.store.slots, jobID)
+
+	return nil
+}
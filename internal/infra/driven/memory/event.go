@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"slices"
+	"sort"
+)
+
+type EventRepository struct {
+	store *Store
+	tx    *tx
+}
+
+func NewEventRepository(store *Store, t *tx) ports.IEventRepository {
+	return &EventRepository{store: store, tx: t}
+}
+
+// Insert implements ports.IEventRepository.
+// Como el NOTIFY de Postgres, el evento llega a los subscribers del broker
+// recién cuando la transacción hace commit.
+func (r *EventRepository) Insert(ctx context.Context, event domain.Event) error {
+	r.store.mu.Lock()
+
+	job, ok := r.store.jobs[event.JobID]
+	if !ok {
+		r.store.mu.Unlock()
+		return fmt.Errorf("insert event: job %s: %w", event.JobID, domain.ErrNotFound)
+	}
+	if _, ok := r.store.events[event.ID]; ok {
+		r.store.mu.Unlock()
+		return fmt.Errorf("event %s already exists: %w", event.ID, domain.ErrConflict)
+	}
+
+	r.store.seq++
+	event.Seq = r.store.seq
+	event.Tenant = job.Tenant
+	put(r.tx, r.store.events, event.ID, event)
+	r.store.mu.Unlock()
+
+	if r.tx != nil {
+		r.tx.events = append(r.tx.events, event)
+	} else {
+		r.store.broker.publish(event)
+	}
+
+	return nil
+}
+
+// Get implements ports.IEventRepository.
+func (r *EventRepository) Get(ctx context.Context, params domain.EventSearchParams) ([]domain.Event, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var events []domain.Event
+	for _, event := range r.search(ctx, params) {
+		events = append(events, event.Event)
+	}
+
+	return events, nil
+}
+
+// GetStream implements ports.IEventRepository.
+func (r *EventRepository) GetStream(ctx context.Context, params domain.EventSearchParams) ([]domain.StreamEvent, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.search(ctx, params), nil
+}
+
+// search devuelve los eventos que cumplen los filtros, con los datos de su
+// job y ordenados por seq. Requiere mu.
+func (r *EventRepository) search(ctx context.Context, params domain.EventSearchParams) []domain.StreamEvent {
+	var events []domain.StreamEvent
+	for _, event := range r.store.events {
+		job, ok := r.store.jobs[event.JobID]
+		if !ok {
+			continue
+		}
+		if !inTenant(ctx, event.Tenant, params.Tenant) {
+			continue
+		}
+		if params.ID != nil && event.ID != *params.ID {
+			continue
+		}
+		if params.JobID != nil && event.JobID != *params.JobID {
+			continue
+		}
+		if len(params.Types) > 0 && !slices.Contains(params.Types, event.Type) {
+			continue
+		}
+		if params.Seq != nil && event.Seq != *params.Seq {
+			continue
+		}
+		if params.AfterSeq != nil && event.Seq <= *params.AfterSeq {
+			continue
+		}
+		if params.JobType != nil && job.Type != *params.JobType {
+			continue
+		}
+		if params.JobStatus != nil && job.Status != *params.JobStatus {
+			continue
+		}
+		events = append(events, domain.StreamEvent{Event: event, JobType: job.Type, JobStatus: job.Status})
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Seq < events[j].Seq })
+	if params.Limit != nil && int(*params.Limit) < len(events) {
+		events = events[:*params.Limit]
+	}
+
+	return events
+}
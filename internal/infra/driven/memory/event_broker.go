@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"sync"
+)
+
+// subscriberBuffer es cuántos eventos puede acumular un subscriber lento antes de ser desconectado.
+const subscriberBuffer = 64
+
+type eventSubscriber struct {
+	filter domain.EventStreamFilter
+	ch     chan domain.StreamEvent
+}
+
+// EventBroker reparte entre los subscribers los eventos que se confirman en
+// el Store. Es el equivalente en memoria de repositories.EventListener.
+type EventBroker struct {
+	store *Store
+	mu    sync.Mutex
+	subs  map[*eventSubscriber]struct{}
+}
+
+var _ ports.IEventBroker = (*EventBroker)(nil)
+
+func newEventBroker(store *Store) *EventBroker {
+	return &EventBroker{
+		store: store,
+		subs:  make(map[*eventSubscriber]struct{}),
+	}
+}
+
+// publish entrega el evento a los subscribers cuyo filtro lo acepta, con el
+// tipo y el estado que tiene su job en este momento.
+func (b *EventBroker) publish(event domain.Event) {
+	b.mu.Lock()
+	empty := len(b.subs) == 0
+	b.mu.Unlock()
+	if empty {
+		return
+	}
+
+	b.store.mu.Lock()
+	job, ok := b.store.jobs[event.JobID]
+	b.store.mu.Unlock()
+	if !ok {
+		return
+	}
+	stream := domain.StreamEvent{Event: event, JobType: job.Type, JobStatus: job.Status}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		if !sub.filter.Matches(stream) {
+			continue
+		}
+		select {
+		case sub.ch <- stream:
+		default:
+			// Subscriber atrasado: se desconecta y puede reanudar con Last-Event-ID
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// Subscribe implements ports.IEventBroker.
+func (b *EventBroker) Subscribe(filter domain.EventStreamFilter) (<-chan domain.StreamEvent, func()) {
+	sub := &eventSubscriber{
+		filter: filter,
+		ch:     make(chan domain.StreamEvent, subscriberBuffer),
+	}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[sub]; ok {
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+
+	return sub.ch, unsubscribe
+}
+
+// Close cierra todos los subscribers.
+func (b *EventBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
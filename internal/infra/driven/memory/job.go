@@ -0,0 +1,343 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobRepository struct {
+	store *Store
+	tx    *tx
+}
+
+func NewJobRepository(store *Store, t *tx) ports.IJobRepository {
+	return &JobRepository{store: store, tx: t}
+}
+
+// jobSortKeys son los campos de orden de domain.JobSortFields.
+var jobSortKeys = map[string]sortKey[domain.Job]{
+	"created_at":   {sortTime, func(j domain.Job) any { return j.CreatedAt }},
+	"updated_at":   {sortTime, func(j domain.Job) any { return j.UpdatedAt }},
+	"scheduled_at": {sortTime, func(j domain.Job) any { return timeOrNull(j.ScheduledAt) }},
+	"priority":     {sortInt, func(j domain.Job) any { return j.Priority }},
+	"type":         {sortText, func(j domain.Job) any { return j.Type }},
+	"status":       {sortText, func(j domain.Job) any { return string(j.Status) }},
+}
+
+func jobID(j domain.Job) uuid.UUID { return j.ID }
+
+// Insert implements ports.IJobRepository.
+func (r *JobRepository) Insert(ctx context.Context, job domain.Job) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.jobs[job.ID]; ok {
+		return fmt.Errorf("job %s already exists: %w", job.ID, domain.ErrConflict)
+	}
+	put(r.tx, r.store.jobs, job.ID, job)
+
+	return nil
+}
+
+// InsertMany implements ports.IJobRepository.
+// Como el COPY de Postgres, un id repetido hace fallar la inserción entera.
+func (r *JobRepository) InsertMany(ctx context.Context, jobs []domain.Job) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	seen := make(map[uuid.UUID]bool, len(jobs))
+	for _, job := range jobs {
+		if _, ok := r.store.jobs[job.ID]; ok || seen[job.ID] {
+			return fmt.Errorf("copy jobs failed: job %s already exists: %w", job.ID, domain.ErrConflict)
+		}
+		seen[job.ID] = true
+	}
+	for _, job := range jobs {
+		put(r.tx, r.store.jobs, job.ID, job)
+	}
+
+	return nil
+}
+
+// Update implements ports.IJobRepository.
+func (r *JobRepository) Update(ctx context.Context, job domain.Job, prevUpdatedAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.jobs[job.ID]
+	if !ok || !current.UpdatedAt.Equal(prevUpdatedAt) || !slices.Contains(domain.EditableJobStatuses, current.Status) {
+		return fmt.Errorf("job %s was modified concurrently: %w", job.ID, domain.ErrPreconditionFailed)
+	}
+
+	current.ScheduledAt = job.ScheduledAt
+	current.Priority = job.Priority
+	current.MaxRetries = job.MaxRetries
+	current.Payload = job.Payload
+	current.CallbackURL = job.CallbackURL
+	current.UpdatedAt = job.UpdatedAt
+	put(r.tx, r.store.jobs, job.ID, current)
+
+	return nil
+}
+
+// GetOne implements ports.IJobRepository.
+func (r *JobRepository) GetOne(ctx context.Context, params domain.JobSearchParams) (*domain.Job, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	jobs := r.search(ctx, params)
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("job: %w", domain.ErrNotFound)
+	}
+
+	return &jobs[0], nil
+}
+
+// Get implements ports.IJobRepository.
+func (r *JobRepository) Get(ctx context.Context, params domain.JobSearchParams) ([]domain.Job, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return paginate(r.search(ctx, params), params.SearchParams, jobSortKeys, jobID)
+}
+
+// Count implements ports.IJobRepository.
+func (r *JobRepository) Count(ctx context.Context, params domain.JobSearchParams) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return len(r.search(ctx, params)), nil
+}
+
+// search devuelve los jobs que cumplen los filtros, ordenados por created_at e id. Requiere mu.
+func (r *JobRepository) search(ctx context.Context, params domain.JobSearchParams) []domain.Job {
+	now := r.store.clock.Now()
+
+	var jobs []domain.Job
+	for _, job := range r.store.jobs {
+		if matchJob(ctx, job, params, now) {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+		}
+		return jobs[i].ID.String() < jobs[j].ID.String()
+	})
+
+	// Reparto por tenant: como mucho PerTenantLimit jobs de cada tenant
+	if params.PerTenantLimit != nil {
+		perTenant := map[string]uint{}
+		limited := jobs[:0]
+		for _, job := range jobs {
+			if perTenant[job.Tenant] < *params.PerTenantLimit {
+				perTenant[job.Tenant]++
+				limited = append(limited, job)
+			}
+		}
+		jobs = limited
+	}
+
+	return jobs
+}
+
+func matchJob(ctx context.Context, job domain.Job, params domain.JobSearchParams, now time.Time) bool {
+	if !inTenant(ctx, job.Tenant, params.Tenant) {
+		return false
+	}
+	if params.ID != nil && job.ID != *params.ID {
+		return false
+	}
+	if params.Status != nil && job.Status != *params.Status {
+		return false
+	}
+	if params.Dispatchable != nil && *params.Dispatchable && !dispatchable(job) {
+		return false
+	}
+	if params.Type != nil && job.Type != *params.Type {
+		return false
+	}
+	if len(params.Statuses) > 0 && !slices.Contains(params.Statuses, job.Status) {
+		return false
+	}
+	if params.CreatedFrom != nil && job.CreatedAt.Before(*params.CreatedFrom) {
+		return false
+	}
+	if params.CreatedTo != nil && !job.CreatedAt.Before(*params.CreatedTo) {
+		return false
+	}
+	if params.ScheduledFrom != nil && (job.ScheduledAt == nil || job.ScheduledAt.Before(*params.ScheduledFrom)) {
+		return false
+	}
+	if params.ScheduledTo != nil && (job.ScheduledAt == nil || !job.ScheduledAt.Before(*params.ScheduledTo)) {
+		return false
+	}
+	if params.CreatedByKeyID != nil && (job.CreatedByKeyID == nil || *job.CreatedByKeyID != *params.CreatedByKeyID) {
+		return false
+	}
+	if params.BatchID != nil && (job.BatchID == nil || *job.BatchID != *params.BatchID) {
+		return false
+	}
+	if params.Q != nil && *params.Q != "" && !containsFold(job.Type, *params.Q) && !containsFold(string(job.Payload), *params.Q) {
+		return false
+	}
+	if params.ReadyToRun != nil && *params.ReadyToRun && job.ScheduledAt != nil && job.ScheduledAt.After(now) {
+		return false
+	}
+	if params.LockFree != nil && *params.LockFree && job.LockedAt != nil {
+		if params.LockTimeout == nil || !job.LockedAt.Before(now.Add(-*params.LockTimeout)) {
+			return false
+		}
+	}
+	return true
+}
+
+// QueuedTenants implements ports.IJobRepository.
+func (r *JobRepository) QueuedTenants(ctx context.Context) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	seen := map[string]bool{}
+	var tenants []string
+	for _, job := range r.store.jobs {
+		// Un failed sin RetryDeferred tiene su reintento en la cola del tenant
+		inQueue := job.Status == domain.JobStatusQueued || (job.Status == domain.JobStatusFailed && !job.RetryDeferred)
+		if inQueue && !seen[job.Tenant] {
+			seen[job.Tenant] = true
+			tenants = append(tenants, job.Tenant)
+		}
+	}
+	sort.Strings(tenants)
+
+	return tenants, nil
+}
+
+// LockJob implements ports.IJobRepository.
+func (r *JobRepository) LockJob(ctx context.Context, jobID uuid.UUID, lockedBy string, lockTimeout time.Duration) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := r.store.clock.Now()
+	job, ok := r.store.jobs[jobID]
+	if !ok || !dispatchable(job) || (job.LockedAt != nil && !job.LockedAt.Before(now.Add(-lockTimeout))) {
+		return fmt.Errorf("job %s already locked or not pending: %w", jobID, domain.ErrConflict)
+	}
+
+	job.LockedAt = &now
+	job.LockedBy = &lockedBy
+	job.UpdatedAt = now
+	put(r.tx, r.store.jobs, jobID, job)
+
+	return nil
+}
+
+// MarkCompleted implements ports.IJobRepository.
+func (r *JobRepository) MarkCompleted(ctx context.Context, jobID uuid.UUID) error {
+	return r.transition(jobID, domain.JobStatusCompleted, func(job *domain.Job, now time.Time) {
+		job.CompletedAt = &now
+		job.Attempts++
+		unlock(job)
+	})
+}
+
+// MarkFailed implements ports.IJobRepository.
+func (r *JobRepository) MarkFailed(ctx context.Context, jobID uuid.UUID, errMsg string, httpStatus *int) error {
+	return r.transition(jobID, domain.JobStatusFailed, func(job *domain.Job, now time.Time) {
+		job.Attempts++
+		unlock(job)
+	})
+}
+
+// MarkQueued implements ports.IJobRepository.
+func (r *JobRepository) MarkQueued(ctx context.Context, jobID uuid.UUID) error {
+	return r.transition(jobID, domain.JobStatusQueued, func(job *domain.Job, now time.Time) {
+		job.RetryDeferred = false
+	})
+}
+
+// MarkRunning implements ports.IJobRepository.
+func (r *JobRepository) MarkRunning(ctx context.Context, jobID uuid.UUID) error {
+	return r.transition(jobID, domain.JobStatusRunning, nil)
+}
+
+// MarkDead implements ports.IJobRepository.
+func (r *JobRepository) MarkDead(ctx context.Context, jobID uuid.UUID, reason string) error {
+	return r.transition(jobID, domain.JobStatusDead, func(job *domain.Job, now time.Time) {
+		job.Attempts++
+		unlock(job)
+	})
+}
+
+// Reschedule implements ports.IJobRepository.
+func (r *JobRepository) Reschedule(ctx context.Context, jobID uuid.UUID, runAt time.Time) error {
+	return r.transition(jobID, domain.JobStatusPending, func(job *domain.Job, now time.Time) {
+		job.ScheduledAt = &runAt
+		job.RetryDeferred = false
+		unlock(job)
+	})
+}
+
+// DeferRetry implements ports.IJobRepository.
+func (r *JobRepository) DeferRetry(ctx context.Context, jobID uuid.UUID, runAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, ok := r.store.jobs[jobID]
+	if !ok {
+		return fmt.Errorf("job %s: %w", jobID, domain.ErrNotFound)
+	}
+	if job.Status != domain.JobStatusFailed {
+		return &domain.TransitionError{JobID: jobID, From: job.Status, To: domain.JobStatusFailed}
+	}
+
+	job.ScheduledAt = &runAt
+	job.RetryDeferred = true
+	job.UpdatedAt = r.store.clock.Now()
+	unlock(&job)
+	put(r.tx, r.store.jobs, jobID, job)
+
+	return nil
+}
+
+// dispatchable: pending, o failed con el reintento diferido.
+func dispatchable(job domain.Job) bool {
+	return job.Status == domain.JobStatusPending || (job.Status == domain.JobStatusFailed && job.RetryDeferred)
+}
+
+func unlock(job *domain.Job) {
+	job.LockedAt = nil
+	job.LockedBy = nil
+}
+
+// transition pasa el job al estado to si la máquina de estados del dominio lo
+// permite desde su estado actual, y aplica set con el timestamp de la transición.
+func (r *JobRepository) transition(jobID uuid.UUID, to domain.JobStatus, set func(job *domain.Job, now time.Time)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, ok := r.store.jobs[jobID]
+	if !ok {
+		return fmt.Errorf("job %s: %w", jobID, domain.ErrNotFound)
+	}
+	if !job.Status.CanTransitionTo(to) {
+		return &domain.TransitionError{JobID: jobID, From: job.Status, To: to}
+	}
+
+	now := r.store.clock.Now()
+	job.Status = to
+	job.UpdatedAt = now
+	if set != nil {
+		set(&job, now)
+	}
+	put(r.tx, r.store.jobs, jobID, job)
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobBatchRepository struct {
+	store *Store
+	tx    *tx
+}
+
+func NewJobBatchRepository(store *Store, t *tx) ports.IJobBatchRepository {
+	return &JobBatchRepository{store: store, tx: t}
+}
+
+// Insert implements ports.IJobBatchRepository.
+func (r *JobBatchRepository) Insert(ctx context.Context, batch domain.JobBatch) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.batches[batch.ID]; ok {
+		return fmt.Errorf("job batch %s already exists: %w", batch.ID, domain.ErrConflict)
+	}
+	put(r.tx, r.store.batches, batch.ID, batch)
+
+	return nil
+}
+
+// GetOne implements ports.IJobBatchRepository.
+func (r *JobBatchRepository) GetOne(ctx context.Context, params domain.JobBatchSearchParams) (*domain.JobBatch, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, batch := range r.store.batches {
+		if !inTenant(ctx, batch.Tenant, params.Tenant) {
+			continue
+		}
+		if params.ID != nil && batch.ID != *params.ID {
+			continue
+		}
+		return &batch, nil
+	}
+
+	return nil, fmt.Errorf("job batch: %w", domain.ErrNotFound)
+}
+
+// CountJobs implements ports.IJobBatchRepository.
+func (r *JobBatchRepository) CountJobs(ctx context.Context, batchID uuid.UUID) (map[domain.JobStatus]int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	counts := map[domain.JobStatus]int{}
+	for _, job := range r.store.jobs {
+		if job.BatchID != nil && *job.BatchID == batchID {
+			counts[job.Status]++
+		}
+	}
+
+	return counts, nil
+}
+
+// AddCreated implements ports.IJobBatchRepository.
+func (r *JobBatchRepository) AddCreated(ctx context.Context, batchID uuid.UUID, n int) error {
+	_, err := r.update(ctx, batchID, func(b *domain.JobBatch) {
+		b.Created += n
+	})
+	return err
+}
+
+// Seal implements ports.IJobBatchRepository.
+func (r *JobBatchRepository) Seal(ctx context.Context, batch domain.JobBatch) (*domain.JobBatch, error) {
+	return r.update(ctx, batch.ID, func(b *domain.JobBatch) {
+		b.Total = batch.Total
+		b.Rejected = batch.Rejected
+		b.SealedAt = &batch.UpdatedAt
+		b.UpdatedAt = batch.UpdatedAt
+	})
+}
+
+// RecordResult implements ports.IJobBatchRepository.
+func (r *JobBatchRepository) RecordResult(ctx context.Context, batchID uuid.UUID, succeeded bool) (*domain.JobBatch, error) {
+	now := r.store.clock.Now().UTC()
+	return r.update(ctx, batchID, func(b *domain.JobBatch) {
+		if succeeded {
+			b.Succeeded++
+		} else {
+			b.Failed++
+		}
+		b.UpdatedAt = now
+	})
+}
+
+// MarkCompleted implements ports.IJobBatchRepository.
+func (r *JobBatchRepository) MarkCompleted(ctx context.Context, batchID uuid.UUID, callbackJobID *uuid.UUID, at time.Time) (bool, error) {
+	var completed bool
+	_, err := r.update(ctx, batchID, func(b *domain.JobBatch) {
+		if b.CompletedAt != nil {
+			return
+		}
+		b.CompletedAt = &at
+		b.CallbackJobID = callbackJobID
+		b.UpdatedAt = at
+		completed = true
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return completed, nil
+}
+
+// update aplica set al lote con su fila bloqueada hasta el fin de la
+// transacción, como el UPDATE de Postgres, y devuelve el lote actualizado.
+func (r *JobBatchRepository) update(ctx context.Context, batchID uuid.UUID, set func(b *domain.JobBatch)) (*domain.JobBatch, error) {
+	release, err := r.store.lockRow(ctx, r.tx, "job_batch:"+batchID.String())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	batch, ok := r.store.batches[batchID]
+	if !ok {
+		return nil, fmt.Errorf("job batch %s: %w", batchID, domain.ErrNotFound)
+	}
+	set(&batch)
+	put(r.tx, r.store.batches, batchID, batch)
+
+	return &batch, nil
+}
@@ -0,0 +1,21 @@
+package memory_test
+
+import (
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/internal/core/ports/portstest"
+	"job_scheduler_go_rabbitmq/internal/infra/driven/memory"
+	"testing"
+)
+
+func TestRepositories(t *testing.T) {
+	portstest.TestRepositories(t, func(t *testing.T) ports.IUnitOfWork {
+		return memory.NewDataStore(memory.NewStore(nil), nil)
+	})
+}
+
+func TestEventBroker(t *testing.T) {
+	portstest.TestEventBroker(t, func(t *testing.T) (ports.IUnitOfWork, ports.IEventBroker) {
+		store := memory.NewStore(nil)
+		return memory.NewDataStore(store, nil), store.Broker()
+	})
+}
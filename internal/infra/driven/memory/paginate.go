@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"cmp"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/utils"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sortKind es el tipo de la columna de orden, para interpretar el cursor.
+type sortKind int
+
+const (
+	sortTime sortKind = iota
+	sortInt
+	sortText
+)
+
+// sortKey es una columna por la que se puede ordenar y paginar.
+type sortKey[T any] struct {
+	kind  sortKind
+	value func(T) any // time.Time, int o string según kind
+}
+
+// nullTime ocupa el lugar de un timestamp NULL: Postgres ordena los NULL
+// después de cualquier valor.
+var nullTime = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+
+func timeOrNull(t *time.Time) time.Time {
+	if t == nil {
+		return nullTime
+	}
+	return *t
+}
+
+// paginate ordena rows como el ORDER BY de los repositorios de Postgres
+// (campo y luego id) y aplica cursor, límite y offset.
+func paginate[T any](rows []T, params utils.SearchParams, keys map[string]sortKey[T], id func(T) uuid.UUID) ([]T, error) {
+	field, desc := "created_at", false
+	if params.Sort != nil && *params.Sort != "" {
+		field, desc = utils.ParseSort(*params.Sort)
+	}
+	key, ok := keys[field]
+	if !ok {
+		return nil, fmt.Errorf("sort field %q: %w", field, domain.ErrInvalid)
+	}
+
+	compare := func(value any, rowID uuid.UUID, other any, otherID uuid.UUID) int {
+		c := compareValues(value, other)
+		if c == 0 {
+			c = strings.Compare(rowID.String(), otherID.String())
+		}
+		if desc {
+			return -c
+		}
+		return c
+	}
+
+	slices.SortStableFunc(rows, func(a, b T) int {
+		return compare(key.value(a), id(a), key.value(b), id(b))
+	})
+
+	if params.Cursor != nil && *params.Cursor != "" {
+		cursor, err := utils.DecodeCursor(*params.Cursor, utils.FormatSort(field, desc))
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", err, domain.ErrInvalid)
+		}
+		value, err := parseCursorValue(key.kind, cursor.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %v: %w", err, domain.ErrInvalid)
+		}
+		cursorID, err := uuid.Parse(cursor.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %v: %w", err, domain.ErrInvalid)
+		}
+
+		after := rows[:0:0]
+		for _, row := range rows {
+			if compare(key.value(row), id(row), value, cursorID) > 0 {
+				after = append(after, row)
+			}
+		}
+		rows = after
+	}
+
+	if params.Limit != nil {
+		offset := 0
+		if params.Page != nil && *params.Page > 1 && (params.Cursor == nil || *params.Cursor == "") {
+			offset = int((*params.Page - 1) * *params.Limit)
+		}
+		rows = rows[min(offset, len(rows)):]
+		rows = rows[:min(int(*params.Limit), len(rows))]
+	}
+
+	return rows, nil
+}
+
+func compareValues(a, b any) int {
+	switch a := a.(type) {
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	case int:
+		return cmp.Compare(a, b.(int))
+	case string:
+		return strings.Compare(a, b.(string))
+	}
+	return 0
+}
+
+func parseCursorValue(kind sortKind, raw string) (any, error) {
+	switch kind {
+	case sortTime:
+		if raw == "" {
+			return nullTime, nil
+		}
+		return time.Parse(time.RFC3339Nano, raw)
+	case sortInt:
+		return strconv.Atoi(raw)
+	default:
+		return raw, nil
+	}
+}
+
+// containsFold es el ILIKE '%q%' de los repositorios de Postgres.
+func containsFold(s, q string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(q))
+}
@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"sort"
+	"time"
+)
+
+type RateLimitRepository struct {
+	store *Store
+	tx    *tx
+}
+
+func NewRateLimitRepository(store *Store, t *tx) ports.IRateLimitRepository {
+	return &RateLimitRepository{store: store, tx: t}
+}
+
+func rateLimitKeyOf(l domain.RateLimit) domain.RateLimitKey {
+	return domain.RateLimitKey{Scope: l.Scope, Key: l.Key}
+}
+
+// Upsert implements ports.IRateLimitRepository.
+// Un bucket existente conserva sus tokens, recortados al nuevo burst.
+func (r *RateLimitRepository) Upsert(ctx context.Context, limit domain.RateLimit) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := rateLimitKeyOf(limit)
+	if current, ok := r.store.rateLimits[key]; ok {
+		current.RatePerSecond = limit.RatePerSecond
+		current.Burst = limit.Burst
+		current.Tokens = min(current.Tokens, float64(limit.Burst))
+		current.UpdatedAt = limit.UpdatedAt
+		limit = current
+	}
+	put(r.tx, r.store.rateLimits, key, limit)
+
+	return nil
+}
+
+// Get implements ports.IRateLimitRepository.
+func (r *RateLimitRepository) Get(ctx context.Context, params domain.RateLimitSearchParams) ([]domain.RateLimit, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var limits []domain.RateLimit
+	for _, l := range r.store.rateLimits {
+		if params.Scope != nil && l.Scope != *params.Scope {
+			continue
+		}
+		if params.Key != nil && l.Key != *params.Key {
+			continue
+		}
+		limits = append(limits, l)
+	}
+	sortRateLimits(limits)
+
+	return limits, nil
+}
+
+// Delete implements ports.IRateLimitRepository.
+func (r *RateLimitRepository) Delete(ctx context.Context, key domain.RateLimitKey) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	remove(r.tx, r.store.rateLimits, key)
+
+	return nil
+}
+
+// Acquire implements ports.IRateLimitRepository.
+func (r *RateLimitRepository) Acquire(ctx context.Context, keys []domain.RateLimitKey) (time.Duration, error) {
+	if r.tx == nil {
+		return 0, errors.New("acquire rate limit requires a transaction")
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	// Bloqueamos los buckets en orden fijo para evitar deadlocks entre workers
+	sorted := append([]domain.RateLimitKey(nil), keys...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Scope != sorted[j].Scope {
+			return sorted[i].Scope < sorted[j].Scope
+		}
+		return sorted[i].Key < sorted[j].Key
+	})
+	for _, k := range sorted {
+		if _, err := r.store.lockRow(ctx, r.tx, fmt.Sprintf("rate_limit:%s:%s", k.Scope, k.Key)); err != nil {
+			return 0, err
+		}
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var limits []domain.RateLimit
+	for _, k := range sorted {
+		if l, ok := r.store.rateLimits[k]; ok {
+			limits = append(limits, l)
+		}
+	}
+
+	// Sin límites configurados para este job
+	if len(limits) == 0 {
+		return 0, nil
+	}
+
+	if wait := domain.TakeToken(limits, r.store.clock.Now()); wait > 0 {
+		return wait, nil
+	}
+
+	for _, l := range limits {
+		put(r.tx, r.store.rateLimits, rateLimitKeyOf(l), l)
+	}
+
+	return 0, nil
+}
+
+func sortRateLimits(limits []domain.RateLimit) {
+	sort.Slice(limits, func(i, j int) bool {
+		if limits[i].Scope != limits[j].Scope {
+			return limits[i].Scope < limits[j].Scope
+		}
+		return limits[i].Key < limits[j].Key
+	})
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"math"
+	"sort"
+	"time"
+)
+
+type StatsRepository struct {
+	store *Store
+	tx    *tx
+}
+
+func NewStatsRepository(store *Store, t *tx) ports.IStatsRepository {
+	return &StatsRepository{store: store, tx: t}
+}
+
+// Todas las consultas se limitan al tenant del request: sin tenant se ven todos.
+
+// CountJobs implements ports.IStatsRepository.
+func (r *StatsRepository) CountJobs(ctx context.Context) ([]domain.JobCount, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	type typeStatus struct {
+		Type   string
+		Status domain.JobStatus
+	}
+	byKey := map[typeStatus]int{}
+	for _, job := range r.store.jobs {
+		if inTenant(ctx, job.Tenant, nil) {
+			byKey[typeStatus{job.Type, job.Status}]++
+		}
+	}
+
+	counts := make([]domain.JobCount, 0, len(byKey))
+	for k, n := range byKey {
+		counts = append(counts, domain.JobCount{Type: k.Type, Status: k.Status, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Type != counts[j].Type {
+			return counts[i].Type < counts[j].Type
+		}
+		return counts[i].Status < counts[j].Status
+	})
+
+	return counts, nil
+}
+
+// OldestPendingSince implements ports.IStatsRepository.
+func (r *StatsRepository) OldestPendingSince(ctx context.Context, now time.Time) (*time.Time, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var oldest *time.Time
+	for _, job := range r.store.jobs {
+		if job.Status != domain.JobStatusPending || job.ScheduledAt == nil || job.ScheduledAt.After(now) || !inTenant(ctx, job.Tenant, nil) {
+			continue
+		}
+		if oldest == nil || job.ScheduledAt.Before(*oldest) {
+			scheduledAt := *job.ScheduledAt
+			oldest = &scheduledAt
+		}
+	}
+
+	return oldest, nil
+}
+
+// Throughput implements ports.IStatsRepository.
+func (r *StatsRepository) Throughput(ctx context.Context, since time.Time) (domain.ThroughputStats, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stats := domain.ThroughputStats{WindowStartedAt: since}
+	for _, a := range r.store.attempts {
+		if a.CreatedAt.Before(since) || !inTenant(ctx, a.Tenant, nil) {
+			continue
+		}
+		switch a.Status {
+		case domain.AttemptStatusSuccess:
+			stats.Succeeded++
+		case domain.AttemptStatusFailed:
+			stats.Failed++
+		}
+	}
+	for _, job := range r.store.jobs {
+		if job.Status == domain.JobStatusDead && !job.UpdatedAt.Before(since) && inTenant(ctx, job.Tenant, nil) {
+			stats.Dead++
+		}
+	}
+
+	return stats, nil
+}
+
+// Durations implements ports.IStatsRepository.
+func (r *StatsRepository) Durations(ctx context.Context, since time.Time) (domain.DurationStats, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var durations []float64
+	for _, a := range r.store.attempts {
+		if a.DurationMs != nil && !a.CreatedAt.Before(since) && inTenant(ctx, a.Tenant, nil) {
+			durations = append(durations, float64(*a.DurationMs))
+		}
+	}
+	sort.Float64s(durations)
+
+	stats := domain.DurationStats{Samples: len(durations)}
+	if len(durations) > 0 {
+		p50, p95 := percentile(durations, 0.5), percentile(durations, 0.95)
+		stats.P50Ms, stats.P95Ms = &p50, &p95
+	}
+
+	return stats, nil
+}
+
+// percentile interpola entre los valores ordenados como percentile_cont de Postgres.
+func percentile(sorted []float64, p float64) float64 {
+	pos := p * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
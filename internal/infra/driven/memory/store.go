@@ -0,0 +1,198 @@
+// Package memory implements the driven ports (unit of work and repositories)
+// on top of in-process maps, so the services and drivers can run without
+// Postgres: in tests and in the single-process dev mode (cmd/dev).
+//
+// Each repository operation is atomic on its own. Inside Atomic, every write
+// records how to undo it and a rollback replays those undos in reverse order.
+// Writes are visible to other callers before the commit (there is no
+// isolation between transactions); the operations the Postgres adapters
+// serialize with FOR UPDATE take a row lock held until the end of the
+// transaction instead.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/internal/infra/clock"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// breakerKey identifica el breaker de un host en un nodo.
+type breakerKey struct {
+	NodeID string
+	Host   string
+}
+
+// slot es un lugar ocupado del semáforo de concurrencia de un tipo.
+type slot struct {
+	JobType    string
+	AcquiredAt time.Time
+}
+
+// delivery guarda la entrega junto con el tenant de su suscripción, que no
+// forma parte del modelo de dominio.
+type delivery struct {
+	domain.WebhookDelivery
+	Tenant string
+}
+
+// Store guarda el estado de todas las tablas. mu se toma solo mientras dura
+// cada operación; nunca se mantiene durante una transacción.
+type Store struct {
+	mu sync.Mutex
+
+	jobs              map[uuid.UUID]domain.Job
+	batches           map[uuid.UUID]domain.JobBatch
+	attempts          map[uuid.UUID]domain.Attempt
+	events            map[uuid.UUID]domain.Event
+	seq               int64 // como una secuencia de Postgres, no vuelve atrás en un rollback
+	rateLimits        map[domain.RateLimitKey]domain.RateLimit
+	concurrencyLimits map[string]domain.ConcurrencyLimit
+	slots             map[uuid.UUID]slot
+	breakers          map[breakerKey]domain.CircuitBreaker
+	subscriptions     map[uuid.UUID]domain.WebhookSubscription
+	deliveries        map[uuid.UUID]delivery
+	apiKeys           map[uuid.UUID]domain.APIKey
+	quotas            map[string]domain.TenantQuota
+
+	locks  sync.Map // clave de fila -> chan struct{} de capacidad 1
+	broker *EventBroker
+	clock  ports.IClock
+}
+
+// NewStore creates an empty store. clock may be nil to use the system clock.
+func NewStore(clk ports.IClock) *Store {
+	if clk == nil {
+		clk = clock.NewSystem()
+	}
+	s := &Store{
+		jobs:              map[uuid.UUID]domain.Job{},
+		batches:           map[uuid.UUID]domain.JobBatch{},
+		attempts:          map[uuid.UUID]domain.Attempt{},
+		events:            map[uuid.UUID]domain.Event{},
+		rateLimits:        map[domain.RateLimitKey]domain.RateLimit{},
+		concurrencyLimits: map[string]domain.ConcurrencyLimit{},
+		slots:             map[uuid.UUID]slot{},
+		breakers:          map[breakerKey]domain.CircuitBreaker{},
+		subscriptions:     map[uuid.UUID]domain.WebhookSubscription{},
+		deliveries:        map[uuid.UUID]delivery{},
+		apiKeys:           map[uuid.UUID]domain.APIKey{},
+		quotas:            map[string]domain.TenantQuota{},
+		clock:             clk,
+	}
+	s.broker = newEventBroker(s)
+	return s
+}
+
+// Broker devuelve el broker que reparte los eventos a medida que se confirman.
+func (s *Store) Broker() *EventBroker {
+	return s.broker
+}
+
+// tx es una transacción en curso: el log para deshacerla, las filas que tiene
+// bloqueadas y los eventos que se notifican al confirmarla.
+type tx struct {
+	undo   []func()
+	held   map[string]chan struct{}
+	events []domain.Event
+}
+
+func newTx() *tx {
+	return &tx{held: map[string]chan struct{}{}}
+}
+
+// onRollback anota cómo deshacer una escritura. Fuera de una transacción no hace nada.
+func (t *tx) onRollback(fn func()) {
+	if t != nil {
+		t.undo = append(t.undo, fn)
+	}
+}
+
+// commit libera los locks y notifica los eventos de la transacción.
+func (s *Store) commit(t *tx) {
+	s.unlockAll(t)
+	for _, event := range t.events {
+		s.broker.publish(event)
+	}
+}
+
+// rollback deshace las escrituras de la transacción, de la última a la primera.
+func (s *Store) rollback(t *tx) {
+	s.mu.Lock()
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		t.undo[i]()
+	}
+	s.mu.Unlock()
+	s.unlockAll(t)
+}
+
+// lockRow bloquea la fila key hasta el fin de la transacción, como un SELECT
+// ... FOR UPDATE. Fuera de una transacción la fila se bloquea solo hasta que
+// se llama a la función devuelta. No debe llamarse con mu tomado.
+func (s *Store) lockRow(ctx context.Context, t *tx, key string) (func(), error) {
+	if t != nil {
+		if _, ok := t.held[key]; ok {
+			return func() {}, nil
+		}
+	}
+
+	value, _ := s.locks.LoadOrStore(key, make(chan struct{}, 1))
+	ch := value.(chan struct{})
+	select {
+	case ch <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("lock %s: %w", key, ctx.Err())
+	}
+
+	if t == nil {
+		return func() { <-ch }, nil
+	}
+	t.held[key] = ch
+	return func() {}, nil
+}
+
+func (s *Store) unlockAll(t *tx) {
+	for key, ch := range t.held {
+		delete(t.held, key)
+		<-ch
+	}
+}
+
+// put guarda v en m[k] y anota en t cómo deshacerlo. Requiere s.mu.
+func put[K comparable, V any](t *tx, m map[K]V, k K, v V) {
+	prev, existed := m[k]
+	m[k] = v
+	t.onRollback(func() {
+		if existed {
+			m[k] = prev
+		} else {
+			delete(m, k)
+		}
+	})
+}
+
+// remove borra m[k] y anota en t cómo deshacerlo. Requiere s.mu.
+func remove[K comparable, V any](t *tx, m map[K]V, k K) {
+	prev, existed := m[k]
+	if !existed {
+		return
+	}
+	delete(m, k)
+	t.onRollback(func() { m[k] = prev })
+}
+
+// inTenant aplica el mismo filtro que scopeTenant en los repositorios de
+// Postgres: el tenant de la API key del request y el pedido en los parámetros.
+func inTenant(ctx context.Context, tenant string, param *string) bool {
+	for _, t := range []*string{domain.TenantFrom(ctx), param} {
+		if t != nil && *t != tenant {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"slices"
+	"sort"
+)
+
+type TenantQuotaRepository struct {
+	store *Store
+	tx    *tx
+}
+
+func NewTenantQuotaRepository(store *Store, t *tx) ports.ITenantQuotaRepository {
+	return &TenantQuotaRepository{store: store, tx: t}
+}
+
+// Upsert implements ports.ITenantQuotaRepository.
+func (r *TenantQuotaRepository) Upsert(ctx context.Context, quota domain.TenantQuota) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if current, ok := r.store.quotas[quota.Tenant]; ok {
+		quota.CreatedAt = current.CreatedAt
+	}
+	quota.Pending = 0
+	put(r.tx, r.store.quotas, quota.Tenant, quota)
+
+	return nil
+}
+
+// Get implements ports.ITenantQuotaRepository.
+func (r *TenantQuotaRepository) Get(ctx context.Context, params domain.TenantQuotaSearchParams) ([]domain.TenantQuota, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var quotas []domain.TenantQuota
+	for _, q := range r.store.quotas {
+		if !inTenant(ctx, q.Tenant, params.Tenant) {
+			continue
+		}
+		q.Pending = r.pending(q.Tenant)
+		quotas = append(quotas, q)
+	}
+	sort.Slice(quotas, func(i, j int) bool { return quotas[i].Tenant < quotas[j].Tenant })
+
+	return quotas, nil
+}
+
+// Delete implements ports.ITenantQuotaRepository.
+func (r *TenantQuotaRepository) Delete(ctx context.Context, tenant string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.quotas[tenant]; !ok {
+		return fmt.Errorf("tenant quota %s: %w", tenant, domain.ErrNotFound)
+	}
+	remove(r.tx, r.store.quotas, tenant)
+
+	return nil
+}
+
+// Reserve implements ports.ITenantQuotaRepository.
+// El lock sobre la cuota serializa las altas concurrentes del mismo tenant
+// hasta el commit, así dos requests no pueden pasar la cuota a la vez.
+func (r *TenantQuotaRepository) Reserve(ctx context.Context, tenant string, n int) (bool, error) {
+	if r.tx == nil {
+		return false, errors.New("reserve tenant quota requires a transaction")
+	}
+	if _, err := r.store.lockRow(ctx, r.tx, "tenant_quota:"+tenant); err != nil {
+		return false, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	quota, ok := r.store.quotas[tenant]
+	if !ok {
+		return true, nil
+	}
+
+	return r.pending(tenant)+n <= quota.MaxPendingJobs, nil
+}
+
+// pending cuenta los jobs del tenant que ocupan cuota. Requiere mu.
+func (r *TenantQuotaRepository) pending(tenant string) int {
+	count := 0
+	for _, job := range r.store.jobs {
+		if job.Tenant == tenant && slices.Contains(domain.TenantQuotaStatuses, job.Status) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"log/slog"
+	"time"
+)
+
+type DataStore struct {
+	store  *Store
+	tx     *tx // Transacción activa (nil si no hay transacción)
+	logger *slog.Logger
+}
+
+// NewDataStore creates the unit of work over store. logger may be nil to use
+// slog.Default().
+func NewDataStore(store *Store, logger *slog.Logger) ports.IUnitOfWork {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &DataStore{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Atomic ejecuta fn dentro de una transacción: si fn devuelve un error o
+// entra en pánico, se deshacen todas sus escrituras.
+func (ds *DataStore) Atomic(ctx context.Context, cb ports.FAtomicCallback) (err error) {
+	// Si ya estamos en una transacción, usarla directamente
+	if ds.tx != nil {
+		ds.logger.DebugContext(ctx, "reusing active transaction")
+		return cb(ds)
+	}
+
+	start := time.Now()
+	t := newTx()
+
+	defer func() {
+		if p := recover(); p != nil {
+			ds.logger.ErrorContext(ctx, "panic inside transaction, rolling back", "panic", p)
+			ds.store.rollback(t)
+			panic(p)
+		} else if err != nil {
+			ds.store.rollback(t)
+			ds.logger.DebugContext(ctx, "transaction rolled back", "error", err, "duration", time.Since(start))
+		} else {
+			ds.store.commit(t)
+			ds.logger.DebugContext(ctx, "transaction committed", "duration", time.Since(start))
+		}
+	}()
+
+	return cb(&DataStore{store: ds.store, tx: t, logger: ds.logger})
+}
+
+func (ds *DataStore) Job() ports.IJobRepository {
+	return NewJobRepository(ds.store, ds.tx)
+}
+func (ds *DataStore) JobBatch() ports.IJobBatchRepository {
+	return NewJobBatchRepository(ds.store, ds.tx)
+}
+func (ds *DataStore) Attempt() ports.IAttemptRepository {
+	return NewAttemptRepository(ds.store, ds.tx)
+}
+func (ds *DataStore) Event() ports.IEventRepository {
+	return NewEventRepository(ds.store, ds.tx)
+}
+func (ds *DataStore) RateLimit() ports.IRateLimitRepository {
+	return NewRateLimitRepository(ds.store, ds.tx)
+}
+func (ds *DataStore) ConcurrencyLimit() ports.IConcurrencyLimitRepository {
+	return NewConcurrencyLimitRepository(ds.store, ds.tx)
+}
+func (ds *DataStore) CircuitBreaker() ports.ICircuitBreakerRepository {
+	return NewCircuitBreakerRepository(ds.store, ds.tx)
+}
+func (ds *DataStore) WebhookSubscription() ports.IWebhookSubscriptionRepository {
+	return NewWebhookSubscriptionRepository(ds.store, ds.tx)
+}
+func (ds *DataStore) WebhookDelivery() ports.IWebhookDeliveryRepository {
+	return NewWebhookDeliveryRepository(ds.store, ds.tx)
+}
+func (ds *DataStore) Stats() ports.IStatsRepository {
+	return NewStatsRepository(ds.store, ds.tx)
+}
+func (ds *DataStore) APIKey() ports.IAPIKeyRepository {
+	return NewAPIKeyRepository(ds.store, ds.tx)
+}
+func (ds *DataStore) TenantQuota() ports.ITenantQuotaRepository {
+	return NewTenantQuotaRepository(ds.store, ds.tx)
+}
@@ -0,0 +1,185 @@
+package memory
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookDeliveryRepository struct {
+	store *Store
+	tx    *tx
+}
+
+func NewWebhookDeliveryRepository(store *Store, t *tx) ports.IWebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{store: store, tx: t}
+}
+
+// webhookDeliverySortKeys son los campos de orden de domain.WebhookDeliverySortFields.
+var webhookDeliverySortKeys = map[string]sortKey[domain.WebhookDelivery]{
+	"created_at": {sortTime, func(d domain.WebhookDelivery) any { return d.CreatedAt }},
+	"updated_at": {sortTime, func(d domain.WebhookDelivery) any { return d.UpdatedAt }},
+}
+
+// Enqueue implements ports.IWebhookDeliveryRepository.
+// Una suscripción coincide si es del job o global (opcionalmente por tipo) y
+// escucha el tipo del evento; sin tipos de evento escucha todos.
+func (r *WebhookDeliveryRepository) Enqueue(ctx context.Context, eventID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	event, ok := r.store.events[eventID]
+	if !ok {
+		return nil
+	}
+	job, ok := r.store.jobs[event.JobID]
+	if !ok {
+		return nil
+	}
+
+	now := r.store.clock.Now()
+	for _, s := range r.store.subscriptions {
+		if !s.Active || s.Tenant != job.Tenant {
+			continue
+		}
+		if s.JobID != nil && *s.JobID != event.JobID {
+			continue
+		}
+		if s.JobType != nil && *s.JobType != job.Type {
+			continue
+		}
+		if len(s.EventTypes) > 0 && !slices.Contains(s.EventTypes, event.Type) {
+			continue
+		}
+
+		d := delivery{
+			WebhookDelivery: domain.WebhookDelivery{
+				ID:             uuid.New(),
+				SubscriptionID: s.ID,
+				EventID:        event.ID,
+				JobID:          event.JobID,
+				EventType:      event.Type,
+				URL:            s.URL,
+				Status:         domain.WebhookDeliveryPending,
+				MaxAttempts:    s.MaxAttempts,
+				NextAttemptAt:  &now,
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			},
+			Tenant: s.Tenant,
+		}
+		put(r.tx, r.store.deliveries, d.ID, d)
+	}
+
+	return nil
+}
+
+// Claim implements ports.IWebhookDeliveryRepository.
+// Mueve next_attempt_at al final del lease: si el proceso cae a mitad del
+// envío, la entrega vuelve a estar vencida cuando expira.
+func (r *WebhookDeliveryRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]domain.PendingWebhook, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := r.store.clock.Now()
+	var due []delivery
+	for _, d := range r.store.deliveries {
+		if d.Status == domain.WebhookDeliveryPending && d.NextAttemptAt != nil && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttemptAt.Equal(*due[j].NextAttemptAt) {
+			return due[i].NextAttemptAt.Before(*due[j].NextAttemptAt)
+		}
+		return due[i].ID.String() < due[j].ID.String()
+	})
+	due = due[:min(limit, len(due))]
+
+	leaseEnd := now.Add(lease)
+	pending := make([]domain.PendingWebhook, 0, len(due))
+	for _, d := range due {
+		d.NextAttemptAt = &leaseEnd
+		put(r.tx, r.store.deliveries, d.ID, d)
+
+		event := r.store.events[d.EventID]
+		event.Tenant = ""
+		pending = append(pending, domain.PendingWebhook{
+			Delivery: d.WebhookDelivery,
+			Event:    event,
+			Secret:   r.store.subscriptions[d.SubscriptionID].Secret,
+		})
+	}
+
+	return pending, nil
+}
+
+// Update implements ports.IWebhookDeliveryRepository.
+func (r *WebhookDeliveryRepository) Update(ctx context.Context, wd domain.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	d, ok := r.store.deliveries[wd.ID]
+	if !ok {
+		return nil
+	}
+	d.Status = wd.Status
+	d.Attempts = wd.Attempts
+	d.NextAttemptAt = wd.NextAttemptAt
+	d.LastHTTPStatus = wd.LastHTTPStatus
+	d.LastError = wd.LastError
+	d.DeliveredAt = wd.DeliveredAt
+	d.UpdatedAt = wd.UpdatedAt
+	put(r.tx, r.store.deliveries, d.ID, d)
+
+	return nil
+}
+
+// Get implements ports.IWebhookDeliveryRepository.
+func (r *WebhookDeliveryRepository) Get(ctx context.Context, params domain.WebhookDeliverySearchParams) ([]domain.WebhookDelivery, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return paginate(r.search(ctx, params), params.SearchParams, webhookDeliverySortKeys, func(d domain.WebhookDelivery) uuid.UUID { return d.ID })
+}
+
+// Count implements ports.IWebhookDeliveryRepository.
+func (r *WebhookDeliveryRepository) Count(ctx context.Context, params domain.WebhookDeliverySearchParams) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return len(r.search(ctx, params)), nil
+}
+
+// search devuelve las entregas que cumplen los filtros. Requiere mu.
+func (r *WebhookDeliveryRepository) search(ctx context.Context, params domain.WebhookDeliverySearchParams) []domain.WebhookDelivery {
+	var deliveries []domain.WebhookDelivery
+	for _, d := range r.store.deliveries {
+		if !inTenant(ctx, d.Tenant, params.Tenant) {
+			continue
+		}
+		if params.ID != nil && d.ID != *params.ID {
+			continue
+		}
+		if params.SubscriptionID != nil && d.SubscriptionID != *params.SubscriptionID {
+			continue
+		}
+		if params.JobID != nil && d.JobID != *params.JobID {
+			continue
+		}
+		if params.EventType != nil && d.EventType != *params.EventType {
+			continue
+		}
+		if params.Status != nil && d.Status != *params.Status {
+			continue
+		}
+		deliveries = append(deliveries, d.WebhookDelivery)
+	}
+
+	return deliveries
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+type WebhookSubscriptionRepository struct {
+	store *Store
+	tx    *tx
+}
+
+func NewWebhookSubscriptionRepository(store *Store, t *tx) ports.IWebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{store: store, tx: t}
+}
+
+// Insert implements ports.IWebhookSubscriptionRepository.
+func (r *WebhookSubscriptionRepository) Insert(ctx context.Context, subscription domain.WebhookSubscription) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.subscriptions[subscription.ID]; ok {
+		return fmt.Errorf("webhook subscription %s already exists: %w", subscription.ID, domain.ErrConflict)
+	}
+	put(r.tx, r.store.subscriptions, subscription.ID, subscription)
+
+	return nil
+}
+
+// Get implements ports.IWebhookSubscriptionRepository.
+// Solo devuelve suscripciones activas.
+func (r *WebhookSubscriptionRepository) Get(ctx context.Context, params domain.WebhookSubscriptionSearchParams) ([]domain.WebhookSubscription, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var subscriptions []domain.WebhookSubscription
+	for _, s := range r.store.subscriptions {
+		if !s.Active || !inTenant(ctx, s.Tenant, params.Tenant) {
+			continue
+		}
+		if params.ID != nil && s.ID != *params.ID {
+			continue
+		}
+		if params.JobID != nil && (s.JobID == nil || *s.JobID != *params.JobID) {
+			continue
+		}
+		if params.JobType != nil && (s.JobType == nil || *s.JobType != *params.JobType) {
+			continue
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		if !subscriptions[i].CreatedAt.Equal(subscriptions[j].CreatedAt) {
+			return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+		}
+		return subscriptions[i].ID.String() < subscriptions[j].ID.String()
+	})
+
+	return subscriptions, nil
+}
+
+// Delete implements ports.IWebhookSubscriptionRepository.
+// La suscripción se desactiva en lugar de borrarse para conservar el historial de entregas.
+func (r *WebhookSubscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	subscription, ok := r.store.subscriptions[id]
+	if !ok || !subscription.Active {
+		return fmt.Errorf("webhook subscription %s: %w", id, domain.ErrNotFound)
+	}
+
+	now := r.store.clock.Now()
+	subscription.Active = false
+	subscription.UpdatedAt = now
+	put(r.tx, r.store.subscriptions, id, subscription)
+
+	// Las entregas pendientes de la suscripción ya no se envían
+	reason := "subscription deleted"
+	for deliveryID, d := range r.store.deliveries {
+		if d.SubscriptionID != id || d.Status != domain.WebhookDeliveryPending {
+			continue
+		}
+		d.Status = domain.WebhookDeliveryDead
+		d.NextAttemptAt = nil
+		d.LastError = &reason
+		d.UpdatedAt = now
+		put(r.tx, r.store.deliveries, deliveryID, d)
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type APIKeyRepository struct {
+	tx   pgx.Tx
+	pool *pgxpool.Pool
+}
+
+func NewAPIKeyRepository(tx pgx.Tx, pool *pgxpool.Pool) ports.IAPIKeyRepository {
+	return &APIKeyRepository{tx: tx, pool: pool}
+}
+
+// Insert implements ports.IAPIKeyRepository.
+func (r *APIKeyRepository) Insert(ctx context.Context, key domain.APIKey) error {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, s := range key.Scopes {
+		scopes = append(scopes, string(s))
+	}
+
+	query := utils.QueryBuilder{
+		Query: `
+		INSERT INTO api_keys
+		(id,
+		tenant,
+		name,
+		prefix,
+		key_hash,
+		scopes,
+		allowed_job_types,
+		created_at,
+		updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		Args: []any{
+			key.ID,
+			key.Tenant,
+			key.Name,
+			key.Prefix,
+			key.Hash,
+			scopes,
+			key.AllowedJobTypes,
+			key.CreatedAt,
+			key.UpdatedAt,
+		},
+	}
+
+	var err error
+	if r.tx != nil {
+		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("insert api key failed: %w", err)
+	}
+
+	return nil
+}
+
+// Get implements ports.IAPIKeyRepository.
+func (r *APIKeyRepository) Get(ctx context.Context, params domain.APIKeySearchParams) ([]domain.APIKey, error) {
+	query := r.selectQuery()
+
+	if err := r.buildSearchParams(ctx, &query, params); err != nil {
+		return nil, fmt.Errorf("failed to build search params: %w", err)
+	}
+	query.Query += " ORDER BY k.created_at, k.id"
+
+	var rows pgx.Rows
+	var err error
+	if r.tx != nil {
+		rows, err = r.tx.Query(ctx, query.Query, query.Args...)
+	} else {
+		rows, err = r.pool.Query(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []domain.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return keys, nil
+}
+
+// GetOne implements ports.IAPIKeyRepository.
+func (r *APIKeyRepository) GetOne(ctx context.Context, params domain.APIKeySearchParams) (*domain.APIKey, error) {
+	query := r.selectQuery()
+
+	if err := r.buildSearchParams(ctx, &query, params); err != nil {
+		return nil, fmt.Errorf("failed to build search params: %w", err)
+	}
+
+	var row pgx.Row
+	if r.tx != nil {
+		row = r.tx.QueryRow(ctx, query.Query, query.Args...)
+	} else {
+		row = r.pool.QueryRow(ctx, query.Query, query.Args...)
+	}
+
+	key, err := scanAPIKey(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("api key: %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan api key: %w", err)
+	}
+
+	return key, nil
+}
+
+// Revoke implements ports.IAPIKeyRepository.
+// Las keys se revocan en lugar de borrarse para que los jobs sigan apuntando a su creador.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := utils.QueryBuilder{
+		Query: `UPDATE api_keys SET revoked_at = $2, updated_at = $2 WHERE id = $1 AND revoked_at IS NULL`,
+		Args:  []any{id, at},
+	}
+
+	var cmdTag pgconn.CommandTag
+	var err error
+
+	if r.tx != nil {
+		cmdTag, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		cmdTag, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("revoke api key failed: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("api key %s: %w", id, domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// TouchLastUsed implements ports.IAPIKeyRepository.
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := utils.QueryBuilder{
+		Query: `
+		UPDATE api_keys SET last_used_at = $2
+		WHERE id = $1
+		AND (last_used_at IS NULL OR last_used_at < $3)`,
+		Args: []any{id, at, at.Add(-time.Minute)},
+	}
+
+	var err error
+	if r.tx != nil {
+		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("touch api key failed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) selectQuery() utils.QueryBuilder {
+	return utils.QueryBuilder{
+		Query: ` SELECT
+				k.id,
+				k.tenant,
+				k.name,
+				k.prefix,
+				k.key_hash,
+				k.scopes,
+				k.allowed_job_types,
+				k.last_used_at,
+				k.revoked_at,
+				k.created_at,
+				k.updated_at
+			FROM api_keys k
+			WHERE 1=1
+		`,
+		Args: []any{},
+	}
+}
+
+func scanAPIKey(row pgx.Row) (*domain.APIKey, error) {
+	var k domain.APIKey
+	var scopes []string
+	if err := row.Scan(
+		&k.ID,
+		&k.Tenant,
+		&k.Name,
+		&k.Prefix,
+		&k.Hash,
+		&scopes,
+		&k.AllowedJobTypes,
+		&k.LastUsedAt,
+		&k.RevokedAt,
+		&k.CreatedAt,
+		&k.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	k.Scopes = make([]domain.APIKeyScope, 0, len(scopes))
+	for _, s := range scopes {
+		k.Scopes = append(k.Scopes, domain.APIKeyScope(s))
+	}
+	return &k, nil
+}
+
+func (r *APIKeyRepository) buildSearchParams(ctx context.Context, qb *utils.QueryBuilder, params domain.APIKeySearchParams) error {
+	scopeTenant(ctx, qb, "k.tenant", params.Tenant)
+
+	if params.ID != nil {
+		qb.Query += fmt.Sprintf(" AND k.id = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.ID)
+	}
+	if params.Hash != nil {
+		qb.Query += fmt.Sprintf(" AND k.key_hash = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.Hash)
+	}
+	if !params.IncludeRevoked {
+		qb.Query += " AND k.revoked_at IS NULL"
+	}
+	return nil
+}
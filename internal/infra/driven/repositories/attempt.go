@@ -6,7 +6,6 @@ import (
 	"job_scheduler_go_rabbitmq/internal/core/domain"
 	"job_scheduler_go_rabbitmq/internal/core/ports"
 	"job_scheduler_go_rabbitmq/utils"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -14,12 +13,13 @@ import (
 )
 
 type AttemptRepository struct {
-	tx   pgx.Tx
-	pool *pgxpool.Pool
+	tx    pgx.Tx
+	pool  *pgxpool.Pool
+	clock Clock
 }
 
-func NewAttemptRepository(tx pgx.Tx, pool *pgxpool.Pool) ports.IAttemptRepository {
-	return &AttemptRepository{tx: tx, pool: pool}
+func NewAttemptRepository(tx pgx.Tx, pool *pgxpool.Pool, clock Clock) ports.IAttemptRepository {
+	return &AttemptRepository{tx: tx, pool: pool, clock: clock}
 }
 
 // MarkFailed implements ports.IAttemptRepository.
@@ -34,7 +34,7 @@ func (r *AttemptRepository) MarkFailed(ctx context.Context, attemptID uuid.UUID,
 			http_status = $4
 		WHERE id = $5
 	`,
-		Args: []any{domain.AttemptStatusFailed, errMsg, time.Now(), httpStatus, attemptID},
+		Args: []any{domain.AttemptStatusFailed, errMsg, r.clock.Now(), httpStatus, attemptID},
 	}
 
 	_, err := r.tx.Exec(ctx, query.Query, query.Args...)
@@ -55,7 +55,7 @@ func (r *AttemptRepository) MarkSuccess(ctx context.Context, attemptID uuid.UUID
 			finished_at = $2
 		WHERE id = $3
 	`,
-		Args: []any{domain.AttemptStatusSuccess, time.Now(), attemptID},
+		Args: []any{domain.AttemptStatusSuccess, r.clock.Now(), attemptID},
 	}
 
 	_, err := r.tx.Exec(ctx, query.Query, query.Args...)
@@ -76,7 +76,9 @@ func (r *AttemptRepository) Count(ctx context.Context, params domain.AttemptSear
 		Args: []any{},
 	}
 
-	r.buildSearchParams(query, params)
+	if err := r.buildSearchParams(ctx, query, params); err != nil {
+		return 0, err
+	}
 
 	var count int
 	var row pgx.Row
@@ -101,23 +103,30 @@ func (r *AttemptRepository) Insert(ctx context.Context, attempt domain.Attempt)
 				(
 				id,
 				job_id,
+				tenant,
 				attempt_number,
 				started_at,
+				finished_at,
+				duration_ms,
 				status,
 				error_message,
 				http_status,
+				worker_id,
 				created_at
 				)
 			VALUES
-				($1, $2, $3, $4, $5, $6, $7, $8)`,
+				($1, $2, (SELECT tenant FROM jobs WHERE id = $2), $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
 		Args: []any{
 			attempt.ID,
 			attempt.JobID,
 			attempt.AttemptNumber,
 			attempt.StartedAt,
+			attempt.FinishedAt,
+			attempt.DurationMs,
 			attempt.Status,
 			attempt.ErrorMessage,
 			attempt.HTTPStatus,
+			attempt.WorkerID,
 			attempt.CreatedAt,
 		},
 	}
@@ -142,11 +151,15 @@ func (r *AttemptRepository) Get(ctx context.Context, params domain.AttemptSearch
 		Query: ` SELECT
 				a.id,
 				a.job_id,
+				a.tenant,
 				a.attempt_number,
 				a.started_at,
+				a.finished_at,
+				a.duration_ms,
 				a.status,
 				a.error_message,
 				a.http_status,
+				a.worker_id,
 				a.created_at
 			FROM job_attempts a
 			WHERE 1=1
@@ -154,7 +167,10 @@ func (r *AttemptRepository) Get(ctx context.Context, params domain.AttemptSearch
 		Args: []any{},
 	}
 
-	if err := r.buildSearchParams(&query, params); err != nil {
+	if err := r.buildSearchParams(ctx, &query, params); err != nil {
+		return nil, err
+	}
+	if err := r.buildPagination(&query, params); err != nil {
 		return nil, err
 	}
 
@@ -176,11 +192,15 @@ func (r *AttemptRepository) Get(ctx context.Context, params domain.AttemptSearch
 		if err := rows.Scan(
 			&attempt.ID,
 			&attempt.JobID,
+			&attempt.Tenant,
 			&attempt.AttemptNumber,
 			&attempt.StartedAt,
+			&attempt.FinishedAt,
+			&attempt.DurationMs,
 			&attempt.Status,
 			&attempt.ErrorMessage,
 			&attempt.HTTPStatus,
+			&attempt.WorkerID,
 			&attempt.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -188,10 +208,16 @@ func (r *AttemptRepository) Get(ctx context.Context, params domain.AttemptSearch
 		attempts = append(attempts, attempt)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
 	return attempts, nil
 }
 
-func (r *AttemptRepository) buildSearchParams(query *utils.QueryBuilder, params domain.AttemptSearchParams) error {
+func (r *AttemptRepository) buildSearchParams(ctx context.Context, query *utils.QueryBuilder, params domain.AttemptSearchParams) error {
+	scopeTenant(ctx, query, "a.tenant", params.Tenant)
+
 	if params.ID != nil {
 		query.Query += fmt.Sprintf(" AND a.id = $%d", len(query.Args)+1)
 		query.Args = append(query.Args, *params.ID)
@@ -201,5 +227,86 @@ func (r *AttemptRepository) buildSearchParams(query *utils.QueryBuilder, params
 		query.Query += fmt.Sprintf(" AND a.job_id = $%d", len(query.Args)+1)
 		query.Args = append(query.Args, *params.JobID)
 	}
+	if params.JobType != nil {
+		query.Query += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM jobs j WHERE j.id = a.job_id AND j.type = $%d)", len(query.Args)+1)
+		query.Args = append(query.Args, *params.JobType)
+	}
+	if params.Status != nil {
+		query.Query += fmt.Sprintf(" AND a.status = $%d", len(query.Args)+1)
+		query.Args = append(query.Args, *params.Status)
+	}
+	if params.HTTPStatus != nil {
+		query.Query += fmt.Sprintf(" AND a.http_status = $%d", len(query.Args)+1)
+		query.Args = append(query.Args, *params.HTTPStatus)
+	}
+	if params.HTTPStatusClass != nil {
+		query.Query += fmt.Sprintf(" AND a.http_status / 100 = $%d", len(query.Args)+1)
+		query.Args = append(query.Args, *params.HTTPStatusClass)
+	}
+	if params.WorkerID != nil {
+		query.Query += fmt.Sprintf(" AND a.worker_id = $%d", len(query.Args)+1)
+		query.Args = append(query.Args, *params.WorkerID)
+	}
+	if params.StartedFrom != nil {
+		query.Query += fmt.Sprintf(" AND a.started_at >= $%d", len(query.Args)+1)
+		query.Args = append(query.Args, *params.StartedFrom)
+	}
+	if params.StartedTo != nil {
+		query.Query += fmt.Sprintf(" AND a.started_at < $%d", len(query.Args)+1)
+		query.Args = append(query.Args, *params.StartedTo)
+	}
+	if params.Q != nil && *params.Q != "" {
+		query.Query += fmt.Sprintf(" AND a.error_message ILIKE $%d", len(query.Args)+1)
+		query.Args = append(query.Args, "%"+*params.Q+"%")
+	}
+	return nil
+}
+
+// attemptSortColumns mapea los campos de orden a columna y tipo (para castear el cursor)
+var attemptSortColumns = map[string][2]string{
+	"created_at":     {"a.created_at", "timestamptz"},
+	"started_at":     {"a.started_at", "timestamptz"},
+	"attempt_number": {"a.attempt_number", "int"},
+}
+
+// buildPagination agrega orden, cursor y límite. Va separado de
+// buildSearchParams para que Count no los incluya.
+func (r *AttemptRepository) buildPagination(query *utils.QueryBuilder, params domain.AttemptSearchParams) error {
+	field, desc := "created_at", false
+	if params.Sort != nil && *params.Sort != "" {
+		field, desc = utils.ParseSort(*params.Sort)
+	}
+	column, ok := attemptSortColumns[field]
+	if !ok {
+		return fmt.Errorf("sort field %q: %w", field, domain.ErrInvalid)
+	}
+
+	op, dir := ">", "ASC"
+	if desc {
+		op, dir = "<", "DESC"
+	}
+
+	if params.Cursor != nil && *params.Cursor != "" {
+		cursor, err := utils.DecodeCursor(*params.Cursor, utils.FormatSort(field, desc))
+		if err != nil {
+			return fmt.Errorf("%v: %w", err, domain.ErrInvalid)
+		}
+		query.Query += fmt.Sprintf(" AND (%s, a.id) %s ($%d::%s, $%d::uuid)",
+			column[0], op, len(query.Args)+1, column[1], len(query.Args)+2)
+		query.Args = append(query.Args, cursor.Value, cursor.ID)
+	}
+
+	query.Query += fmt.Sprintf(" ORDER BY %s %s, a.id %s", column[0], dir, dir)
+
+	if params.Limit != nil {
+		query.Query += fmt.Sprintf(" LIMIT $%d", len(query.Args)+1)
+		query.Args = append(query.Args, *params.Limit)
+
+		if params.Page != nil && *params.Page > 1 && (params.Cursor == nil || *params.Cursor == "") {
+			query.Query += fmt.Sprintf(" OFFSET $%d", len(query.Args)+1)
+			query.Args = append(query.Args, (*params.Page-1)**params.Limit)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CircuitBreakerRepository struct {
+	tx   pgx.Tx
+	pool *pgxpool.Pool
+}
+
+func NewCircuitBreakerRepository(tx pgx.Tx, pool *pgxpool.Pool) ports.ICircuitBreakerRepository {
+	return &CircuitBreakerRepository{tx: tx, pool: pool}
+}
+
+// Upsert implements ports.ICircuitBreakerRepository.
+func (r *CircuitBreakerRepository) Upsert(ctx context.Context, breaker domain.CircuitBreaker) error {
+	query := utils.QueryBuilder{
+		Query: `
+		INSERT INTO circuit_breakers
+		(node_id,
+		host,
+		state,
+		failures,
+		opened_at,
+		retry_at,
+		updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (node_id, host) DO UPDATE
+		SET
+			state = EXCLUDED.state,
+			failures = EXCLUDED.failures,
+			opened_at = EXCLUDED.opened_at,
+			retry_at = EXCLUDED.retry_at,
+			updated_at = EXCLUDED.updated_at`,
+		Args: []any{
+			breaker.NodeID,
+			breaker.Host,
+			breaker.State,
+			breaker.Failures,
+			breaker.OpenedAt,
+			breaker.RetryAt,
+			breaker.UpdatedAt,
+		},
+	}
+
+	var err error
+	if r.tx != nil {
+		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("upsert circuit breaker failed: %w", err)
+	}
+
+	return nil
+}
+
+// Get implements ports.ICircuitBreakerRepository.
+func (r *CircuitBreakerRepository) Get(ctx context.Context, params domain.CircuitBreakerSearchParams) ([]domain.CircuitBreaker, error) {
+	query := utils.QueryBuilder{
+		Query: ` SELECT
+				cb.node_id,
+				cb.host,
+				cb.state,
+				cb.failures,
+				cb.opened_at,
+				cb.retry_at,
+				cb.updated_at
+			FROM circuit_breakers cb
+			WHERE 1=1
+		`,
+		Args: []any{},
+	}
+
+	if err := r.buildSearchParams(&query, params); err != nil {
+		return nil, fmt.Errorf("failed to build search params: %w", err)
+	}
+	query.Query += " ORDER BY cb.host, cb.node_id"
+
+	var rows pgx.Rows
+	var err error
+	if r.tx != nil {
+		rows, err = r.tx.Query(ctx, query.Query, query.Args...)
+	} else {
+		rows, err = r.pool.Query(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var breakers []domain.CircuitBreaker
+	for rows.Next() {
+		var cb domain.CircuitBreaker
+		if err := rows.Scan(
+			&cb.NodeID,
+			&cb.Host,
+			&cb.State,
+			&cb.Failures,
+			&cb.OpenedAt,
+			&cb.RetryAt,
+			&cb.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		breakers = append(breakers, cb)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return breakers, nil
+}
+
+func (r *CircuitBreakerRepository) buildSearchParams(qb *utils.QueryBuilder, params domain.CircuitBreakerSearchParams) error {
+	if params.NodeID != nil {
+		qb.Query += fmt.Sprintf(" AND cb.node_id = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.NodeID)
+	}
+	if params.Host != nil {
+		qb.Query += fmt.Sprintf(" AND cb.host = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.Host)
+	}
+	if params.State != nil {
+		qb.Query += fmt.Sprintf(" AND cb.state = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.State)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/utils"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Clock es el reloj de los repositorios. Con DBNow las comparaciones de
+// scheduling (jobs listos para correr, locks y leases vencidos, slots y
+// buckets de rate limit) usan now() de Postgres en lugar de la hora local,
+// así nodos con relojes desfasados deciden igual qué está vencido.
+// El valor cero usa el reloj del sistema.
+type Clock struct {
+	ports.IClock
+	DBNow bool
+}
+
+// Now implements ports.IClock.
+func (c Clock) Now() time.Time {
+	if c.IClock == nil {
+		return time.Now()
+	}
+	return c.IClock.Now()
+}
+
+// sqlNow agrega a qb la hora actual y devuelve la expresión que la
+// referencia: now() con DBNow o un parámetro con la hora del reloj.
+func (c Clock) sqlNow(qb *utils.QueryBuilder) string {
+	if c.DBNow {
+		return "now()"
+	}
+	qb.Args = append(qb.Args, c.Now())
+	return fmt.Sprintf("$%d", len(qb.Args))
+}
+
+// sqlShift es como sqlNow pero desplazada d (negativo hacia el pasado).
+func (c Clock) sqlShift(qb *utils.QueryBuilder, d time.Duration) string {
+	if c.DBNow {
+		qb.Args = append(qb.Args, d.Seconds())
+		return fmt.Sprintf("(now() + make_interval(secs => $%d))", len(qb.Args))
+	}
+	qb.Args = append(qb.Args, c.Now().Add(d))
+	return fmt.Sprintf("$%d", len(qb.Args))
+}
+
+// current devuelve la hora actual para los cálculos que se hacen en Go
+// dentro de tx: now() de la transacción con DBNow o la hora del reloj.
+func (c Clock) current(ctx context.Context, tx pgx.Tx) (time.Time, error) {
+	if !c.DBNow {
+		return c.Now(), nil
+	}
+	var now time.Time
+	if err := tx.QueryRow(ctx, `SELECT now()`).Scan(&now); err != nil {
+		return time.Time{}, fmt.Errorf("read database clock: %w", err)
+	}
+	return now, nil
+}
@@ -0,0 +1,220 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ConcurrencyLimitRepository struct {
+	tx    pgx.Tx
+	pool  *pgxpool.Pool
+	clock Clock
+}
+
+func NewConcurrencyLimitRepository(tx pgx.Tx, pool *pgxpool.Pool, clock Clock) ports.IConcurrencyLimitRepository {
+	return &ConcurrencyLimitRepository{tx: tx, pool: pool, clock: clock}
+}
+
+// Upsert implements ports.IConcurrencyLimitRepository.
+func (r *ConcurrencyLimitRepository) Upsert(ctx context.Context, limit domain.ConcurrencyLimit) error {
+	query := utils.QueryBuilder{
+		Query: `
+		INSERT INTO concurrency_limits
+		(job_type,
+		max_concurrent,
+		created_at,
+		updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (job_type) DO UPDATE
+		SET
+			max_concurrent = EXCLUDED.max_concurrent,
+			updated_at = EXCLUDED.updated_at`,
+		Args: []any{
+			limit.JobType,
+			limit.MaxConcurrent,
+			limit.CreatedAt,
+			limit.UpdatedAt,
+		},
+	}
+
+	var err error
+	if r.tx != nil {
+		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("upsert concurrency limit failed: %w", err)
+	}
+
+	return nil
+}
+
+// Get implements ports.IConcurrencyLimitRepository.
+func (r *ConcurrencyLimitRepository) Get(ctx context.Context, params domain.ConcurrencyLimitSearchParams) ([]domain.ConcurrencyLimit, error) {
+	query := utils.QueryBuilder{
+		Args: []any{},
+	}
+	query.Query = ` SELECT
+				cl.job_type,
+				cl.max_concurrent,
+				(SELECT COUNT(*) FROM concurrency_slots cs
+					WHERE cs.job_type = cl.job_type AND cs.acquired_at >= ` + r.clock.sqlShift(&query, -domain.ConcurrencySlotLease) + `),
+				cl.created_at,
+				cl.updated_at
+			FROM concurrency_limits cl
+			WHERE 1=1
+		`
+
+	if err := r.buildSearchParams(&query, params); err != nil {
+		return nil, fmt.Errorf("failed to build search params: %w", err)
+	}
+	query.Query += " ORDER BY cl.job_type"
+
+	var rows pgx.Rows
+	var err error
+	if r.tx != nil {
+		rows, err = r.tx.Query(ctx, query.Query, query.Args...)
+	} else {
+		rows, err = r.pool.Query(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var limits []domain.ConcurrencyLimit
+	for rows.Next() {
+		var l domain.ConcurrencyLimit
+		if err := rows.Scan(
+			&l.JobType,
+			&l.MaxConcurrent,
+			&l.Running,
+			&l.CreatedAt,
+			&l.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		limits = append(limits, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return limits, nil
+}
+
+// Delete implements ports.IConcurrencyLimitRepository.
+func (r *ConcurrencyLimitRepository) Delete(ctx context.Context, jobType string) error {
+	query := utils.QueryBuilder{
+		Query: `DELETE FROM concurrency_limits WHERE job_type = $1`,
+		Args:  []any{jobType},
+	}
+
+	var err error
+	if r.tx != nil {
+		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("delete concurrency limit failed: %w", err)
+	}
+
+	return nil
+}
+
+// AcquireSlot implements ports.IConcurrencyLimitRepository.
+func (r *ConcurrencyLimitRepository) AcquireSlot(ctx context.Context, jobType string, jobID uuid.UUID) (bool, error) {
+	if r.tx == nil {
+		return false, errors.New("acquire concurrency slot requires a transaction")
+	}
+
+	// El lock sobre la fila del límite serializa a los workers que compiten por el mismo tipo
+	var maxConcurrent int
+	err := r.tx.QueryRow(ctx,
+		`SELECT max_concurrent FROM concurrency_limits WHERE job_type = $1 FOR UPDATE`,
+		jobType,
+	).Scan(&maxConcurrent)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Tipo sin límite configurado
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("lock concurrency limit failed: %w", err)
+	}
+
+	now, err := r.clock.current(ctx, r.tx)
+	if err != nil {
+		return false, err
+	}
+
+	// Liberar slots huérfanos de workers que murieron sin soltarlos
+	if _, err := r.tx.Exec(ctx,
+		`DELETE FROM concurrency_slots WHERE job_type = $1 AND acquired_at < $2`,
+		jobType, now.Add(-domain.ConcurrencySlotLease),
+	); err != nil {
+		return false, fmt.Errorf("expire concurrency slots failed: %w", err)
+	}
+
+	var running int
+	if err := r.tx.QueryRow(ctx,
+		`SELECT COUNT(*) FROM concurrency_slots WHERE job_type = $1`,
+		jobType,
+	).Scan(&running); err != nil {
+		return false, fmt.Errorf("count concurrency slots failed: %w", err)
+	}
+	if running >= maxConcurrent {
+		return false, nil
+	}
+
+	cmdTag, err := r.tx.Exec(ctx, `
+		INSERT INTO concurrency_slots (job_id, job_type, acquired_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (job_id) DO NOTHING`,
+		jobID, jobType, now,
+	)
+	if err != nil {
+		return false, fmt.Errorf("insert concurrency slot failed: %w", err)
+	}
+
+	// Otro worker ya tiene un slot para este mismo job (mensaje duplicado)
+	return cmdTag.RowsAffected() == 1, nil
+}
+
+// ReleaseSlot implements ports.IConcurrencyLimitRepository.
+func (r *ConcurrencyLimitRepository) ReleaseSlot(ctx context.Context, jobID uuid.UUID) error {
+	query := utils.QueryBuilder{
+		Query: `DELETE FROM concurrency_slots WHERE job_id = $1`,
+		Args:  []any{jobID},
+	}
+
+	var err error
+	if r.tx != nil {
+		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("release concurrency slot failed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ConcurrencyLimitRepository) buildSearchParams(qb *utils.QueryBuilder, params domain.ConcurrencyLimitSearchParams) error {
+	if params.JobType != nil {
+		qb.Query += fmt.Sprintf(" AND cl.job_type = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.JobType)
+	}
+	return nil
+}
@@ -0,0 +1,6 @@
+package repositories
+
+// códigos de error de Postgres que se traducen a errores de dominio
+const (
+	pgUniqueViolation = "23505"
+)
@@ -11,6 +11,9 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// EventsChannel es el canal de LISTEN/NOTIFY por el que se avisa cada evento insertado.
+const EventsChannel = "job_events"
+
 type EventRepository struct {
 	tx   pgx.Tx
 	pool *pgxpool.Pool
@@ -25,20 +28,24 @@ func (r *EventRepository) Get(ctx context.Context, params domain.EventSearchPara
 	query := utils.QueryBuilder{
 		Query: `
 		SELECT
-			id,
-			job_id,
-			type,
-			message,
-			metadata,
-			created_at
-		FROM events
+			e.id,
+			e.seq,
+			e.job_id,
+			e.tenant,
+			e.event_type,
+			e.message,
+			e.metadata,
+			e.created_at
+		FROM job_events e
 		WHERE 1=1
 	`,
 	}
 
-	if err := r.buildSearchParams(&query, params); err != nil {
+	if err := r.buildSearchParams(ctx, &query, params); err != nil {
 		return nil, fmt.Errorf("build search params: %w", err)
 	}
+	r.buildPagination(&query, params)
+
 	var rows pgx.Rows
 	var err error
 
@@ -57,45 +64,150 @@ func (r *EventRepository) Get(ctx context.Context, params domain.EventSearchPara
 		var event domain.Event
 		if err := rows.Scan(
 			&event.ID,
+			&event.Seq,
+			&event.JobID,
+			&event.Tenant,
+			&event.Type,
+			&event.Message,
+			&event.Metadata,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// GetStream implements ports.IEventRepository.
+func (r *EventRepository) GetStream(ctx context.Context, params domain.EventSearchParams) ([]domain.StreamEvent, error) {
+	query := utils.QueryBuilder{
+		Query: `
+		SELECT
+			e.id,
+			e.seq,
+			e.job_id,
+			e.tenant,
+			e.event_type,
+			e.message,
+			e.metadata,
+			e.created_at,
+			j.type,
+			j.status
+		FROM job_events e
+		JOIN jobs j ON j.id = e.job_id
+		WHERE 1=1
+	`,
+	}
+
+	if err := r.buildSearchParams(ctx, &query, params); err != nil {
+		return nil, fmt.Errorf("build search params: %w", err)
+	}
+	r.buildPagination(&query, params)
+
+	var rows pgx.Rows
+	var err error
+
+	if r.tx != nil {
+		rows, err = r.tx.Query(ctx, query.Query, query.Args...)
+	} else {
+		rows, err = r.pool.Query(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.StreamEvent
+	for rows.Next() {
+		var event domain.StreamEvent
+		if err := rows.Scan(
+			&event.ID,
+			&event.Seq,
 			&event.JobID,
+			&event.Tenant,
 			&event.Type,
 			&event.Message,
 			&event.Metadata,
 			&event.CreatedAt,
+			&event.JobType,
+			&event.JobStatus,
 		); err != nil {
 			return nil, err
 		}
 		events = append(events, event)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
 	return events, nil
 }
 
-func (r *EventRepository) buildSearchParams(query *utils.QueryBuilder, params domain.EventSearchParams) error {
+func (r *EventRepository) buildSearchParams(ctx context.Context, query *utils.QueryBuilder, params domain.EventSearchParams) error {
+	scopeTenant(ctx, query, "e.tenant", params.Tenant)
+
 	if params.ID != nil {
-		query.Query += fmt.Sprintf(" AND id = $%d", len(query.Args)+1)
+		query.Query += fmt.Sprintf(" AND e.id = $%d", len(query.Args)+1)
 		query.Args = append(query.Args, *params.ID)
 	}
 	if params.JobID != nil {
-		query.Query += fmt.Sprintf(" AND job_id = $%d", len(query.Args)+1)
+		query.Query += fmt.Sprintf(" AND e.job_id = $%d", len(query.Args)+1)
 		query.Args = append(query.Args, *params.JobID)
 	}
+	if len(params.Types) > 0 {
+		query.Query += fmt.Sprintf(" AND e.event_type = ANY($%d)", len(query.Args)+1)
+		query.Args = append(query.Args, params.Types)
+	}
+	if params.Seq != nil {
+		query.Query += fmt.Sprintf(" AND e.seq = $%d", len(query.Args)+1)
+		query.Args = append(query.Args, *params.Seq)
+	}
+	if params.AfterSeq != nil {
+		query.Query += fmt.Sprintf(" AND e.seq > $%d", len(query.Args)+1)
+		query.Args = append(query.Args, *params.AfterSeq)
+	}
+	if params.JobType != nil {
+		query.Query += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM jobs jt WHERE jt.id = e.job_id AND jt.type = $%d)", len(query.Args)+1)
+		query.Args = append(query.Args, *params.JobType)
+	}
+	if params.JobStatus != nil {
+		query.Query += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM jobs js WHERE js.id = e.job_id AND js.status = $%d)", len(query.Args)+1)
+		query.Args = append(query.Args, *params.JobStatus)
+	}
 
 	return nil
 }
 
+func (r *EventRepository) buildPagination(query *utils.QueryBuilder, params domain.EventSearchParams) {
+	query.Query += " ORDER BY e.seq ASC"
+
+	if params.Limit != nil {
+		query.Query += fmt.Sprintf(" LIMIT $%d", len(query.Args)+1)
+		query.Args = append(query.Args, *params.Limit)
+	}
+}
+
 // Insert implements ports.IEventRepository.
+// El NOTIFY se entrega recién cuando la transacción hace commit.
 func (r *EventRepository) Insert(ctx context.Context, event domain.Event) error {
 	query := utils.QueryBuilder{
 		Query: `
-		INSERT INTO events (
-			id,
-			job_id,
-			type,
-			message,
-			metadata,
-			created_at
-		) VALUES ($1, $2, $3, $4, $5, $6)
+		WITH ins AS (
+			INSERT INTO job_events (
+				id,
+				job_id,
+				tenant,
+				event_type,
+				message,
+				metadata,
+				created_at
+			) VALUES ($1, $2, (SELECT tenant FROM jobs WHERE id = $2), $3, $4, $5, $6)
+			RETURNING seq
+		)
+		SELECT pg_notify('` + EventsChannel + `', seq::text) FROM ins
 	`,
 		Args: []any{
 			event.ID,
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// subscriberBuffer es cuántos eventos puede acumular un subscriber lento antes de ser desconectado.
+const subscriberBuffer = 64
+
+type eventSubscriber struct {
+	filter domain.EventStreamFilter
+	ch     chan domain.StreamEvent
+}
+
+// EventListener escucha el canal de NOTIFY de eventos con una conexión
+// dedicada y reparte cada evento entre los subscribers del proceso. Así cada
+// instancia del server recibe los eventos insertados por cualquier worker.
+type EventListener struct {
+	pool   *pgxpool.Pool
+	repo   ports.IEventRepository
+	logger *slog.Logger
+
+	mu   sync.Mutex
+	subs map[*eventSubscriber]struct{}
+}
+
+var _ ports.IEventBroker = (*EventListener)(nil)
+
+// NewEventListener creates the listener. logger may be nil to use slog.Default().
+func NewEventListener(pool *pgxpool.Pool, logger *slog.Logger) *EventListener {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &EventListener{
+		pool:   pool,
+		repo:   NewEventRepository(nil, pool),
+		logger: logger,
+		subs:   make(map[*eventSubscriber]struct{}),
+	}
+}
+
+// Run escucha hasta que ctx termina, reconectando ante errores. Al salir
+// cierra todos los subscribers.
+func (l *EventListener) Run(ctx context.Context) {
+	defer l.closeAll()
+
+	backoff := time.Second
+	for ctx.Err() == nil {
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		l.logger.WarnContext(ctx, "listener error, reconnecting", "backoff", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+func (l *EventListener) listen(ctx context.Context) error {
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+EventsChannel); err != nil {
+		return err
+	}
+	l.logger.InfoContext(ctx, "listening for job events")
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			// La conexión queda en estado incierto, no se devuelve al pool
+			_ = conn.Conn().Close(context.Background())
+			return err
+		}
+
+		seq, err := strconv.ParseInt(notification.Payload, 10, 64)
+		if err != nil {
+			continue
+		}
+		l.dispatch(ctx, seq)
+	}
+}
+
+func (l *EventListener) dispatch(ctx context.Context, seq int64) {
+	l.mu.Lock()
+	empty := len(l.subs) == 0
+	l.mu.Unlock()
+	if empty {
+		return
+	}
+
+	events, err := l.repo.GetStream(ctx, domain.EventSearchParams{Seq: &seq})
+	if err != nil {
+		l.logger.ErrorContext(ctx, "failed to load event", "seq", seq, "error", err)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, event := range events {
+		for sub := range l.subs {
+			if !sub.filter.Matches(event) {
+				continue
+			}
+			select {
+			case sub.ch <- event:
+			default:
+				// Subscriber atrasado: se desconecta y puede reanudar con Last-Event-ID
+				delete(l.subs, sub)
+				close(sub.ch)
+			}
+		}
+	}
+}
+
+// Subscribe implements ports.IEventBroker.
+func (l *EventListener) Subscribe(filter domain.EventStreamFilter) (<-chan domain.StreamEvent, func()) {
+	sub := &eventSubscriber{
+		filter: filter,
+		ch:     make(chan domain.StreamEvent, subscriberBuffer),
+	}
+
+	l.mu.Lock()
+	l.subs[sub] = struct{}{}
+	l.mu.Unlock()
+
+	unsubscribe := func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if _, ok := l.subs[sub]; ok {
+			delete(l.subs, sub)
+			close(sub.ch)
+		}
+	}
+
+	return sub.ch, unsubscribe
+}
+
+func (l *EventListener) closeAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for sub := range l.subs {
+		delete(l.subs, sub)
+		close(sub.ch)
+	}
+}
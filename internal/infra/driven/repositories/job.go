@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"job_scheduler_go_rabbitmq/internal/core/domain"
 	"job_scheduler_go_rabbitmq/internal/core/ports"
@@ -15,12 +16,13 @@ import (
 )
 
 type JobRepository struct {
-	tx   pgx.Tx
-	pool *pgxpool.Pool
+	tx    pgx.Tx
+	pool  *pgxpool.Pool
+	clock Clock
 }
 
-func NewJobRepository(tx pgx.Tx, pool *pgxpool.Pool) ports.IJobRepository {
-	return &JobRepository{tx: tx, pool: pool}
+func NewJobRepository(tx pgx.Tx, pool *pgxpool.Pool, clock Clock) ports.IJobRepository {
+	return &JobRepository{tx: tx, pool: pool, clock: clock}
 }
 
 // GetDueJobs implements ports.IJobRepository.
@@ -28,16 +30,22 @@ func (r *JobRepository) Get(ctx context.Context, params domain.JobSearchParams)
 	query := utils.QueryBuilder{
 		Query: ` SELECT
 				j.id,
+				j.tenant,
 				j.type,
 				j.callback_url,
 				j.payload,
 				j.status,
 				j.max_retries,
+				j.attempts,
+				j.retry_deferred,
 				j.scheduled_at,
 				j.locked_at,
 				j.locked_by,
 				j.completed_at,
 				j.priority,
+				j.trace_context,
+				j.created_by_key_id,
+				j.batch_id,
 				j.created_at,
 				j.updated_at
 			FROM jobs j
@@ -46,9 +54,12 @@ func (r *JobRepository) Get(ctx context.Context, params domain.JobSearchParams)
 		Args: []any{},
 	}
 
-	if err := r.buildSearchParams(&query, params); err != nil {
+	if err := r.buildSearchParams(ctx, &query, params); err != nil {
 		return nil, fmt.Errorf("failed to build search params: %w", err)
 	}
+	if err := r.buildPagination(&query, params); err != nil {
+		return nil, fmt.Errorf("failed to build pagination: %w", err)
+	}
 
 	var rows pgx.Rows
 	var err error
@@ -67,16 +78,22 @@ func (r *JobRepository) Get(ctx context.Context, params domain.JobSearchParams)
 		var job domain.Job
 		err := rows.Scan(
 			&job.ID,
+			&job.Tenant,
 			&job.Type,
 			&job.CallbackURL,
 			&job.Payload,
 			&job.Status,
 			&job.MaxRetries,
+			&job.Attempts,
+			&job.RetryDeferred,
 			&job.ScheduledAt,
 			&job.LockedAt,
 			&job.LockedBy,
 			&job.CompletedAt,
 			&job.Priority,
+			&job.TraceContext,
+			&job.CreatedByKeyID,
+			&job.BatchID,
 			&job.CreatedAt,
 			&job.UpdatedAt,
 		)
@@ -99,16 +116,22 @@ func (r *JobRepository) GetOne(ctx context.Context, params domain.JobSearchParam
 	query := utils.QueryBuilder{
 		Query: ` SELECT
 				j.id,
+				j.tenant,
 				j.type,
 				j.callback_url,
 				j.payload,
 				j.status,
 				j.max_retries,
+				j.attempts,
+				j.retry_deferred,
 				j.scheduled_at,
 				j.locked_at,
 				j.locked_by,
 				j.completed_at,
 				j.priority,
+				j.trace_context,
+				j.created_by_key_id,
+				j.batch_id,
 				j.created_at,
 				j.updated_at
 			FROM jobs j
@@ -117,7 +140,7 @@ func (r *JobRepository) GetOne(ctx context.Context, params domain.JobSearchParam
 		Args: []any{},
 	}
 
-	if err := r.buildSearchParams(&query, params); err != nil {
+	if err := r.buildSearchParams(ctx, &query, params); err != nil {
 		return nil, fmt.Errorf("failed to build search params: %w", err)
 	}
 
@@ -131,27 +154,38 @@ func (r *JobRepository) GetOne(ctx context.Context, params domain.JobSearchParam
 	var job domain.Job
 	err := row.Scan(
 		&job.ID,
+		&job.Tenant,
 		&job.Type,
 		&job.CallbackURL,
 		&job.Payload,
 		&job.Status,
 		&job.MaxRetries,
+		&job.Attempts,
+		&job.RetryDeferred,
 		&job.ScheduledAt,
 		&job.LockedAt,
 		&job.LockedBy,
 		&job.CompletedAt,
 		&job.Priority,
+		&job.TraceContext,
+		&job.CreatedByKeyID,
+		&job.BatchID,
 		&job.CreatedAt,
 		&job.UpdatedAt,
 	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("job: %w", domain.ErrNotFound)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("[TEAM][REPOSITORY][GetOne()] Error en Scan: %v", err)
+		return nil, fmt.Errorf("[TEAM][REPOSITORY][GetOne()] Error en Scan: %w", err)
 	}
 
 	return &job, nil
 }
 
-func (r *JobRepository) buildSearchParams(qb *utils.QueryBuilder, params domain.JobSearchParams) error {
+func (r *JobRepository) buildSearchParams(ctx context.Context, qb *utils.QueryBuilder, params domain.JobSearchParams) error {
+	scopeTenant(ctx, qb, "j.tenant", params.Tenant)
+
 	if params.ID != nil {
 		qb.Query += fmt.Sprintf(" AND j.id = $%d", len(qb.Args)+1)
 		qb.Args = append(qb.Args, params.ID)
@@ -164,40 +198,309 @@ func (r *JobRepository) buildSearchParams(qb *utils.QueryBuilder, params domain.
 		qb.Query += fmt.Sprintf(" AND j.type = $%d", len(qb.Args)+1)
 		qb.Args = append(qb.Args, params.Type)
 	}
+	if len(params.Statuses) > 0 {
+		qb.Query += fmt.Sprintf(" AND j.status = ANY($%d)", len(qb.Args)+1)
+		qb.Args = append(qb.Args, params.Statuses)
+	}
+	if params.CreatedFrom != nil {
+		qb.Query += fmt.Sprintf(" AND j.created_at >= $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.CreatedFrom)
+	}
+	if params.CreatedTo != nil {
+		qb.Query += fmt.Sprintf(" AND j.created_at < $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.CreatedTo)
+	}
+	if params.ScheduledFrom != nil {
+		qb.Query += fmt.Sprintf(" AND j.scheduled_at >= $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.ScheduledFrom)
+	}
+	if params.ScheduledTo != nil {
+		qb.Query += fmt.Sprintf(" AND j.scheduled_at < $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.ScheduledTo)
+	}
+	if params.CreatedByKeyID != nil {
+		qb.Query += fmt.Sprintf(" AND j.created_by_key_id = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.CreatedByKeyID)
+	}
+	if params.BatchID != nil {
+		qb.Query += fmt.Sprintf(" AND j.batch_id = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.BatchID)
+	}
+	if params.Q != nil && *params.Q != "" {
+		qb.Query += fmt.Sprintf(" AND (j.type ILIKE $%d OR j.payload::text ILIKE $%d)", len(qb.Args)+1, len(qb.Args)+1)
+		qb.Args = append(qb.Args, "%"+*params.Q+"%")
+	}
 
-	if params.ReadyToRun != nil && *params.ReadyToRun {
-		now := time.Now()
+	if params.Dispatchable != nil && *params.Dispatchable {
+		qb.Query += fmt.Sprintf(" AND (j.status = $%d OR (j.status = $%d AND j.retry_deferred))", len(qb.Args)+1, len(qb.Args)+2)
+		qb.Args = append(qb.Args, domain.JobStatusPending, domain.JobStatusFailed)
+	}
 
-		qb.Query += fmt.Sprintf(
-			" AND (j.scheduled_at IS NULL OR j.scheduled_at <= $%d)",
-			len(qb.Args)+1,
-		)
-		qb.Args = append(qb.Args, now)
+	if params.ReadyToRun != nil && *params.ReadyToRun {
+		qb.Query += " AND (j.scheduled_at IS NULL OR j.scheduled_at <= " + r.clock.sqlNow(qb) + ")"
 	}
 
 	if params.LockFree != nil && *params.LockFree {
 		if params.LockTimeout != nil {
-			expiredAt := time.Now().Add(-*params.LockTimeout)
-
-			qb.Query += fmt.Sprintf(
-				" AND (j.locked_at IS NULL OR j.locked_at < $%d)",
-				len(qb.Args)+1,
-			)
-			qb.Args = append(qb.Args, expiredAt)
+			qb.Query += " AND (j.locked_at IS NULL OR j.locked_at < " + r.clock.sqlShift(qb, -*params.LockTimeout) + ")"
 		} else {
 			qb.Query += " AND j.locked_at IS NULL"
 		}
 	}
 
+	// Reparto por tenant: se rankean los jobs que cumplen los mismos filtros y
+	// se toman como máximo PerTenantLimit de cada tenant, así el backlog de uno
+	// no llena todo el lote
+	if params.PerTenantLimit != nil {
+		inner := utils.QueryBuilder{
+			Query: `SELECT j.id, ROW_NUMBER() OVER (PARTITION BY j.tenant ORDER BY j.created_at, j.id) AS tenant_rank
+				FROM jobs j
+				WHERE 1=1`,
+			Args: qb.Args,
+		}
+		innerParams := params
+		innerParams.PerTenantLimit = nil
+		if err := r.buildSearchParams(ctx, &inner, innerParams); err != nil {
+			return err
+		}
+		qb.Query += fmt.Sprintf(" AND j.id IN (SELECT ranked.id FROM (%s) ranked WHERE ranked.tenant_rank <= $%d)", inner.Query, len(inner.Args)+1)
+		qb.Args = append(inner.Args, *params.PerTenantLimit)
+	}
+
+	return nil
+}
+
+// jobSortColumns mapea los campos de orden a columna y tipo (para castear el cursor)
+var jobSortColumns = map[string][2]string{
+	"created_at":   {"j.created_at", "timestamptz"},
+	"updated_at":   {"j.updated_at", "timestamptz"},
+	"scheduled_at": {"COALESCE(j.scheduled_at, 'infinity')", "timestamptz"},
+	"priority":     {"j.priority", "int"},
+	"type":         {"j.type", "text"},
+	"status":       {"j.status", "text"},
+}
+
+// buildPagination agrega orden, cursor y límite. Va separado de
+// buildSearchParams para que Count no los incluya.
+func (r *JobRepository) buildPagination(qb *utils.QueryBuilder, params domain.JobSearchParams) error {
+	field, desc := "created_at", false
+	if params.Sort != nil && *params.Sort != "" {
+		field, desc = utils.ParseSort(*params.Sort)
+	}
+	column, ok := jobSortColumns[field]
+	if !ok {
+		return fmt.Errorf("sort field %q: %w", field, domain.ErrInvalid)
+	}
+
+	op, dir := ">", "ASC"
+	if desc {
+		op, dir = "<", "DESC"
+	}
+
+	if params.Cursor != nil && *params.Cursor != "" {
+		cursor, err := utils.DecodeCursor(*params.Cursor, utils.FormatSort(field, desc))
+		if err != nil {
+			return fmt.Errorf("%v: %w", err, domain.ErrInvalid)
+		}
+		// Un scheduled_at NULL viaja vacío en el cursor y ordena como 'infinity'
+		value := cursor.Value
+		if value == "" && column[1] == "timestamptz" {
+			value = "infinity"
+		}
+		qb.Query += fmt.Sprintf(" AND (%s, j.id) %s ($%d::%s, $%d::uuid)",
+			column[0], op, len(qb.Args)+1, column[1], len(qb.Args)+2)
+		qb.Args = append(qb.Args, value, cursor.ID)
+	}
+
+	qb.Query += fmt.Sprintf(" ORDER BY %s %s, j.id %s", column[0], dir, dir)
+
+	if params.Limit != nil {
+		qb.Query += fmt.Sprintf(" LIMIT $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.Limit)
+
+		if params.Page != nil && *params.Page > 1 && (params.Cursor == nil || *params.Cursor == "") {
+			qb.Query += fmt.Sprintf(" OFFSET $%d", len(qb.Args)+1)
+			qb.Args = append(qb.Args, (*params.Page-1)**params.Limit)
+		}
+	}
+
+	return nil
+}
+
+// Count implements ports.IJobRepository.
+func (r *JobRepository) Count(ctx context.Context, params domain.JobSearchParams) (int, error) {
+	query := &utils.QueryBuilder{
+		Query: `
+		SELECT COUNT(*)
+			FROM jobs j
+			WHERE 1=1`,
+		Args: []any{},
+	}
+
+	if err := r.buildSearchParams(ctx, query, params); err != nil {
+		return 0, fmt.Errorf("failed to build search params: %w", err)
+	}
+
+	var row pgx.Row
+	if r.tx != nil {
+		row = r.tx.QueryRow(ctx, query.Query, query.Args...)
+	} else {
+		row = r.pool.QueryRow(ctx, query.Query, query.Args...)
+	}
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to scan count: %w", err)
+	}
+
+	return count, nil
+}
+
+// Update implements ports.IJobRepository.
+// La condición sobre updated_at es la concurrencia optimista: si otro request
+// o el dispatcher tocaron el job entretanto, no se actualiza nada.
+func (r *JobRepository) Update(ctx context.Context, job domain.Job, prevUpdatedAt time.Time) error {
+	query := utils.QueryBuilder{
+		Query: `
+		UPDATE jobs
+		SET
+			scheduled_at = $1,
+			priority = $2,
+			max_retries = $3,
+			payload = $4,
+			callback_url = $5,
+			updated_at = $6
+		WHERE id = $7
+		AND updated_at = $8
+		AND status = ANY($9)
+	`,
+		Args: []any{
+			job.ScheduledAt,
+			job.Priority,
+			job.MaxRetries,
+			job.Payload,
+			job.CallbackURL,
+			job.UpdatedAt,
+			job.ID,
+			prevUpdatedAt,
+			domain.EditableJobStatuses,
+		},
+	}
+
+	var cmdTag pgconn.CommandTag
+	var err error
+
+	if r.tx != nil {
+		cmdTag, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		cmdTag, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("update job failed: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("job %s was modified concurrently: %w", job.ID, domain.ErrPreconditionFailed)
+	}
+
+	return nil
+}
+
+// InsertMany implements ports.IJobRepository.
+// Usa COPY, mucho más rápido que un INSERT por job en las altas masivas.
+func (r *JobRepository) InsertMany(ctx context.Context, jobs []domain.Job) error {
+	columns := []string{
+		"id",
+		"tenant",
+		"type",
+		"callback_url",
+		"payload",
+		"status",
+		"max_retries",
+		"scheduled_at",
+		"priority",
+		"trace_context",
+		"created_by_key_id",
+		"batch_id",
+		"attempts",
+		"retry_deferred",
+		"created_at",
+		"updated_at",
+	}
+	rows := make([][]any, 0, len(jobs))
+	for _, job := range jobs {
+		rows = append(rows, []any{
+			job.ID,
+			job.Tenant,
+			job.Type,
+			job.CallbackURL,
+			job.Payload,
+			job.Status,
+			job.MaxRetries,
+			job.ScheduledAt,
+			job.Priority,
+			job.TraceContext,
+			job.CreatedByKeyID,
+			job.BatchID,
+			job.Attempts,
+			job.RetryDeferred,
+			job.CreatedAt,
+			job.UpdatedAt,
+		})
+	}
+
+	var err error
+	if r.tx != nil {
+		_, err = r.tx.CopyFrom(ctx, pgx.Identifier{"jobs"}, columns, pgx.CopyFromRows(rows))
+	} else {
+		_, err = r.pool.CopyFrom(ctx, pgx.Identifier{"jobs"}, columns, pgx.CopyFromRows(rows))
+	}
+	if err != nil {
+		return fmt.Errorf("copy jobs failed: %w", err)
+	}
+
 	return nil
 }
 
+// QueuedTenants implements ports.IJobRepository.
+func (r *JobRepository) QueuedTenants(ctx context.Context) ([]string, error) {
+	// Un failed sin retry_deferred tiene su reintento en la cola del tenant
+	query := `SELECT DISTINCT j.tenant FROM jobs j WHERE j.status = $1 OR (j.status = $2 AND NOT j.retry_deferred)`
+
+	var rows pgx.Rows
+	var err error
+	if r.tx != nil {
+		rows, err = r.tx.Query(ctx, query, domain.JobStatusQueued, domain.JobStatusFailed)
+	} else {
+		rows, err = r.pool.Query(ctx, query, domain.JobStatusQueued, domain.JobStatusFailed)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var tenants []string
+	for rows.Next() {
+		var tenant string
+		if err := rows.Scan(&tenant); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return tenants, nil
+}
+
 // Insert implements ports.IJobRepository.
 func (r *JobRepository) Insert(ctx context.Context, job domain.Job) error {
 	query := utils.QueryBuilder{
 		Query: `
 		INSERT INTO jobs
 		(id, 
+		tenant,
 		type, 
 		callback_url, 
 		payload, 
@@ -205,11 +508,17 @@ func (r *JobRepository) Insert(ctx context.Context, job domain.Job) error {
 		max_retries, 
 		scheduled_at,
 		priority, 
+		trace_context,
+		created_by_key_id,
+		batch_id,
+		attempts,
+		retry_deferred,
 		created_at, 
 		updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`,
 		Args: []any{
 			job.ID,
+			job.Tenant,
 			job.Type,
 			job.CallbackURL,
 			job.Payload,
@@ -217,6 +526,11 @@ func (r *JobRepository) Insert(ctx context.Context, job domain.Job) error {
 			job.MaxRetries,
 			job.ScheduledAt,
 			job.Priority,
+			job.TraceContext,
+			job.CreatedByKeyID,
+			job.BatchID,
+			job.Attempts,
+			job.RetryDeferred,
 			job.CreatedAt,
 			job.UpdatedAt,
 		},
@@ -230,6 +544,10 @@ func (r *JobRepository) Insert(ctx context.Context, job domain.Job) error {
 		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
 	}
 
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return fmt.Errorf("job %s already exists: %w", job.ID, domain.ErrConflict)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to execute insert: %w", err)
 	}
@@ -238,32 +556,30 @@ func (r *JobRepository) Insert(ctx context.Context, job domain.Job) error {
 }
 
 // LockJob implements ports.IJobRepository.
-func (r *JobRepository) LockJob(ctx context.Context, jobID uuid.UUID, lockedBy string) error {
-	now := time.Now()
-	lockExpiry := now.Add(-5 * time.Minute)
-
+func (r *JobRepository) LockJob(ctx context.Context, jobID uuid.UUID, lockedBy string, lockTimeout time.Duration) error {
 	query := utils.QueryBuilder{
-		Query: `
-			UPDATE jobs
-			SET
-				locked_at = $1,
-				locked_by = $2,
-				updated_at = $1
-			WHERE id = $3
-			AND status = $4
-			AND (
-				locked_at IS NULL
-				OR locked_at < $5
-			)
-		`,
 		Args: []any{
-			now,
 			lockedBy,
 			jobID,
 			domain.JobStatusPending,
-			lockExpiry,
+			domain.JobStatusFailed,
 		},
 	}
+	now := r.clock.sqlNow(&query)
+	lockExpiry := r.clock.sqlShift(&query, -lockTimeout)
+	query.Query = `
+			UPDATE jobs
+			SET
+				locked_at = ` + now + `,
+				locked_by = $1,
+				updated_at = ` + now + `
+			WHERE id = $2
+			AND (status = $3 OR (status = $4 AND retry_deferred))
+			AND (
+				locked_at IS NULL
+				OR locked_at < ` + lockExpiry + `
+			)
+		`
 
 	var cmdTag pgconn.CommandTag
 	var err error
@@ -278,7 +594,7 @@ func (r *JobRepository) LockJob(ctx context.Context, jobID uuid.UUID, lockedBy s
 		return fmt.Errorf("lock job failed: %w", err)
 	}
 	if cmdTag.RowsAffected() == 0 {
-		return fmt.Errorf("job already locked or not pending")
+		return fmt.Errorf("job %s already locked or not pending: %w", jobID, domain.ErrConflict)
 	}
 
 	return nil
@@ -286,143 +602,139 @@ func (r *JobRepository) LockJob(ctx context.Context, jobID uuid.UUID, lockedBy s
 
 // MarkCompleted implements ports.IJobRepository.
 func (r *JobRepository) MarkCompleted(ctx context.Context, jobID uuid.UUID) error {
-	query := utils.QueryBuilder{
-		Query: `
-		UPDATE jobs
-		SET
-			status = $1,
+	return r.transition(ctx, jobID, domain.JobStatusCompleted, `
 			completed_at = $2,
+			attempts = attempts + 1,
 			locked_at = NULL,
-			locked_by = NULL,
-			updated_at = $2
-		WHERE id = $3
-		AND status = $4
-	`,
-		Args: []any{domain.JobStatusCompleted, time.Now(), jobID, domain.JobStatusRunning},
-	}
-	var err error
-
-	if r.tx != nil {
-		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
-	} else {
-		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
-	}
-
-	if err != nil {
-		return fmt.Errorf("mark completed failed: %w", err)
-	}
-	return nil
+			locked_by = NULL`)
 }
 
 // MarkFailed implements ports.IJobRepository.
 func (r *JobRepository) MarkFailed(ctx context.Context, jobID uuid.UUID, errMsg string, httpStatus *int) error {
-	query := utils.QueryBuilder{
-		Query: `
-		UPDATE jobs
-		SET
-			status = $1,
+	return r.transition(ctx, jobID, domain.JobStatusFailed, `
+			attempts = attempts + 1,
 			locked_at = NULL,
-			locked_by = NULL,
-			updated_at = $2
-		WHERE id = $3
-		AND status = $4
-	`,
-		Args: []any{domain.JobStatusFailed, time.Now(), jobID, domain.JobStatusRunning},
-	}
-	var err error
-
-	if r.tx != nil {
-		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
-	} else {
-		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
-	}
-
-	if err != nil {
-		return fmt.Errorf("mark failed failed: %w", err)
-	}
-	return nil
+			locked_by = NULL`)
 }
 
 // MarkQueued implements ports.IJobRepository.
 func (r *JobRepository) MarkQueued(ctx context.Context, jobID uuid.UUID) error {
-	query := utils.QueryBuilder{
-		Query: `
+	return r.transition(ctx, jobID, domain.JobStatusQueued, `
+			retry_deferred = false`)
+}
+
+// MarkRunning implements ports.IJobRepository.
+func (r *JobRepository) MarkRunning(ctx context.Context, jobID uuid.UUID) error {
+	return r.transition(ctx, jobID, domain.JobStatusRunning, "")
+}
+
+// MarkDead implements ports.IJobRepository.
+func (r *JobRepository) MarkDead(ctx context.Context, jobID uuid.UUID, reason string) error {
+	return r.transition(ctx, jobID, domain.JobStatusDead, `
+			attempts = attempts + 1,
+			locked_at = NULL,
+			locked_by = NULL`)
+}
+
+// Reschedule implements ports.IJobRepository.
+func (r *JobRepository) Reschedule(ctx context.Context, jobID uuid.UUID, runAt time.Time) error {
+	return r.transition(ctx, jobID, domain.JobStatusPending, `
+			scheduled_at = $5,
+			retry_deferred = false,
+			locked_at = NULL,
+			locked_by = NULL`, runAt)
+}
+
+// DeferRetry implements ports.IJobRepository.
+func (r *JobRepository) DeferRetry(ctx context.Context, jobID uuid.UUID, runAt time.Time) error {
+	query := `
 		UPDATE jobs
 		SET
-			status = $1,
-			updated_at = $2
+			scheduled_at = $1,
+			retry_deferred = true,
+			updated_at = $2,
+			locked_at = NULL,
+			locked_by = NULL
 		WHERE id = $3
 		AND status = $4
-	`,
-		Args: []any{domain.JobStatusQueued, time.Now(), jobID, domain.JobStatusPending},
-	}
+	`
+	args := []any{runAt, r.clock.Now(), jobID, domain.JobStatusFailed}
 
+	var cmdTag pgconn.CommandTag
 	var err error
 
 	if r.tx != nil {
-		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
+		cmdTag, err = r.tx.Exec(ctx, query, args...)
 	} else {
-		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
+		cmdTag, err = r.pool.Exec(ctx, query, args...)
 	}
-
 	if err != nil {
-		return fmt.Errorf("mark queued failed: %w", err)
+		return fmt.Errorf("defer retry failed: %w", err)
 	}
-	return nil
+	if cmdTag.RowsAffected() > 0 {
+		return nil
+	}
+
+	return r.transitionError(ctx, jobID, domain.JobStatusFailed)
 }
 
-// MarkRunning implements ports.IJobRepository.
-func (r *JobRepository) MarkRunning(ctx context.Context, jobID uuid.UUID) error {
+// transition pasa el job al estado to solo si su estado actual lo permite
+// según la máquina de estados del dominio. set son asignaciones extra del
+// UPDATE: $2 es el timestamp de la transición y args empiezan en $5.
+// Si el job no está en un estado de origen válido devuelve un
+// *domain.TransitionError con su estado real.
+func (r *JobRepository) transition(ctx context.Context, jobID uuid.UUID, to domain.JobStatus, set string, args ...any) error {
+	if set != "" {
+		set = "," + set
+	}
+
 	query := utils.QueryBuilder{
 		Query: `
 		UPDATE jobs
 		SET
 			status = $1,
-			updated_at = $2
+			updated_at = $2` + set + `
 		WHERE id = $3
-		AND status = $4
+		AND status = ANY($4)
 	`,
-		Args: []any{domain.JobStatusRunning, time.Now(), jobID, domain.JobStatusQueued},
+		Args: append([]any{to, r.clock.Now(), jobID, domain.TransitionSources(to)}, args...),
 	}
+
+	var cmdTag pgconn.CommandTag
 	var err error
 
 	if r.tx != nil {
-		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
+		cmdTag, err = r.tx.Exec(ctx, query.Query, query.Args...)
 	} else {
-		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
+		cmdTag, err = r.pool.Exec(ctx, query.Query, query.Args...)
 	}
 	if err != nil {
-		return fmt.Errorf("mark running failed: %w", err)
+		return fmt.Errorf("mark %s failed: %w", to, err)
 	}
-	return nil
-}
-
-// MarkDead implements ports.IJobRepository.
-func (r *JobRepository) MarkDead(ctx context.Context, jobID uuid.UUID, reason string) error {
-	query := utils.QueryBuilder{
-		Query: `
-		UPDATE jobs
-		SET
-			status = $1,
-			locked_at = NULL,
-			locked_by = NULL,
-			updated_at = $2
-		WHERE id = $3
-		AND status = $4
-	`,
-		Args: []any{domain.JobStatusDead, time.Now(), jobID, domain.JobStatusFailed},
+	if cmdTag.RowsAffected() > 0 {
+		return nil
 	}
 
-	var err error
+	return r.transitionError(ctx, jobID, to)
+}
 
+// transitionError explica un UPDATE de estado que no tocó ninguna fila: o el
+// job no existe o su estado no admite la transición.
+func (r *JobRepository) transitionError(ctx context.Context, jobID uuid.UUID, to domain.JobStatus) error {
+	var from domain.JobStatus
+	var err error
+	statusQuery := `SELECT status FROM jobs WHERE id = $1`
 	if r.tx != nil {
-		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
+		err = r.tx.QueryRow(ctx, statusQuery, jobID).Scan(&from)
 	} else {
-		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
+		err = r.pool.QueryRow(ctx, statusQuery, jobID).Scan(&from)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("job %s: %w", jobID, domain.ErrNotFound)
 	}
 	if err != nil {
-		return fmt.Errorf("mark dead failed: %w", err)
+		return fmt.Errorf("get job status failed: %w", err)
 	}
 
-	return nil
+	return &domain.TransitionError{JobID: jobID, From: from, To: to}
 }
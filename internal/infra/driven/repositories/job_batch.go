@@ -0,0 +1,288 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type JobBatchRepository struct {
+	tx    pgx.Tx
+	pool  *pgxpool.Pool
+	clock Clock
+}
+
+func NewJobBatchRepository(tx pgx.Tx, pool *pgxpool.Pool, clock Clock) ports.IJobBatchRepository {
+	return &JobBatchRepository{tx: tx, pool: pool, clock: clock}
+}
+
+const jobBatchColumns = `
+			b.id,
+			b.tenant,
+			b.total,
+			b.created,
+			b.rejected,
+			b.succeeded,
+			b.failed,
+			b.all_or_nothing,
+			b.callback_url,
+			b.callback_job_id,
+			b.created_by_key_id,
+			b.sealed_at,
+			b.completed_at,
+			b.created_at,
+			b.updated_at`
+
+// Insert implements ports.IJobBatchRepository.
+func (r *JobBatchRepository) Insert(ctx context.Context, batch domain.JobBatch) error {
+	query := utils.QueryBuilder{
+		Query: `
+		INSERT INTO job_batches
+		(id,
+		tenant,
+		total,
+		created,
+		rejected,
+		succeeded,
+		failed,
+		all_or_nothing,
+		callback_url,
+		created_by_key_id,
+		created_at,
+		updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		Args: []any{
+			batch.ID,
+			batch.Tenant,
+			batch.Total,
+			batch.Created,
+			batch.Rejected,
+			batch.Succeeded,
+			batch.Failed,
+			batch.AllOrNothing,
+			batch.CallbackURL,
+			batch.CreatedByKeyID,
+			batch.CreatedAt,
+			batch.UpdatedAt,
+		},
+	}
+
+	var err error
+	if r.tx != nil {
+		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("insert job batch failed: %w", err)
+	}
+
+	return nil
+}
+
+// GetOne implements ports.IJobBatchRepository.
+func (r *JobBatchRepository) GetOne(ctx context.Context, params domain.JobBatchSearchParams) (*domain.JobBatch, error) {
+	query := utils.QueryBuilder{
+		Query: ` SELECT` + jobBatchColumns + `
+			FROM job_batches b
+			WHERE 1=1
+		`,
+		Args: []any{},
+	}
+
+	if err := r.buildSearchParams(ctx, &query, params); err != nil {
+		return nil, fmt.Errorf("failed to build search params: %w", err)
+	}
+
+	return r.queryOne(ctx, query)
+}
+
+// CountJobs implements ports.IJobBatchRepository.
+func (r *JobBatchRepository) CountJobs(ctx context.Context, batchID uuid.UUID) (map[domain.JobStatus]int, error) {
+	query := utils.QueryBuilder{
+		Query: `SELECT status, COUNT(*) FROM jobs WHERE batch_id = $1 GROUP BY status`,
+		Args:  []any{batchID},
+	}
+
+	var rows pgx.Rows
+	var err error
+	if r.tx != nil {
+		rows, err = r.tx.Query(ctx, query.Query, query.Args...)
+	} else {
+		rows, err = r.pool.Query(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("count batch jobs failed: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[domain.JobStatus]int{}
+	for rows.Next() {
+		var status domain.JobStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		counts[status] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return counts, nil
+}
+
+// AddCreated implements ports.IJobBatchRepository.
+func (r *JobBatchRepository) AddCreated(ctx context.Context, batchID uuid.UUID, n int) error {
+	query := utils.QueryBuilder{
+		Query: `UPDATE job_batches SET created = created + $2 WHERE id = $1`,
+		Args:  []any{batchID, n},
+	}
+
+	var cmdTag pgconn.CommandTag
+	var err error
+
+	if r.tx != nil {
+		cmdTag, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		cmdTag, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("update job batch failed: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("job batch %s: %w", batchID, domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Seal implements ports.IJobBatchRepository.
+func (r *JobBatchRepository) Seal(ctx context.Context, batch domain.JobBatch) (*domain.JobBatch, error) {
+	query := utils.QueryBuilder{
+		Query: `
+		UPDATE job_batches b
+		SET
+			total = $2,
+			rejected = $3,
+			sealed_at = $4,
+			updated_at = $4
+		WHERE b.id = $1
+		RETURNING` + jobBatchColumns,
+		Args: []any{
+			batch.ID,
+			batch.Total,
+			batch.Rejected,
+			batch.UpdatedAt,
+		},
+	}
+
+	return r.queryOne(ctx, query)
+}
+
+// RecordResult implements ports.IJobBatchRepository.
+func (r *JobBatchRepository) RecordResult(ctx context.Context, batchID uuid.UUID, succeeded bool) (*domain.JobBatch, error) {
+	column := "failed"
+	if succeeded {
+		column = "succeeded"
+	}
+
+	query := utils.QueryBuilder{
+		Query: fmt.Sprintf(`
+		UPDATE job_batches b
+		SET
+			%[1]s = %[1]s + 1,
+			updated_at = $2
+		WHERE b.id = $1
+		RETURNING`, column) + jobBatchColumns,
+		Args: []any{batchID, r.clock.Now().UTC()},
+	}
+
+	return r.queryOne(ctx, query)
+}
+
+// MarkCompleted implements ports.IJobBatchRepository.
+func (r *JobBatchRepository) MarkCompleted(ctx context.Context, batchID uuid.UUID, callbackJobID *uuid.UUID, at time.Time) (bool, error) {
+	query := utils.QueryBuilder{
+		Query: `
+		UPDATE job_batches
+		SET
+			completed_at = $2,
+			callback_job_id = $3,
+			updated_at = $2
+		WHERE id = $1
+		AND completed_at IS NULL`,
+		Args: []any{batchID, at, callbackJobID},
+	}
+
+	var cmdTag pgconn.CommandTag
+	var err error
+
+	if r.tx != nil {
+		cmdTag, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		cmdTag, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return false, fmt.Errorf("complete job batch failed: %w", err)
+	}
+
+	return cmdTag.RowsAffected() == 1, nil
+}
+
+func (r *JobBatchRepository) queryOne(ctx context.Context, query utils.QueryBuilder) (*domain.JobBatch, error) {
+	var row pgx.Row
+	if r.tx != nil {
+		row = r.tx.QueryRow(ctx, query.Query, query.Args...)
+	} else {
+		row = r.pool.QueryRow(ctx, query.Query, query.Args...)
+	}
+
+	var b domain.JobBatch
+	err := row.Scan(
+		&b.ID,
+		&b.Tenant,
+		&b.Total,
+		&b.Created,
+		&b.Rejected,
+		&b.Succeeded,
+		&b.Failed,
+		&b.AllOrNothing,
+		&b.CallbackURL,
+		&b.CallbackJobID,
+		&b.CreatedByKeyID,
+		&b.SealedAt,
+		&b.CompletedAt,
+		&b.CreatedAt,
+		&b.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("job batch: %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan job batch: %w", err)
+	}
+
+	return &b, nil
+}
+
+func (r *JobBatchRepository) buildSearchParams(ctx context.Context, qb *utils.QueryBuilder, params domain.JobBatchSearchParams) error {
+	scopeTenant(ctx, qb, "b.tenant", params.Tenant)
+
+	if params.ID != nil {
+		qb.Query += fmt.Sprintf(" AND b.id = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.ID)
+	}
+
+	return nil
+}
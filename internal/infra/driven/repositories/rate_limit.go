@@ -0,0 +1,236 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/utils"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RateLimitRepository struct {
+	tx    pgx.Tx
+	pool  *pgxpool.Pool
+	clock Clock
+}
+
+func NewRateLimitRepository(tx pgx.Tx, pool *pgxpool.Pool, clock Clock) ports.IRateLimitRepository {
+	return &RateLimitRepository{tx: tx, pool: pool, clock: clock}
+}
+
+// Upsert implements ports.IRateLimitRepository.
+func (r *RateLimitRepository) Upsert(ctx context.Context, limit domain.RateLimit) error {
+	query := utils.QueryBuilder{
+		Query: `
+		INSERT INTO rate_limits
+		(scope,
+		key,
+		rate_per_second,
+		burst,
+		tokens,
+		refilled_at,
+		created_at,
+		updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (scope, key) DO UPDATE
+		SET
+			rate_per_second = EXCLUDED.rate_per_second,
+			burst = EXCLUDED.burst,
+			tokens = LEAST(rate_limits.tokens, EXCLUDED.burst),
+			updated_at = EXCLUDED.updated_at`,
+		Args: []any{
+			limit.Scope,
+			limit.Key,
+			limit.RatePerSecond,
+			limit.Burst,
+			limit.Tokens,
+			limit.RefilledAt,
+			limit.CreatedAt,
+			limit.UpdatedAt,
+		},
+	}
+
+	var err error
+	if r.tx != nil {
+		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("upsert rate limit failed: %w", err)
+	}
+
+	return nil
+}
+
+// Get implements ports.IRateLimitRepository.
+func (r *RateLimitRepository) Get(ctx context.Context, params domain.RateLimitSearchParams) ([]domain.RateLimit, error) {
+	query := utils.QueryBuilder{
+		Query: ` SELECT
+				rl.scope,
+				rl.key,
+				rl.rate_per_second,
+				rl.burst,
+				rl.tokens,
+				rl.refilled_at,
+				rl.created_at,
+				rl.updated_at
+			FROM rate_limits rl
+			WHERE 1=1
+		`,
+		Args: []any{},
+	}
+
+	if err := r.buildSearchParams(&query, params); err != nil {
+		return nil, fmt.Errorf("failed to build search params: %w", err)
+	}
+	query.Query += " ORDER BY rl.scope, rl.key"
+
+	var rows pgx.Rows
+	var err error
+	if r.tx != nil {
+		rows, err = r.tx.Query(ctx, query.Query, query.Args...)
+	} else {
+		rows, err = r.pool.Query(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRateLimits(rows)
+}
+
+// Delete implements ports.IRateLimitRepository.
+func (r *RateLimitRepository) Delete(ctx context.Context, key domain.RateLimitKey) error {
+	query := utils.QueryBuilder{
+		Query: `DELETE FROM rate_limits WHERE scope = $1 AND key = $2`,
+		Args:  []any{key.Scope, key.Key},
+	}
+
+	var err error
+	if r.tx != nil {
+		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("delete rate limit failed: %w", err)
+	}
+
+	return nil
+}
+
+// Acquire implements ports.IRateLimitRepository.
+func (r *RateLimitRepository) Acquire(ctx context.Context, keys []domain.RateLimitKey) (time.Duration, error) {
+	if r.tx == nil {
+		return 0, errors.New("acquire rate limit requires a transaction")
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	// Bloqueamos los buckets en orden fijo para evitar deadlocks entre workers
+	query := utils.QueryBuilder{
+		Query: ` SELECT
+				rl.scope,
+				rl.key,
+				rl.rate_per_second,
+				rl.burst,
+				rl.tokens,
+				rl.refilled_at,
+				rl.created_at,
+				rl.updated_at
+			FROM rate_limits rl
+			WHERE 1=0`,
+		Args: []any{},
+	}
+	for _, k := range keys {
+		query.Query += fmt.Sprintf(" OR (rl.scope = $%d AND rl.key = $%d)", len(query.Args)+1, len(query.Args)+2)
+		query.Args = append(query.Args, k.Scope, k.Key)
+	}
+	query.Query += " ORDER BY rl.scope, rl.key FOR UPDATE"
+
+	rows, err := r.tx.Query(ctx, query.Query, query.Args...)
+	if err != nil {
+		return 0, fmt.Errorf("lock rate limits failed: %w", err)
+	}
+	limits, err := scanRateLimits(rows)
+	rows.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	// Sin límites configurados para este job
+	if len(limits) == 0 {
+		return 0, nil
+	}
+
+	now, err := r.clock.current(ctx, r.tx)
+	if err != nil {
+		return 0, err
+	}
+	if wait := domain.TakeToken(limits, now); wait > 0 {
+		return wait, nil
+	}
+
+	for _, l := range limits {
+		_, err := r.tx.Exec(ctx, `
+			UPDATE rate_limits
+			SET
+				tokens = $1,
+				refilled_at = $2,
+				updated_at = $3
+			WHERE scope = $4 AND key = $5`,
+			l.Tokens, l.RefilledAt, l.UpdatedAt, l.Scope, l.Key,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("update rate limit failed: %w", err)
+		}
+	}
+
+	return 0, nil
+}
+
+func (r *RateLimitRepository) buildSearchParams(qb *utils.QueryBuilder, params domain.RateLimitSearchParams) error {
+	if params.Scope != nil {
+		qb.Query += fmt.Sprintf(" AND rl.scope = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.Scope)
+	}
+	if params.Key != nil {
+		qb.Query += fmt.Sprintf(" AND rl.key = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.Key)
+	}
+	return nil
+}
+
+func scanRateLimits(rows pgx.Rows) ([]domain.RateLimit, error) {
+	var limits []domain.RateLimit
+	for rows.Next() {
+		var l domain.RateLimit
+		if err := rows.Scan(
+			&l.Scope,
+			&l.Key,
+			&l.RatePerSecond,
+			&l.Burst,
+			&l.Tokens,
+			&l.RefilledAt,
+			&l.CreatedAt,
+			&l.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		limits = append(limits, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return limits, nil
+}
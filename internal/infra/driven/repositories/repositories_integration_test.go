@@ -0,0 +1,151 @@
+//go:build integration
+
+// Corre las suites de portstest contra Postgres. La base se configura como la
+// de los binarios (DB_HOST, DB_PORT, DB_USER, DB_PASSWORD, DB_DATABASE o
+// CONFIG_FILE) y tiene que ser descartable: cada subtest vacía todas las tablas.
+//
+//	DB_HOST=localhost DB_DATABASE=scheduler_test go test -tags integration ./internal/infra/driven/repositories/
+package repositories_test
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/configs"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/internal/core/ports/portstest"
+	"job_scheduler_go_rabbitmq/internal/infra/driven/repositories"
+	"job_scheduler_go_rabbitmq/internal/infra/migrate"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// openPool conecta a la base de test y la lleva a la última migración.
+func openPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	if os.Getenv("DB_HOST") == "" && os.Getenv("CONFIG_FILE") == "" {
+		t.Skip("DB_HOST or CONFIG_FILE not set")
+	}
+
+	cfg, err := configs.Load(configs.NeedDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := configs.NewDBConnection(cfg.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+
+	m, err := migrate.New(pool, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	return pool
+}
+
+// truncate vacía todas las tablas salvo schema_migrations.
+func truncate(t *testing.T, pool *pgxpool.Pool) {
+	t.Helper()
+	ctx := context.Background()
+
+	rows, err := pool.Query(ctx, `
+		SELECT tablename FROM pg_tables
+		WHERE schemaname = current_schema() AND tablename <> 'schema_migrations'`)
+	if err != nil {
+		t.Fatalf("list tables: %v", err)
+	}
+	tables, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		t.Fatalf("list tables: %v", err)
+	}
+	if len(tables) == 0 {
+		return
+	}
+
+	query := "TRUNCATE "
+	for i, table := range tables {
+		if i > 0 {
+			query += ", "
+		}
+		query += pgx.Identifier{table}.Sanitize()
+	}
+	if _, err := pool.Exec(ctx, query+" RESTART IDENTITY CASCADE"); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+}
+
+func TestRepositories(t *testing.T) {
+	pool := openPool(t)
+
+	// Con DBNow los vencimientos se comparan con now() de Postgres: las dos
+	// variantes tienen que comportarse igual
+	for _, tc := range []struct {
+		name  string
+		clock repositories.Clock
+	}{
+		{"SystemClock", repositories.Clock{}},
+		{"DBNow", repositories.Clock{DBNow: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			portstest.TestRepositories(t, func(t *testing.T) ports.IUnitOfWork {
+				truncate(t, pool)
+				return repositories.NewDataStore(pool, tc.clock, nil)
+			})
+		})
+	}
+}
+
+func TestEventBroker(t *testing.T) {
+	pool := openPool(t)
+
+	// Un solo listener para toda la suite: cada subtest se suscribe y se
+	// desuscribe, como los requests de SSE
+	ctx, cancel := context.WithCancel(context.Background())
+	listener := repositories.NewEventListener(pool, nil)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		listener.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	waitListening(t, pool)
+
+	portstest.TestEventBroker(t, func(t *testing.T) (ports.IUnitOfWork, ports.IEventBroker) {
+		truncate(t, pool)
+		return repositories.NewDataStore(pool, repositories.Clock{}, nil), listener
+	})
+}
+
+// waitListening espera a que la conexión del listener haya ejecutado LISTEN;
+// un evento confirmado antes se perdería.
+func waitListening(t *testing.T, pool *pgxpool.Pool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var listening bool
+		err := pool.QueryRow(context.Background(), `
+			SELECT EXISTS (
+				SELECT 1 FROM pg_stat_activity
+				WHERE pid <> pg_backend_pid() AND datname = current_database() AND query = 'LISTEN ' || $1
+			)`, repositories.EventsChannel).Scan(&listening)
+		if err != nil {
+			t.Fatalf("check listener: %v", err)
+		}
+		if listening {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("event listener did not start listening")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type StatsRepository struct {
+	tx   pgx.Tx
+	pool *pgxpool.Pool
+}
+
+func NewStatsRepository(tx pgx.Tx, pool *pgxpool.Pool) ports.IStatsRepository {
+	return &StatsRepository{tx: tx, pool: pool}
+}
+
+// Todas las consultas se limitan al tenant del request con
+// "($n::text IS NULL OR tenant = $n)": sin tenant se ven todos.
+
+// CountJobs implements ports.IStatsRepository.
+func (r *StatsRepository) CountJobs(ctx context.Context) ([]domain.JobCount, error) {
+	query := `
+		SELECT j.type, j.status, COUNT(*)
+		FROM jobs j
+		WHERE ($1::text IS NULL OR j.tenant = $1)
+		GROUP BY j.type, j.status
+		ORDER BY j.type, j.status`
+	tenant := domain.TenantFrom(ctx)
+
+	var rows pgx.Rows
+	var err error
+	if r.tx != nil {
+		rows, err = r.tx.Query(ctx, query, tenant)
+	} else {
+		rows, err = r.pool.Query(ctx, query, tenant)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []domain.JobCount
+	for rows.Next() {
+		var c domain.JobCount
+		if err := rows.Scan(&c.Type, &c.Status, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		counts = append(counts, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return counts, nil
+}
+
+// OldestPendingSince implements ports.IStatsRepository.
+func (r *StatsRepository) OldestPendingSince(ctx context.Context, now time.Time) (*time.Time, error) {
+	query := `
+		SELECT MIN(j.scheduled_at)
+		FROM jobs j
+		WHERE j.status = $1
+		AND j.scheduled_at <= $2
+		AND ($3::text IS NULL OR j.tenant = $3)`
+	args := []any{domain.JobStatusPending, now, domain.TenantFrom(ctx)}
+
+	var row pgx.Row
+	if r.tx != nil {
+		row = r.tx.QueryRow(ctx, query, args...)
+	} else {
+		row = r.pool.QueryRow(ctx, query, args...)
+	}
+
+	var oldest *time.Time
+	if err := row.Scan(&oldest); err != nil {
+		return nil, fmt.Errorf("failed to scan oldest pending: %w", err)
+	}
+
+	return oldest, nil
+}
+
+// Throughput implements ports.IStatsRepository.
+func (r *StatsRepository) Throughput(ctx context.Context, since time.Time) (domain.ThroughputStats, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM job_attempts a WHERE a.created_at >= $1 AND a.status = $2 AND ($5::text IS NULL OR a.tenant = $5)),
+			(SELECT COUNT(*) FROM job_attempts a WHERE a.created_at >= $1 AND a.status = $3 AND ($5::text IS NULL OR a.tenant = $5)),
+			(SELECT COUNT(*) FROM jobs j WHERE j.updated_at >= $1 AND j.status = $4 AND ($5::text IS NULL OR j.tenant = $5))`
+	args := []any{since, domain.AttemptStatusSuccess, domain.AttemptStatusFailed, domain.JobStatusDead, domain.TenantFrom(ctx)}
+
+	var row pgx.Row
+	if r.tx != nil {
+		row = r.tx.QueryRow(ctx, query, args...)
+	} else {
+		row = r.pool.QueryRow(ctx, query, args...)
+	}
+
+	stats := domain.ThroughputStats{WindowStartedAt: since}
+	if err := row.Scan(&stats.Succeeded, &stats.Failed, &stats.Dead); err != nil {
+		return domain.ThroughputStats{}, fmt.Errorf("failed to scan throughput: %w", err)
+	}
+
+	return stats, nil
+}
+
+// Durations implements ports.IStatsRepository.
+func (r *StatsRepository) Durations(ctx context.Context, since time.Time) (domain.DurationStats, error) {
+	query := `
+		SELECT
+			COUNT(a.duration_ms),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY a.duration_ms),
+			percentile_cont(0.95) WITHIN GROUP (ORDER BY a.duration_ms)
+		FROM job_attempts a
+		WHERE a.created_at >= $1
+		AND a.duration_ms IS NOT NULL
+		AND ($2::text IS NULL OR a.tenant = $2)`
+	tenant := domain.TenantFrom(ctx)
+
+	var row pgx.Row
+	if r.tx != nil {
+		row = r.tx.QueryRow(ctx, query, since, tenant)
+	} else {
+		row = r.pool.QueryRow(ctx, query, since, tenant)
+	}
+
+	var stats domain.DurationStats
+	if err := row.Scan(&stats.Samples, &stats.P50Ms, &stats.P95Ms); err != nil {
+		return domain.DurationStats{}, fmt.Errorf("failed to scan durations: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/utils"
+)
+
+// scopeTenant restringe la consulta al tenant de la API key del request y,
+// además, al tenant pedido en los parámetros. Se llama desde cada
+// buildSearchParams para que ningún servicio pueda olvidarse del filtro.
+func scopeTenant(ctx context.Context, qb *utils.QueryBuilder, column string, tenant *string) {
+	for _, t := range []*string{domain.TenantFrom(ctx), tenant} {
+		if t == nil {
+			continue
+		}
+		qb.Query += fmt.Sprintf(" AND %s = $%d", column, len(qb.Args)+1)
+		qb.Args = append(qb.Args, *t)
+	}
+}
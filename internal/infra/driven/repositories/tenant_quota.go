@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TenantQuotaRepository struct {
+	tx   pgx.Tx
+	pool *pgxpool.Pool
+}
+
+func NewTenantQuotaRepository(tx pgx.Tx, pool *pgxpool.Pool) ports.ITenantQuotaRepository {
+	return &TenantQuotaRepository{tx: tx, pool: pool}
+}
+
+// Upsert implements ports.ITenantQuotaRepository.
+func (r *TenantQuotaRepository) Upsert(ctx context.Context, quota domain.TenantQuota) error {
+	query := utils.QueryBuilder{
+		Query: `
+		INSERT INTO tenant_quotas
+		(tenant,
+		max_pending_jobs,
+		created_at,
+		updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tenant) DO UPDATE
+		SET
+			max_pending_jobs = EXCLUDED.max_pending_jobs,
+			updated_at = EXCLUDED.updated_at`,
+		Args: []any{
+			quota.Tenant,
+			quota.MaxPendingJobs,
+			quota.CreatedAt,
+			quota.UpdatedAt,
+		},
+	}
+
+	var err error
+	if r.tx != nil {
+		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("upsert tenant quota failed: %w", err)
+	}
+
+	return nil
+}
+
+// Get implements ports.ITenantQuotaRepository.
+func (r *TenantQuotaRepository) Get(ctx context.Context, params domain.TenantQuotaSearchParams) ([]domain.TenantQuota, error) {
+	query := utils.QueryBuilder{
+		Query: ` SELECT
+				tq.tenant,
+				tq.max_pending_jobs,
+				(SELECT COUNT(*) FROM jobs j
+					WHERE j.tenant = tq.tenant AND j.status = ANY($1)),
+				tq.created_at,
+				tq.updated_at
+			FROM tenant_quotas tq
+			WHERE 1=1
+		`,
+		Args: []any{domain.TenantQuotaStatuses},
+	}
+
+	if err := r.buildSearchParams(ctx, &query, params); err != nil {
+		return nil, fmt.Errorf("failed to build search params: %w", err)
+	}
+	query.Query += " ORDER BY tq.tenant"
+
+	var rows pgx.Rows
+	var err error
+	if r.tx != nil {
+		rows, err = r.tx.Query(ctx, query.Query, query.Args...)
+	} else {
+		rows, err = r.pool.Query(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var quotas []domain.TenantQuota
+	for rows.Next() {
+		var q domain.TenantQuota
+		if err := rows.Scan(
+			&q.Tenant,
+			&q.MaxPendingJobs,
+			&q.Pending,
+			&q.CreatedAt,
+			&q.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		quotas = append(quotas, q)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return quotas, nil
+}
+
+// Delete implements ports.ITenantQuotaRepository.
+func (r *TenantQuotaRepository) Delete(ctx context.Context, tenant string) error {
+	query := utils.QueryBuilder{
+		Query: `DELETE FROM tenant_quotas WHERE tenant = $1`,
+		Args:  []any{tenant},
+	}
+
+	var cmdTag pgconn.CommandTag
+	var err error
+
+	if r.tx != nil {
+		cmdTag, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		cmdTag, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("delete tenant quota failed: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("tenant quota %s: %w", tenant, domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Reserve implements ports.ITenantQuotaRepository.
+// El FOR UPDATE serializa las altas concurrentes del mismo tenant, así dos
+// requests no pueden pasar la cuota a la vez.
+func (r *TenantQuotaRepository) Reserve(ctx context.Context, tenant string, n int) (bool, error) {
+	if r.tx == nil {
+		return false, errors.New("reserve tenant quota requires a transaction")
+	}
+
+	var maxPending int
+	err := r.tx.QueryRow(ctx,
+		`SELECT max_pending_jobs FROM tenant_quotas WHERE tenant = $1 FOR UPDATE`,
+		tenant,
+	).Scan(&maxPending)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("lock tenant quota failed: %w", err)
+	}
+
+	var pending int
+	err = r.tx.QueryRow(ctx,
+		`SELECT COUNT(*) FROM jobs WHERE tenant = $1 AND status = ANY($2)`,
+		tenant, domain.TenantQuotaStatuses,
+	).Scan(&pending)
+	if err != nil {
+		return false, fmt.Errorf("count pending jobs failed: %w", err)
+	}
+
+	return pending+n <= maxPending, nil
+}
+
+func (r *TenantQuotaRepository) buildSearchParams(ctx context.Context, qb *utils.QueryBuilder, params domain.TenantQuotaSearchParams) error {
+	scopeTenant(ctx, qb, "tq.tenant", params.Tenant)
+	return nil
+}
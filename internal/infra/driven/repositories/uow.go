@@ -4,28 +4,43 @@ import (
 	"context"
 	"fmt"
 	"job_scheduler_go_rabbitmq/internal/core/ports"
-	"log"
+	"job_scheduler_go_rabbitmq/internal/infra/metrics"
+	"job_scheduler_go_rabbitmq/internal/infra/tracing"
+	"log/slog"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type DataStore struct {
-	tx   pgx.Tx        // Transacción activa (nil si no hay transacción)
-	pool *pgxpool.Pool // Conexión principal a la base de datos
+	tx     pgx.Tx        // Transacción activa (nil si no hay transacción)
+	pool   *pgxpool.Pool // Conexión principal a la base de datos
+	clock  Clock
+	logger *slog.Logger
 }
 
-func NewDataStore(pool *pgxpool.Pool) ports.IUnitOfWork {
+// NewDataStore creates the unit of work. The zero Clock uses the system clock
+// and logger may be nil to use slog.Default().
+func NewDataStore(pool *pgxpool.Pool, clock Clock, logger *slog.Logger) ports.IUnitOfWork {
+	if logger == nil {
+		logger = slog.Default()
+	}
 	return &DataStore{
-		pool: pool,
+		pool:   pool,
+		clock:  clock,
+		logger: logger,
 	}
 }
 
 // WithTx crea una nueva instancia de DataStore con una transacción
 func (ds *DataStore) WithTx(tx pgx.Tx) *DataStore {
 	return &DataStore{
-		tx:   tx,
-		pool: ds.pool,
+		tx:     tx,
+		pool:   ds.pool,
+		clock:  ds.clock,
+		logger: ds.logger,
 	}
 }
 
@@ -34,19 +49,23 @@ func (ds *DataStore) GetTx() pgx.Tx {
 	return ds.tx
 }
 
-// Atomic ejecuta una función dentro de una transacción
+// Atomic ejecuta una función dentro de una transacción.
+// Las transacciones normales se loguean en nivel debug (LOG_LEVEL_UOW=debug).
 func (ds *DataStore) Atomic(ctx context.Context, cb ports.FAtomicCallback) (err error) {
 	// Si ya estamos en una transacción, usarla directamente
 	if ds.tx != nil {
-		log.Println("[UOW][Atomic()] Ya hay una transacción activa, usando la transacción existente")
+		ds.logger.DebugContext(ctx, "reusing active transaction")
 		return cb(ds)
 	}
 
 	// Iniciar una transacción
-	log.Println("[UOW][Atomic()] Iniciando nueva transacción")
+	start := time.Now()
+	ctx, span := tracing.Tracer().Start(ctx, "db.transaction", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() { tracing.End(span, err) }()
+
 	tx, err := ds.pool.Begin(ctx)
 	if err != nil {
-		log.Println("[UOW][Atomic()] Error al iniciar transacción:", err)
+		ds.logger.ErrorContext(ctx, "begin transaction failed", "error", err)
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
@@ -54,26 +73,30 @@ func (ds *DataStore) Atomic(ctx context.Context, cb ports.FAtomicCallback) (err
 	defer func() {
 		if p := recover(); p != nil {
 			// En caso de panic, hacer rollback
-			log.Println("[UOW][Atomic()] Panic detectado, haciendo rollback:", p)
+			ds.logger.ErrorContext(ctx, "panic inside transaction, rolling back", "panic", p)
 			tx.Rollback(ctx)
+			observeTx(start, "rollback")
 			panic(p) // Re-panic después del rollback
 		} else if err != nil {
 			// En caso de error, hacer rollback
-			log.Println("[UOW][Atomic()] Error detectado, haciendo rollback:", err)
 			rbErr := tx.Rollback(ctx)
 			if rbErr != nil {
-				log.Println("[UOW][Atomic()] Error al hacer rollback:", rbErr)
+				ds.logger.ErrorContext(ctx, "rollback failed", "error", err, "rollback_error", rbErr)
 				err = fmt.Errorf("transaction error: %v, rollback error: %v", err, rbErr)
+			} else {
+				ds.logger.DebugContext(ctx, "transaction rolled back", "error", err, "duration", time.Since(start))
 			}
+			observeTx(start, "rollback")
 		} else {
 			// Si todo está bien, hacer commit
-			log.Println("[UOW][Atomic()] Transacción exitosa, haciendo commit")
 			err = tx.Commit(ctx)
 			if err != nil {
-				log.Println("[UOW][Atomic()] Error al hacer commit:", err)
+				ds.logger.ErrorContext(ctx, "commit failed", "error", err)
 				err = fmt.Errorf("failed to commit transaction: %w", err)
+				observeTx(start, "commit_error")
 			} else {
-				log.Println("[UOW][Atomic()] Commit exitoso")
+				ds.logger.DebugContext(ctx, "transaction committed", "duration", time.Since(start))
+				observeTx(start, "commit")
 			}
 		}
 	}()
@@ -82,24 +105,55 @@ func (ds *DataStore) Atomic(ctx context.Context, cb ports.FAtomicCallback) (err
 	dataStoreTx := ds.WithTx(tx)
 
 	// Ejecutar el callback
-	log.Println("[UOW][Atomic()] Ejecutando callback dentro de la transacción")
 	err = cb(dataStoreTx)
-	if err != nil {
-		log.Println("[UOW][Atomic()] Error en callback:", err)
-	}
 
 	return err
 }
 
+// observeTx registra la duración de la transacción y cuenta los rollbacks.
+func observeTx(start time.Time, outcome string) {
+	metrics.TxDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+	if outcome == "rollback" {
+		metrics.TxRollbacks.Inc()
+	}
+}
+
 func (ds *DataStore) Job() ports.IJobRepository {
-	return NewJobRepository(ds.tx, ds.pool)
+	return NewJobRepository(ds.tx, ds.pool, ds.clock)
+}
+func (ds *DataStore) JobBatch() ports.IJobBatchRepository {
+	return NewJobBatchRepository(ds.tx, ds.pool, ds.clock)
 }
 func (ds *DataStore) Attempt() ports.IAttemptRepository {
-	return NewAttemptRepository(ds.tx, ds.pool)
+	return NewAttemptRepository(ds.tx, ds.pool, ds.clock)
 }
 func (ds *DataStore) Event() ports.IEventRepository {
 	return NewEventRepository(ds.tx, ds.pool)
 }
+func (ds *DataStore) RateLimit() ports.IRateLimitRepository {
+	return NewRateLimitRepository(ds.tx, ds.pool, ds.clock)
+}
+func (ds *DataStore) ConcurrencyLimit() ports.IConcurrencyLimitRepository {
+	return NewConcurrencyLimitRepository(ds.tx, ds.pool, ds.clock)
+}
+func (ds *DataStore) CircuitBreaker() ports.ICircuitBreakerRepository {
+	return NewCircuitBreakerRepository(ds.tx, ds.pool)
+}
+func (ds *DataStore) WebhookSubscription() ports.IWebhookSubscriptionRepository {
+	return NewWebhookSubscriptionRepository(ds.tx, ds.pool, ds.clock)
+}
+func (ds *DataStore) WebhookDelivery() ports.IWebhookDeliveryRepository {
+	return NewWebhookDeliveryRepository(ds.tx, ds.pool, ds.clock)
+}
+func (ds *DataStore) Stats() ports.IStatsRepository {
+	return NewStatsRepository(ds.tx, ds.pool)
+}
+func (ds *DataStore) APIKey() ports.IAPIKeyRepository {
+	return NewAPIKeyRepository(ds.tx, ds.pool)
+}
+func (ds *DataStore) TenantQuota() ports.ITenantQuotaRepository {
+	return NewTenantQuotaRepository(ds.tx, ds.pool)
+}
 
 // func (ds *DataStore) DeadLetter() ports.IDeadLetterRepository {
 // 	return NewDeadLetterRepository(ds.tx, ds.pool)
@@ -0,0 +1,396 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WebhookDeliveryRepository struct {
+	tx    pgx.Tx
+	pool  *pgxpool.Pool
+	clock Clock
+}
+
+func NewWebhookDeliveryRepository(tx pgx.Tx, pool *pgxpool.Pool, clock Clock) ports.IWebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{tx: tx, pool: pool, clock: clock}
+}
+
+const webhookDeliveryColumns = `
+			d.id,
+			d.subscription_id,
+			d.event_id,
+			d.job_id,
+			d.event_type,
+			d.url,
+			d.status,
+			d.attempts,
+			d.max_attempts,
+			d.next_attempt_at,
+			d.last_http_status,
+			d.last_error,
+			d.delivered_at,
+			d.created_at,
+			d.updated_at`
+
+// Enqueue implements ports.IWebhookDeliveryRepository.
+// Una suscripción coincide si es del job o global (opcionalmente por tipo) y
+// escucha el tipo del evento; sin tipos de evento escucha todos.
+func (r *WebhookDeliveryRepository) Enqueue(ctx context.Context, eventID uuid.UUID) error {
+	now := r.clock.Now()
+	query := utils.QueryBuilder{
+		Query: `
+		INSERT INTO webhook_deliveries
+		(id,
+		tenant,
+		subscription_id,
+		event_id,
+		job_id,
+		event_type,
+		url,
+		status,
+		attempts,
+		max_attempts,
+		next_attempt_at,
+		created_at,
+		updated_at)
+		SELECT
+			gen_random_uuid(),
+			s.tenant,
+			s.id,
+			e.id,
+			e.job_id,
+			e.event_type,
+			s.url,
+			$2,
+			0,
+			s.max_attempts,
+			$3,
+			$3,
+			$3
+		FROM job_events e
+		JOIN jobs j ON j.id = e.job_id
+		JOIN webhook_subscriptions s
+			ON s.active
+			AND s.tenant = j.tenant
+			AND (s.job_id IS NULL OR s.job_id = e.job_id)
+			AND (s.job_type IS NULL OR s.job_type = j.type)
+			AND (cardinality(s.event_types) = 0 OR e.event_type = ANY(s.event_types))
+		WHERE e.id = $1`,
+		Args: []any{eventID, domain.WebhookDeliveryPending, now},
+	}
+
+	var err error
+	if r.tx != nil {
+		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("enqueue webhook deliveries failed: %w", err)
+	}
+
+	return nil
+}
+
+// Claim implements ports.IWebhookDeliveryRepository.
+// Mueve next_attempt_at al final del lease: si el proceso cae a mitad del
+// envío, la entrega vuelve a estar vencida cuando expira.
+func (r *WebhookDeliveryRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]domain.PendingWebhook, error) {
+	query := utils.QueryBuilder{
+		Args: []any{domain.WebhookDeliveryPending, limit},
+	}
+	now := r.clock.sqlNow(&query)
+	leaseEnd := r.clock.sqlShift(&query, lease)
+	query.Query = `
+		WITH due AS (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = $1
+			AND next_attempt_at <= ` + now + `
+			ORDER BY next_attempt_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		), claimed AS (
+			UPDATE webhook_deliveries d
+			SET next_attempt_at = ` + leaseEnd + `
+			FROM due
+			WHERE d.id = due.id
+			RETURNING d.*
+		)
+		SELECT` + webhookDeliveryColumns + `,
+			e.id,
+			e.seq,
+			e.job_id,
+			e.event_type,
+			e.message,
+			e.metadata,
+			e.created_at,
+			s.secret
+		FROM claimed d
+		JOIN job_events e ON e.id = d.event_id
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		ORDER BY d.next_attempt_at, d.id`
+
+	var rows pgx.Rows
+	var err error
+	if r.tx != nil {
+		rows, err = r.tx.Query(ctx, query.Query, query.Args...)
+	} else {
+		rows, err = r.pool.Query(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("claim webhook deliveries failed: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []domain.PendingWebhook
+	for rows.Next() {
+		var p domain.PendingWebhook
+		d := &p.Delivery
+		if err := rows.Scan(
+			&d.ID,
+			&d.SubscriptionID,
+			&d.EventID,
+			&d.JobID,
+			&d.EventType,
+			&d.URL,
+			&d.Status,
+			&d.Attempts,
+			&d.MaxAttempts,
+			&d.NextAttemptAt,
+			&d.LastHTTPStatus,
+			&d.LastError,
+			&d.DeliveredAt,
+			&d.CreatedAt,
+			&d.UpdatedAt,
+			&p.Event.ID,
+			&p.Event.Seq,
+			&p.Event.JobID,
+			&p.Event.Type,
+			&p.Event.Message,
+			&p.Event.Metadata,
+			&p.Event.CreatedAt,
+			&p.Secret,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		pending = append(pending, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return pending, nil
+}
+
+// Update implements ports.IWebhookDeliveryRepository.
+func (r *WebhookDeliveryRepository) Update(ctx context.Context, delivery domain.WebhookDelivery) error {
+	query := utils.QueryBuilder{
+		Query: `
+		UPDATE webhook_deliveries
+		SET
+			status = $2,
+			attempts = $3,
+			next_attempt_at = $4,
+			last_http_status = $5,
+			last_error = $6,
+			delivered_at = $7,
+			updated_at = $8
+		WHERE id = $1`,
+		Args: []any{
+			delivery.ID,
+			delivery.Status,
+			delivery.Attempts,
+			delivery.NextAttemptAt,
+			delivery.LastHTTPStatus,
+			delivery.LastError,
+			delivery.DeliveredAt,
+			delivery.UpdatedAt,
+		},
+	}
+
+	var err error
+	if r.tx != nil {
+		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("update webhook delivery failed: %w", err)
+	}
+
+	return nil
+}
+
+// Get implements ports.IWebhookDeliveryRepository.
+func (r *WebhookDeliveryRepository) Get(ctx context.Context, params domain.WebhookDeliverySearchParams) ([]domain.WebhookDelivery, error) {
+	query := utils.QueryBuilder{
+		Query: `SELECT` + webhookDeliveryColumns + `
+		FROM webhook_deliveries d
+		WHERE 1=1`,
+		Args: []any{},
+	}
+
+	if err := r.buildSearchParams(ctx, &query, params); err != nil {
+		return nil, fmt.Errorf("failed to build search params: %w", err)
+	}
+	if err := r.buildPagination(&query, params); err != nil {
+		return nil, err
+	}
+
+	var rows pgx.Rows
+	var err error
+	if r.tx != nil {
+		rows, err = r.tx.Query(ctx, query.Query, query.Args...)
+	} else {
+		rows, err = r.pool.Query(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		if err := rows.Scan(
+			&d.ID,
+			&d.SubscriptionID,
+			&d.EventID,
+			&d.JobID,
+			&d.EventType,
+			&d.URL,
+			&d.Status,
+			&d.Attempts,
+			&d.MaxAttempts,
+			&d.NextAttemptAt,
+			&d.LastHTTPStatus,
+			&d.LastError,
+			&d.DeliveredAt,
+			&d.CreatedAt,
+			&d.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// Count implements ports.IWebhookDeliveryRepository.
+func (r *WebhookDeliveryRepository) Count(ctx context.Context, params domain.WebhookDeliverySearchParams) (int, error) {
+	query := &utils.QueryBuilder{
+		Query: `
+		SELECT COUNT(*)
+			FROM webhook_deliveries d
+			WHERE 1=1`,
+		Args: []any{},
+	}
+
+	if err := r.buildSearchParams(ctx, query, params); err != nil {
+		return 0, err
+	}
+
+	var count int
+	var row pgx.Row
+
+	if r.tx != nil {
+		row = r.tx.QueryRow(ctx, query.Query, query.Args...)
+	} else {
+		row = r.pool.QueryRow(ctx, query.Query, query.Args...)
+	}
+
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to scan count: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *WebhookDeliveryRepository) buildSearchParams(ctx context.Context, qb *utils.QueryBuilder, params domain.WebhookDeliverySearchParams) error {
+	scopeTenant(ctx, qb, "d.tenant", params.Tenant)
+
+	if params.ID != nil {
+		qb.Query += fmt.Sprintf(" AND d.id = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.ID)
+	}
+	if params.SubscriptionID != nil {
+		qb.Query += fmt.Sprintf(" AND d.subscription_id = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.SubscriptionID)
+	}
+	if params.JobID != nil {
+		qb.Query += fmt.Sprintf(" AND d.job_id = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.JobID)
+	}
+	if params.EventType != nil {
+		qb.Query += fmt.Sprintf(" AND d.event_type = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.EventType)
+	}
+	if params.Status != nil {
+		qb.Query += fmt.Sprintf(" AND d.status = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.Status)
+	}
+	return nil
+}
+
+// webhookDeliverySortColumns mapea los campos de orden a columna y tipo (para castear el cursor)
+var webhookDeliverySortColumns = map[string][2]string{
+	"created_at": {"d.created_at", "timestamptz"},
+	"updated_at": {"d.updated_at", "timestamptz"},
+}
+
+// buildPagination agrega orden, cursor y límite. Va separado de
+// buildSearchParams para que Count no los incluya.
+func (r *WebhookDeliveryRepository) buildPagination(query *utils.QueryBuilder, params domain.WebhookDeliverySearchParams) error {
+	field, desc := "created_at", false
+	if params.Sort != nil && *params.Sort != "" {
+		field, desc = utils.ParseSort(*params.Sort)
+	}
+	column, ok := webhookDeliverySortColumns[field]
+	if !ok {
+		return fmt.Errorf("sort field %q: %w", field, domain.ErrInvalid)
+	}
+
+	op, dir := ">", "ASC"
+	if desc {
+		op, dir = "<", "DESC"
+	}
+
+	if params.Cursor != nil && *params.Cursor != "" {
+		cursor, err := utils.DecodeCursor(*params.Cursor, utils.FormatSort(field, desc))
+		if err != nil {
+			return fmt.Errorf("%v: %w", err, domain.ErrInvalid)
+		}
+		query.Query += fmt.Sprintf(" AND (%s, d.id) %s ($%d::%s, $%d::uuid)",
+			column[0], op, len(query.Args)+1, column[1], len(query.Args)+2)
+		query.Args = append(query.Args, cursor.Value, cursor.ID)
+	}
+
+	query.Query += fmt.Sprintf(" ORDER BY %s %s, d.id %s", column[0], dir, dir)
+
+	if params.Limit != nil {
+		query.Query += fmt.Sprintf(" LIMIT $%d", len(query.Args)+1)
+		query.Args = append(query.Args, *params.Limit)
+
+		if params.Page != nil && *params.Page > 1 && (params.Cursor == nil || *params.Cursor == "") {
+			query.Query += fmt.Sprintf(" OFFSET $%d", len(query.Args)+1)
+			query.Args = append(query.Args, (*params.Page-1)**params.Limit)
+		}
+	}
+
+	return nil
+}
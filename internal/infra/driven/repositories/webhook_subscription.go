@@ -0,0 +1,204 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WebhookSubscriptionRepository struct {
+	tx    pgx.Tx
+	pool  *pgxpool.Pool
+	clock Clock
+}
+
+func NewWebhookSubscriptionRepository(tx pgx.Tx, pool *pgxpool.Pool, clock Clock) ports.IWebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{tx: tx, pool: pool, clock: clock}
+}
+
+// Insert implements ports.IWebhookSubscriptionRepository.
+func (r *WebhookSubscriptionRepository) Insert(ctx context.Context, subscription domain.WebhookSubscription) error {
+	eventTypes := make([]string, 0, len(subscription.EventTypes))
+	for _, t := range subscription.EventTypes {
+		eventTypes = append(eventTypes, string(t))
+	}
+
+	query := utils.QueryBuilder{
+		Query: `
+		INSERT INTO webhook_subscriptions
+		(id,
+		tenant,
+		job_id,
+		job_type,
+		event_types,
+		url,
+		secret,
+		max_attempts,
+		active,
+		created_at,
+		updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		Args: []any{
+			subscription.ID,
+			subscription.Tenant,
+			subscription.JobID,
+			subscription.JobType,
+			eventTypes,
+			subscription.URL,
+			subscription.Secret,
+			subscription.MaxAttempts,
+			subscription.Active,
+			subscription.CreatedAt,
+			subscription.UpdatedAt,
+		},
+	}
+
+	var err error
+	if r.tx != nil {
+		_, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		_, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("insert webhook subscription failed: %w", err)
+	}
+
+	return nil
+}
+
+// Get implements ports.IWebhookSubscriptionRepository.
+// Solo devuelve suscripciones activas.
+func (r *WebhookSubscriptionRepository) Get(ctx context.Context, params domain.WebhookSubscriptionSearchParams) ([]domain.WebhookSubscription, error) {
+	query := utils.QueryBuilder{
+		Query: ` SELECT
+				s.id,
+				s.tenant,
+				s.job_id,
+				s.job_type,
+				s.event_types,
+				s.url,
+				s.secret,
+				s.max_attempts,
+				s.active,
+				s.created_at,
+				s.updated_at
+			FROM webhook_subscriptions s
+			WHERE s.active
+		`,
+		Args: []any{},
+	}
+
+	if err := r.buildSearchParams(ctx, &query, params); err != nil {
+		return nil, fmt.Errorf("failed to build search params: %w", err)
+	}
+	query.Query += " ORDER BY s.created_at, s.id"
+
+	var rows pgx.Rows
+	var err error
+	if r.tx != nil {
+		rows, err = r.tx.Query(ctx, query.Query, query.Args...)
+	} else {
+		rows, err = r.pool.Query(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []domain.WebhookSubscription
+	for rows.Next() {
+		var s domain.WebhookSubscription
+		var eventTypes []string
+		if err := rows.Scan(
+			&s.ID,
+			&s.Tenant,
+			&s.JobID,
+			&s.JobType,
+			&eventTypes,
+			&s.URL,
+			&s.Secret,
+			&s.MaxAttempts,
+			&s.Active,
+			&s.CreatedAt,
+			&s.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		s.EventTypes = make([]domain.EventType, 0, len(eventTypes))
+		for _, t := range eventTypes {
+			s.EventTypes = append(s.EventTypes, domain.EventType(t))
+		}
+		subscriptions = append(subscriptions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// Delete implements ports.IWebhookSubscriptionRepository.
+// La suscripción se desactiva en lugar de borrarse para conservar el historial de entregas.
+func (r *WebhookSubscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	now := r.clock.Now()
+	query := utils.QueryBuilder{
+		Query: `UPDATE webhook_subscriptions SET active = false, updated_at = $2 WHERE id = $1 AND active`,
+		Args:  []any{id, now},
+	}
+
+	var cmdTag pgconn.CommandTag
+	var err error
+
+	if r.tx != nil {
+		cmdTag, err = r.tx.Exec(ctx, query.Query, query.Args...)
+	} else {
+		cmdTag, err = r.pool.Exec(ctx, query.Query, query.Args...)
+	}
+	if err != nil {
+		return fmt.Errorf("delete webhook subscription failed: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("webhook subscription %s: %w", id, domain.ErrNotFound)
+	}
+
+	// Las entregas pendientes de la suscripción ya no se envían
+	cancel := `UPDATE webhook_deliveries SET status = $2, next_attempt_at = NULL, last_error = 'subscription deleted', updated_at = $3 WHERE subscription_id = $1 AND status = $4`
+	args := []any{id, domain.WebhookDeliveryDead, now, domain.WebhookDeliveryPending}
+
+	if r.tx != nil {
+		_, err = r.tx.Exec(ctx, cancel, args...)
+	} else {
+		_, err = r.pool.Exec(ctx, cancel, args...)
+	}
+	if err != nil {
+		return fmt.Errorf("cancel pending webhook deliveries failed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookSubscriptionRepository) buildSearchParams(ctx context.Context, qb *utils.QueryBuilder, params domain.WebhookSubscriptionSearchParams) error {
+	scopeTenant(ctx, qb, "s.tenant", params.Tenant)
+
+	if params.ID != nil {
+		qb.Query += fmt.Sprintf(" AND s.id = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.ID)
+	}
+	if params.JobID != nil {
+		qb.Query += fmt.Sprintf(" AND s.job_id = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.JobID)
+	}
+	if params.JobType != nil {
+		qb.Query += fmt.Sprintf(" AND s.job_type = $%d", len(qb.Args)+1)
+		qb.Args = append(qb.Args, *params.JobType)
+	}
+	return nil
+}
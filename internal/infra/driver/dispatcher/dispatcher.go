@@ -2,38 +2,80 @@ package dispatcher
 
 import (
 	"context"
-	"log"
+	"log/slog"
+	"sync/atomic"
+	"time"
 
 	"job_scheduler_go_rabbitmq/internal/core/domain"
 	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/internal/infra/logging"
+	"job_scheduler_go_rabbitmq/internal/infra/metrics"
 	"job_scheduler_go_rabbitmq/utils"
 )
 
+// Settings ajusta cuántos jobs toma cada tick y cuándo vence un lock.
+type Settings struct {
+	// BatchSize es el tope de jobs por tick y PerTenantLimit el de cada tenant,
+	// así el backlog de uno no llena todo el lote
+	BatchSize      uint
+	PerTenantLimit uint
+	// LockTimeout es cuándo se considera abandonado el lock de otro dispatcher
+	LockTimeout time.Duration
+}
+
 // Dispatcher is responsible for dispatching jobs to RabbitMQ.
 type Dispatcher struct {
-	repo   ports.IJobRepository
-	rabbit ports.IRabbitMQClient
-	nodeID string
+	repo     ports.IJobRepository
+	rabbit   ports.IRabbitMQClient
+	nodeID   string
+	clock    ports.IClock
+	settings Settings
+	logger   *slog.Logger
+	// lastSuccess es el unix nano del último RunOnce sin error (ver LastSuccess)
+	lastSuccess atomic.Int64
 }
 
-// New creates a new Dispatcher instance.
-func New(repo ports.IJobRepository, rabbit ports.IRabbitMQClient, nodeID string) *Dispatcher {
-	return &Dispatcher{
-		repo:   repo,
-		rabbit: rabbit,
-		nodeID: nodeID,
+// New creates a new Dispatcher instance. clock decides when a job reverted
+// after a failed publish runs again; liveness always uses the system clock.
+func New(repo ports.IJobRepository, rabbit ports.IRabbitMQClient, nodeID string, clock ports.IClock, settings Settings, logger *slog.Logger) *Dispatcher {
+	d := &Dispatcher{
+		repo:     repo,
+		rabbit:   rabbit,
+		nodeID:   nodeID,
+		clock:    clock,
+		settings: settings,
+		logger:   logger.With("node_id", nodeID),
 	}
+	// Se cuenta el arranque como éxito para no fallar el probe antes del primer tick
+	d.lastSuccess.Store(time.Now().UnixNano())
+	return d
+}
+
+// LastSuccess returns when RunOnce last completed without error.
+func (d *Dispatcher) LastSuccess() time.Time {
+	return time.Unix(0, d.lastSuccess.Load())
 }
 
 // RunOnce dispatches pending ready jobs by publishing them to RabbitMQ.
 func (d *Dispatcher) RunOnce(ctx context.Context) error {
-	limit := uint(50)
-	pending := domain.JobStatusPending
+	start := time.Now()
+	defer func() {
+		metrics.DispatcherTickDuration.Observe(time.Since(start).Seconds())
+	}()
 
+	// Hasta PerTenantLimit jobs de cada tenant por tick, con un tope global.
+	// Los locks vencidos cuentan como libres: el dispatcher que los tomó se cayó
+	perTenant := d.settings.PerTenantLimit
+	limit := d.settings.BatchSize
+	lockTimeout := d.settings.LockTimeout
+
+	// Jobs pending y reintentos diferidos por rate/concurrency limit
 	jobs, err := d.repo.Get(ctx, domain.JobSearchParams{
-		Status:     &pending,
-		ReadyToRun: func(b bool) *bool { return &b }(true),
-		LockFree:   func(b bool) *bool { return &b }(true),
+		Dispatchable:   func(b bool) *bool { return &b }(true),
+		ReadyToRun:     func(b bool) *bool { return &b }(true),
+		LockFree:       func(b bool) *bool { return &b }(true),
+		LockTimeout:    &lockTimeout,
+		PerTenantLimit: &perTenant,
 		SearchParams: utils.SearchParams{
 			Limit: &limit,
 		},
@@ -41,26 +83,39 @@ func (d *Dispatcher) RunOnce(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	metrics.DispatcherBatchSize.Observe(float64(len(jobs)))
 
 	for _, job := range jobs {
-		if err := d.repo.LockJob(ctx, job.ID, d.nodeID); err != nil {
+		jobCtx := logging.WithAttrs(ctx, slog.String("job_id", job.ID.String()), slog.String("type", job.Type), slog.String("tenant", job.Tenant))
+
+		if err := d.repo.LockJob(ctx, job.ID, d.nodeID, lockTimeout); err != nil {
+			d.logger.DebugContext(jobCtx, "job already locked, skipping", "error", err)
 			continue
 		}
 
-		msg := domain.NewRabbitJobMessageFromJob(job)
-
-		if err := d.rabbit.Publish(msg); err != nil {
-			log.Printf("[DISPATCHER] failed to publish job %s: %v", job.ID, err)
+		// Se marca queued antes de publicar: si el worker consumiera el mensaje
+		// con el job aún pending, la máquina de estados lo descartaría. Un
+		// reintento diferido pasa de failed a queued
+		if err := d.repo.MarkQueued(ctx, job.ID); err != nil {
+			d.logger.WarnContext(jobCtx, "mark queued failed", "error", err)
 			continue
 		}
 
-		if err := d.repo.MarkQueued(ctx, job.ID); err != nil {
-			log.Printf("[DISPATCHER] failed to mark job %s queued: %v", job.ID, err)
+		msg := domain.NewRabbitJobMessageFromJob(job)
+
+		if err := d.rabbit.Publish(msg); err != nil {
+			d.logger.ErrorContext(jobCtx, "publish failed", "error", err)
+			// Vuelve a pending para el próximo tick
+			if err := d.repo.Reschedule(ctx, job.ID, d.clock.Now()); err != nil {
+				d.logger.ErrorContext(jobCtx, "revert to pending failed", "error", err)
+			}
 			continue
 		}
 
-		log.Printf("[DISPATCHER] Job %s queued", job.ID)
+		metrics.JobsDispatched.WithLabelValues(job.Type).Inc()
+		d.logger.InfoContext(jobCtx, "job queued")
 	}
 
+	d.lastSuccess.Store(time.Now().UnixNano())
 	return nil
 }
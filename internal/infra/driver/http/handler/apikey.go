@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"encoding/json"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type APIKeyHandler struct {
+	service ports.IAPIKeyService
+}
+
+func NewAPIKeyHandler(service ports.IAPIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: service}
+}
+
+func RegisterAPIKeyRoutes(r *mux.Router, handler *APIKeyHandler) {
+	r.HandleFunc("/admin/api-keys", handler.Create()).Methods(http.MethodPost)        // POST para crear una key (el token se devuelve solo acá)
+	r.HandleFunc("/admin/api-keys", handler.List()).Methods(http.MethodGet)           // GET para listar las keys, incluidas las revocadas
+	r.HandleFunc("/admin/api-keys/{id}", handler.Revoke()).Methods(http.MethodDelete) // DELETE para revocar una key
+}
+
+// Create implements ports.IAPIKeyHandler.
+func (h *APIKeyHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input domain.APIKeyInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeErrorCode(w, http.StatusBadRequest, "invalid_json", "request body must be a valid JSON object")
+			return
+		}
+
+		key, err := h.service.Create(r.Context(), input)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, key)
+	}
+}
+
+// List implements ports.IAPIKeyHandler.
+func (h *APIKeyHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keys, err := h.service.List(r.Context())
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, keys)
+	}
+}
+
+// Revoke implements ports.IAPIKeyHandler.
+func (h *APIKeyHandler) Revoke() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(mux.Vars(r)["id"])
+		if err != nil {
+			writeErrorCode(w, http.StatusBadRequest, "invalid_id", "api key id must be a valid UUID")
+			return
+		}
+
+		if err := h.service.Revoke(r.Context(), id); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
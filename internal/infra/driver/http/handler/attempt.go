@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type AttemptHandler struct {
+	service ports.IAttemptService
+}
+
+func NewAttemptHandler(service ports.IAttemptService) *AttemptHandler {
+	return &AttemptHandler{service: service}
+}
+
+func RegisterAttemptRoutes(r *mux.Router, handler *AttemptHandler) {
+	r.HandleFunc("/attempts", handler.List()).Methods(http.MethodGet)                // GET para buscar intentos de todos los jobs
+	r.HandleFunc("/jobs/{id}/attempts", handler.ListByJob()).Methods(http.MethodGet) // GET para los intentos de un job
+}
+
+// List implements ports.IAttemptHandler.
+func (h *AttemptHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parsear filtros, orden y paginación del query string
+		q := newQueryParser(r)
+		params := domain.AttemptSearchParams{
+			Tenant:      q.String("tenant"),
+			JobID:       q.UUID("job_id"),
+			JobType:     q.String("job_type"),
+			HTTPStatus:  q.Int("http_status"),
+			WorkerID:    q.String("worker_id"),
+			StartedFrom: q.Time("started_from"),
+			StartedTo:   q.Time("started_to"),
+		}
+		if status := q.String("status"); status != nil {
+			s := domain.AttemptStatus(*status)
+			if s != domain.AttemptStatusSuccess && s != domain.AttemptStatusFailed {
+				q.errs.Add("status", "invalid_value", "status must be one of: success, failed")
+			}
+			params.Status = &s
+		}
+		// Familia de status, ej. http_status_class=5 para 5xx
+		params.HTTPStatusClass = q.Int("http_status_class")
+		params.Q = q.String("q")
+		params.Page = q.Uint("page")
+		params.Limit = q.Uint("limit")
+		params.Cursor = q.String("cursor")
+		params.Sort = q.Sort("sort", "-created_at", domain.AttemptSortFields)
+
+		if err := q.Err(); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		attempts, err := h.service.List(r.Context(), params)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, attempts)
+	}
+}
+
+// ListByJob implements ports.IAttemptHandler.
+func (h *AttemptHandler) ListByJob() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Obtener el ID del job de los parámetros de la URL
+		jobID, err := uuid.Parse(mux.Vars(r)["id"])
+		if err != nil {
+			writeErrorCode(w, http.StatusBadRequest, "invalid_id", "job id must be a valid UUID")
+			return
+		}
+
+		attempts, err := h.service.ListByJob(r.Context(), jobID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, attempts)
+	}
+}
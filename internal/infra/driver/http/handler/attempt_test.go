@@ -0,0 +1,83 @@
+package handler_test
+
+import (
+	"context"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type attemptBody struct {
+	AttemptNumber int     `json:"attempt_number"`
+	Status        string  `json:"status"`
+	DurationMs    *int64  `json:"duration_ms"`
+	ErrorMessage  *string `json:"error_message"`
+	HTTPStatus    *int    `json:"http_status"`
+	WorkerID      *string `json:"worker_id"`
+}
+
+// Cada intento se ve con su detalle, por job y en la búsqueda entre jobs.
+func TestAttempts(t *testing.T) {
+	api := newTestAPI(t)
+	ctx := context.Background()
+
+	jobID := uuid.MustParse(api.createJob(t))
+	other := uuid.MustParse(api.createJob(t))
+
+	start := api.clock.Now()
+	errMsg := "callback failed with status 503"
+	for _, a := range []domain.Attempt{
+		domain.NewAttempt(jobID, 1, domain.AttemptStatusFailed, &errMsg, ptrTo(503), start, start.Add(1500*time.Millisecond), ptrTo("worker-a")),
+		domain.NewAttempt(jobID, 2, domain.AttemptStatusSuccess, nil, ptrTo(200), start.Add(time.Minute), start.Add(time.Minute+200*time.Millisecond), ptrTo("worker-b")),
+		domain.NewAttempt(other, 1, domain.AttemptStatusSuccess, nil, ptrTo(204), start, start.Add(time.Second), ptrTo("worker-a")),
+	} {
+		if err := api.uow.Attempt().Insert(ctx, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("por job", func(t *testing.T) {
+		var attempts []attemptBody
+		decode(t, api.do(t, http.MethodGet, "/jobs/"+jobID.String()+"/attempts", nil), http.StatusOK, &attempts)
+		if len(attempts) != 2 {
+			t.Fatalf("got %d attempts, want 2", len(attempts))
+		}
+
+		first := attempts[0]
+		if first.AttemptNumber != 1 || first.Status != "failed" {
+			t.Fatalf("first attempt = %+v, want attempt 1 failed", first)
+		}
+		if first.HTTPStatus == nil || *first.HTTPStatus != 503 || first.ErrorMessage == nil || *first.ErrorMessage != errMsg {
+			t.Errorf("first attempt = %+v, want the 503 and its error", first)
+		}
+		if first.DurationMs == nil || *first.DurationMs != 1500 || first.WorkerID == nil || *first.WorkerID != "worker-a" {
+			t.Errorf("first attempt = %+v, want 1500ms on worker-a", first)
+		}
+		if attempts[1].AttemptNumber != 2 || attempts[1].Status != "success" {
+			t.Errorf("second attempt = %+v, want attempt 2 success", attempts[1])
+		}
+	})
+
+	t.Run("búsqueda por familia de status", func(t *testing.T) {
+		var page struct {
+			Total uint          `json:"total"`
+			Data  []attemptBody `json:"data"`
+		}
+		decode(t, api.do(t, http.MethodGet, "/attempts?http_status_class=5", nil), http.StatusOK, &page)
+		if page.Total != 1 || len(page.Data) != 1 || *page.Data[0].HTTPStatus != 503 {
+			t.Fatalf("got %+v, want only the 503 attempt", page)
+		}
+
+		decode(t, api.do(t, http.MethodGet, "/attempts?worker_id=worker-a", nil), http.StatusOK, &page)
+		if page.Total != 2 {
+			t.Fatalf("worker-a attempts = %d, want 2", page.Total)
+		}
+	})
+
+	t.Run("job inexistente", func(t *testing.T) {
+		decode(t, api.do(t, http.MethodGet, "/jobs/"+uuid.NewString()+"/attempts", nil), http.StatusNotFound, nil)
+	})
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// publicPaths no requieren API key (probes y scraping de métricas).
+var publicPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// AuthMiddleware authenticates every request with an API key sent as
+// "Authorization: Bearer <key>" or "X-API-Key: <key>" and checks that the key
+// has the scope the route needs (see requiredScope). The key is stored in the
+// request context so services can read it with domain.APIKeyFrom.
+func AuthMiddleware(service ports.IAPIKeyService) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			template := routeTemplate(r)
+			if publicPaths[template] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key, err := service.Authenticate(r.Context(), apiKeyFromRequest(r))
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+
+			scope := requiredScope(r.Method, template)
+			if !key.HasScope(scope) {
+				writeError(w, r, fmt.Errorf("api key %s lacks the %q scope: %w", key.Prefix, scope, domain.ErrForbidden))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(domain.WithAPIKey(r.Context(), key)))
+		})
+	}
+}
+
+// requiredScope mapea cada ruta a un scope: todo /admin y las escrituras de
+// webhooks son admin, las lecturas read y el resto de las escrituras create.
+func requiredScope(method string, template string) domain.APIKeyScope {
+	readOnly := method == http.MethodGet || method == http.MethodHead
+
+	switch {
+	case strings.HasPrefix(template, "/admin/"):
+		return domain.APIKeyScopeAdmin
+	case strings.HasPrefix(template, "/webhooks/") && !readOnly:
+		return domain.APIKeyScopeAdmin
+	case readOnly:
+		return domain.APIKeyScopeRead
+	default:
+		return domain.APIKeyScopeCreate
+	}
+}
+
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return r.URL.Path
+}
+
+func apiKeyFromRequest(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	return strings.TrimSpace(r.Header.Get("X-API-Key"))
+}
@@ -0,0 +1,94 @@
+package handler_test
+
+import (
+	"job_scheduler_go_rabbitmq/internal/core/service"
+	"job_scheduler_go_rabbitmq/internal/infra/driver/http/handler"
+	"net/http"
+	"testing"
+)
+
+const bootstrapKey = "bootstrap-test-key"
+
+// newAuthAPI es testAPI con el middleware de API keys y las rutas de /admin.
+func newAuthAPI(t *testing.T) *testAPI {
+	t.Helper()
+	api := newTestAPI(t)
+	apiKeys := service.NewAPIKeyService(api.uow, bootstrapKey, api.clock, nil)
+	handler.RegisterAPIKeyRoutes(api.router, handler.NewAPIKeyHandler(apiKeys))
+	api.router.Use(handler.AuthMiddleware(apiKeys))
+	return api
+}
+
+func bearer(token string) http.Header {
+	return http.Header{"Authorization": {"Bearer " + token}}
+}
+
+// createKey crea una API key con la key de bootstrap; devuelve su id y su token.
+func (a *testAPI) createKey(t *testing.T, input map[string]any) (id, token string) {
+	t.Helper()
+	var key struct {
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	}
+	decode(t, a.doHeader(t, http.MethodPost, "/admin/api-keys", input, bearer(bootstrapKey)), http.StatusCreated, &key)
+	return key.ID, key.Key
+}
+
+// Cada ruta exige una key válida con el scope que corresponde, y el job
+// registra la key que lo creó.
+func TestAuth(t *testing.T) {
+	api := newAuthAPI(t)
+
+	_, readKey := api.createKey(t, map[string]any{"name": "reader", "scopes": []string{"read"}})
+	createID, createKey := api.createKey(t, map[string]any{
+		"name":              "producer",
+		"scopes":            []string{"read", "create"},
+		"allowed_job_types": []string{"email"},
+	})
+
+	job := func(jobType string) map[string]any {
+		return map[string]any{"type": jobType, "callback_url": "https://example.com/callback", "payload": map[string]any{}}
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       any
+		header     http.Header
+		wantStatus int
+		wantCode   string
+	}{
+		{"sin key", http.MethodGet, "/jobs", nil, nil, http.StatusUnauthorized, "unauthorized"},
+		{"key desconocida", http.MethodGet, "/jobs", nil, bearer("nope"), http.StatusUnauthorized, "unauthorized"},
+		{"read crea un job", http.MethodPost, "/jobs", job("email"), bearer(readKey), http.StatusForbidden, "forbidden"},
+		{"create crea una key", http.MethodPost, "/admin/api-keys", map[string]any{"name": "x", "scopes": []string{"read"}}, bearer(createKey), http.StatusForbidden, "forbidden"},
+		{"tipo no permitido", http.MethodPost, "/jobs", job("sms"), http.Header{"X-Api-Key": {createKey}}, http.StatusForbidden, "forbidden"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body struct {
+				Error struct {
+					Code string `json:"code"`
+				} `json:"error"`
+			}
+			decode(t, api.doHeader(t, tt.method, tt.path, tt.body, tt.header), tt.wantStatus, &body)
+			if body.Error.Code != tt.wantCode {
+				t.Fatalf("error code = %q, want %q", body.Error.Code, tt.wantCode)
+			}
+		})
+	}
+
+	decode(t, api.doHeader(t, http.MethodGet, "/jobs", nil, bearer(readKey)), http.StatusOK, nil)
+
+	var created struct {
+		CreatedByKeyID string `json:"created_by_key_id"`
+	}
+	decode(t, api.doHeader(t, http.MethodPost, "/jobs", job("email"), bearer(createKey)), http.StatusCreated, &created)
+	if created.CreatedByKeyID != createID {
+		t.Fatalf("created_by_key_id = %q, want %q", created.CreatedByKeyID, createID)
+	}
+
+	decode(t, api.doHeader(t, http.MethodDelete, "/admin/api-keys/"+createID, nil, bearer(bootstrapKey)), http.StatusNoContent, nil)
+	decode(t, api.doHeader(t, http.MethodGet, "/jobs", nil, bearer(createKey)), http.StatusUnauthorized, nil)
+}
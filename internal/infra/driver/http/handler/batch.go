@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type BatchHandler struct {
+	service ports.IBatchService
+}
+
+func NewBatchHandler(service ports.IBatchService) *BatchHandler {
+	return &BatchHandler{service: service}
+}
+
+func RegisterBatchRoutes(r *mux.Router, handler *BatchHandler) {
+	r.HandleFunc("/batches/{id}", handler.GetOne()).Methods(http.MethodGet) // GET para el estado y progreso de un lote
+}
+
+// GetOne implements ports.IBatchHandler.
+func (h *BatchHandler) GetOne() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Obtener el ID del lote de los parámetros de la URL
+		vars := mux.Vars(r)
+		batchID, err := uuid.Parse(vars["id"])
+		if err != nil {
+			writeErrorCode(w, http.StatusBadRequest, "invalid_id", "batch id must be a valid UUID")
+			return
+		}
+
+		status, err := h.service.GetStatus(r.Context(), domain.JobBatchSearchParams{ID: &batchID})
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, status)
+	}
+}
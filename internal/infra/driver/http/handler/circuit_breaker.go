@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type CircuitBreakerHandler struct {
+	service ports.ICircuitBreakerService
+}
+
+func NewCircuitBreakerHandler(service ports.ICircuitBreakerService) *CircuitBreakerHandler {
+	return &CircuitBreakerHandler{service: service}
+}
+
+func RegisterCircuitBreakerRoutes(r *mux.Router, handler *CircuitBreakerHandler) {
+	r.HandleFunc("/admin/circuit-breakers", handler.List()).Methods(http.MethodGet) // GET para ver el estado de los breakers por host
+}
+
+// List implements ports.ICircuitBreakerHandler.
+func (h *CircuitBreakerHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Filtros opcionales por query string
+		var params domain.CircuitBreakerSearchParams
+		q := r.URL.Query()
+		if host := q.Get("host"); host != "" {
+			params.Host = &host
+		}
+		if nodeID := q.Get("node_id"); nodeID != "" {
+			params.NodeID = &nodeID
+		}
+		if state := q.Get("state"); state != "" {
+			s := domain.CircuitState(state)
+			params.State = &s
+		}
+
+		breakers, err := h.service.List(r.Context(), params)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if breakers == nil {
+			breakers = []domain.CircuitBreaker{}
+		}
+
+		writeJSON(w, http.StatusOK, breakers)
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"encoding/json"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type ConcurrencyLimitHandler struct {
+	service ports.IConcurrencyLimitService
+}
+
+func NewConcurrencyLimitHandler(service ports.IConcurrencyLimitService) *ConcurrencyLimitHandler {
+	return &ConcurrencyLimitHandler{service: service}
+}
+
+func RegisterConcurrencyLimitRoutes(r *mux.Router, handler *ConcurrencyLimitHandler) {
+	r.HandleFunc("/admin/concurrency-limits", handler.List()).Methods(http.MethodGet)             // GET para ver límites y slots ocupados
+	r.HandleFunc("/admin/concurrency-limits", handler.Put()).Methods(http.MethodPut)              // PUT para crear o actualizar un límite
+	r.HandleFunc("/admin/concurrency-limits/{type}", handler.Delete()).Methods(http.MethodDelete) // DELETE para quitar un límite
+}
+
+// List implements ports.IConcurrencyLimitHandler.
+func (h *ConcurrencyLimitHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var params domain.ConcurrencyLimitSearchParams
+		if jobType := r.URL.Query().Get("type"); jobType != "" {
+			params.JobType = &jobType
+		}
+
+		limits, err := h.service.List(r.Context(), params)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if limits == nil {
+			limits = []domain.ConcurrencyLimit{}
+		}
+
+		writeJSON(w, http.StatusOK, limits)
+	}
+}
+
+// Put implements ports.IConcurrencyLimitHandler.
+func (h *ConcurrencyLimitHandler) Put() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input domain.ConcurrencyLimitInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeErrorCode(w, http.StatusBadRequest, "invalid_json", "request body must be a valid JSON object")
+			return
+		}
+
+		limit, err := h.service.Put(r.Context(), input)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, limit)
+	}
+}
+
+// Delete implements ports.IConcurrencyLimitHandler.
+func (h *ConcurrencyLimitHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.service.Delete(r.Context(), mux.Vars(r)["type"]); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// heartbeatInterval mantiene viva la conexión a través de proxies que cortan conexiones inactivas.
+const heartbeatInterval = 15 * time.Second
+
+type EventStreamHandler struct {
+	service ports.IEventStreamService
+}
+
+func NewEventStreamHandler(service ports.IEventStreamService) *EventStreamHandler {
+	return &EventStreamHandler{service: service}
+}
+
+func RegisterEventStreamRoutes(r *mux.Router, handler *EventStreamHandler) {
+	r.HandleFunc("/events/stream", handler.Stream()).Methods(http.MethodGet)              // GET stream SSE de eventos de todos los jobs
+	r.HandleFunc("/jobs/{id}/events/stream", handler.StreamJob()).Methods(http.MethodGet) // GET stream SSE de eventos de un job
+}
+
+// Stream implements ports.IEventStreamHandler.
+func (h *EventStreamHandler) Stream() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := newQueryParser(r)
+		filter := domain.EventStreamFilter{
+			JobType: q.String("type"),
+		}
+		if status := q.String("status"); status != nil {
+			s := domain.JobStatus(*status)
+			if !s.IsValid() {
+				q.errs.Add("status", "invalid_value", "status is not a valid job status")
+			}
+			filter.JobStatus = &s
+		}
+		for _, t := range q.List("event_type") {
+			filter.EventTypes = append(filter.EventTypes, domain.EventType(t))
+		}
+
+		h.serve(w, r, q, filter)
+	}
+}
+
+// StreamJob implements ports.IEventStreamHandler.
+func (h *EventStreamHandler) StreamJob() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Obtener el ID del job de los parámetros de la URL
+		jobID, err := uuid.Parse(mux.Vars(r)["id"])
+		if err != nil {
+			writeErrorCode(w, http.StatusBadRequest, "invalid_id", "job id must be a valid UUID")
+			return
+		}
+
+		q := newQueryParser(r)
+		filter := domain.EventStreamFilter{JobID: &jobID}
+		for _, t := range q.List("event_type") {
+			filter.EventTypes = append(filter.EventTypes, domain.EventType(t))
+		}
+
+		h.serve(w, r, q, filter)
+	}
+}
+
+func (h *EventStreamHandler) serve(w http.ResponseWriter, r *http.Request, q *queryParser, filter domain.EventStreamFilter) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeErrorCode(w, http.StatusInternalServerError, "streaming_unsupported", "streaming is not supported")
+		return
+	}
+
+	// El navegador reenvía Last-Event-ID al reconectar; last_event_id sirve para clientes sin header
+	lastEventID := strings.TrimSpace(r.Header.Get("Last-Event-ID"))
+	if lastEventID == "" {
+		lastEventID = strings.TrimSpace(r.URL.Query().Get("last_event_id"))
+	}
+	var afterSeq *int64
+	if lastEventID != "" {
+		seq, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || seq < 0 {
+			q.errs.Add("last_event_id", "invalid_number", "last_event_id must be a non-negative integer")
+		} else {
+			afterSeq = &seq
+		}
+	}
+
+	if err := q.Err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	events, err := h.service.Stream(r.Context(), filter, afterSeq)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, ok := <-events:
+			// Canal cerrado: el server se apaga o el cliente quedó atrasado y debe reconectar
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
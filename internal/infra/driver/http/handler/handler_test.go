@@ -0,0 +1,83 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/internal/core/service"
+	"job_scheduler_go_rabbitmq/internal/infra/clock"
+	"job_scheduler_go_rabbitmq/internal/infra/driven/memory"
+	"job_scheduler_go_rabbitmq/internal/infra/driver/http/handler"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// testAPI es el router de la API sobre el store en memoria, sin auth.
+type testAPI struct {
+	router *mux.Router
+	uow    ports.IUnitOfWork
+	clock  *clock.Fake
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	clk := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	uow := memory.NewDataStore(memory.NewStore(clk), nil)
+	router := mux.NewRouter()
+
+	jobService := service.NewJobService(uow, nil, nil, nil, clk, nil, nil)
+	handler.RegisterJobRoutes(router, handler.NewJobHandler(jobService))
+	handler.RegisterAttemptRoutes(router, handler.NewAttemptHandler(service.NewAttemptService(uow)))
+	webhookService := service.NewWebhookService(uow, nil, nil, clk, nil)
+	handler.RegisterWebhookRoutes(router, handler.NewWebhookHandler(webhookService))
+
+	return &testAPI{router: router, uow: uow, clock: clk}
+}
+
+// do ejecuta el request y devuelve la respuesta; body se envía como JSON si no es nil.
+func (a *testAPI) do(t *testing.T, method, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	return a.doHeader(t, method, path, body, nil)
+}
+
+// doHeader es do con headers extra en el request.
+func (a *testAPI) doHeader(t *testing.T, method, path string, body any, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	var raw []byte
+	if body != nil {
+		var err error
+		if raw, err = json.Marshal(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(raw))
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	a.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// decode lee el body de rec en v y falla si el status no es el esperado.
+func decode(t *testing.T, rec *httptest.ResponseRecorder, status int, v any) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, status, rec.Body)
+	}
+	if v == nil {
+		return
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode %s: %v", rec.Body, err)
+	}
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"job_scheduler_go_rabbitmq/internal/core/domain"
 	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/internal/infra/tracing"
 	"net/http"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type JobHandler struct {
@@ -20,7 +23,10 @@ func NewJobHandler(service ports.IJobService) *JobHandler {
 
 func RegisterJobRoutes(r *mux.Router, handler *JobHandler) {
 	r.HandleFunc("/jobs", handler.Create()).Methods(http.MethodPost)                   // POST para crear un job
+	r.HandleFunc("/jobs/batch", handler.CreateBatch()).Methods(http.MethodPost)        // POST para crear jobs en lote (JSON o NDJSON)
+	r.HandleFunc("/jobs", handler.List()).Methods(http.MethodGet)                      // GET para listar y buscar jobs
 	r.HandleFunc("/jobs/{id}", handler.GetOne()).Methods(http.MethodGet)               // GET para obtener un job por ID
+	r.HandleFunc("/jobs/{id}", handler.Update()).Methods(http.MethodPatch)             // PATCH para editar un job pending o failed (If-Match)
 	r.HandleFunc("/jobs/{id}/timeline", handler.GetTimeline()).Methods(http.MethodGet) // GET para el timeline de un job
 }
 
@@ -30,21 +36,75 @@ func (j *JobHandler) Create() http.HandlerFunc {
 		// Parse body para obtener input de creación
 		var input domain.CreateJobInput
 		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-			http.Error(w, "Invalid input", http.StatusBadRequest)
+			writeErrorCode(w, http.StatusBadRequest, "invalid_json", "request body must be a valid JSON object")
 			return
 		}
 
+		// Span del request (continúa el traceparent del cliente si viene); su
+		// contexto se guarda con el job para seguirlo hasta el callback
+		ctx, span := tracing.Tracer().Start(tracing.ExtractHTTP(r), "jobs.create",
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("job.type", input.Type)),
+		)
+		input.TraceContext = tracing.Inject(ctx)
+
 		// Crear job usando el servicio
-		job, err := j.service.Create(r.Context(), input)
+		job, err := j.service.Create(ctx, input)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			tracing.End(span, err)
+			writeError(w, r, err)
 			return
 		}
+		span.SetAttributes(attribute.String("job.id", job.ID.String()))
+		tracing.End(span, nil)
 
 		// Responder con el job creado
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusCreated)
-		_ = json.NewEncoder(w).Encode(job)
+		writeJSON(w, http.StatusCreated, job)
+	}
+}
+
+// List implements ports.IJobHandler.
+func (j *JobHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parsear filtros, orden y paginación del query string
+		q := newQueryParser(r)
+		params := domain.JobSearchParams{
+			Tenant:        q.String("tenant"),
+			Type:          q.String("type"),
+			CreatedFrom:   q.Time("created_from"),
+			CreatedTo:     q.Time("created_to"),
+			ScheduledFrom: q.Time("scheduled_from"),
+			ScheduledTo:   q.Time("scheduled_to"),
+			// Auditoría: jobs encolados por una API key
+			CreatedByKeyID: q.UUID("created_by_key_id"),
+			// Jobs creados por un POST /jobs/batch
+			BatchID: q.UUID("batch_id"),
+		}
+		for _, status := range q.List("status") {
+			if !domain.JobStatus(status).IsValid() {
+				q.errs.Add("status", "invalid_value", "unknown status "+status)
+				continue
+			}
+			params.Statuses = append(params.Statuses, domain.JobStatus(status))
+		}
+		params.Q = q.String("q")
+		params.Page = q.Uint("page")
+		params.Limit = q.Uint("limit")
+		params.Cursor = q.String("cursor")
+		params.Sort = q.Sort("sort", "-created_at", domain.JobSortFields)
+
+		if err := q.Err(); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		jobs, err := j.service.List(r.Context(), params)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, jobs)
 	}
 }
 
@@ -55,20 +115,52 @@ func (j *JobHandler) GetOne() http.HandlerFunc {
 		vars := mux.Vars(r)
 		jobID, err := uuid.Parse(vars["id"])
 		if err != nil {
-			http.Error(w, "Invalid job ID", http.StatusBadRequest)
+			writeErrorCode(w, http.StatusBadRequest, "invalid_id", "job id must be a valid UUID")
 			return
 		}
 
 		// Obtener el job desde el servicio
 		job, err := j.service.GetOne(r.Context(), domain.JobSearchParams{ID: &jobID})
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			writeError(w, r, err)
+			return
+		}
+
+		// Responder con el job encontrado; el ETag se usa en el If-Match del PATCH
+		w.Header().Set("ETag", job.ETag())
+		writeJSON(w, http.StatusOK, job)
+	}
+}
+
+// Update implements ports.IJobHandler.
+func (j *JobHandler) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Obtener el ID del job de los parámetros de la URL
+		vars := mux.Vars(r)
+		jobID, err := uuid.Parse(vars["id"])
+		if err != nil {
+			writeErrorCode(w, http.StatusBadRequest, "invalid_id", "job id must be a valid UUID")
+			return
+		}
+
+		// Los campos no editables (type, tenant...) se rechazan en lugar de ignorarse
+		var input domain.UpdateJobInput
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&input); err != nil {
+			writeErrorCode(w, http.StatusBadRequest, "invalid_json", "request body must be a JSON object with editable job fields: "+err.Error())
+			return
+		}
+		input.IfMatch = r.Header.Get("If-Match")
+
+		job, err := j.service.Update(r.Context(), jobID, input)
+		if err != nil {
+			writeError(w, r, err)
 			return
 		}
 
-		// Responder con el job encontrado
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(job)
+		w.Header().Set("ETag", job.ETag())
+		writeJSON(w, http.StatusOK, job)
 	}
 }
 
@@ -79,19 +171,18 @@ func (j *JobHandler) GetTimeline() http.HandlerFunc {
 		vars := mux.Vars(r)
 		jobID, err := uuid.Parse(vars["id"])
 		if err != nil {
-			http.Error(w, "Invalid job ID", http.StatusBadRequest)
+			writeErrorCode(w, http.StatusBadRequest, "invalid_id", "job id must be a valid UUID")
 			return
 		}
 
 		// Obtener el timeline del job
 		timeline, err := j.service.GetTimeline(r.Context(), jobID)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, err)
 			return
 		}
 
 		// Responder con el timeline
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(timeline)
+		writeJSON(w, http.StatusOK, timeline)
 	}
 }
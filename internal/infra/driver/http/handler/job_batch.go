@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/infra/tracing"
+	"mime"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxNDJSONLine es el tamaño máximo de una línea (un job) en el modo NDJSON.
+const maxNDJSONLine = 1 << 20
+
+// CreateBatch implements ports.IJobHandler.
+// Acepta un array JSON o NDJSON (Content-Type: application/x-ndjson, un job
+// por línea). El body se decodifica en streaming, así el lote nunca está
+// entero en memoria.
+func (j *JobHandler) CreateBatch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := newQueryParser(r)
+		allOrNothing := false
+		if v := q.String("all_or_nothing"); v != nil {
+			switch *v {
+			case "true", "1":
+				allOrNothing = true
+			case "false", "0":
+			default:
+				q.errs.Add("all_or_nothing", "invalid_value", "must be true or false")
+			}
+		}
+		// callback_url recibe el resumen cuando todos los jobs del lote terminan
+		opts := domain.BulkCreateOptions{
+			AllOrNothing: allOrNothing,
+			CallbackURL:  q.String("callback_url"),
+		}
+		if err := q.Err(); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		var items iter.Seq2[domain.CreateJobInput, error]
+		if isNDJSON(r) {
+			items = decodeNDJSON(r.Body)
+		} else {
+			var err error
+			if items, err = decodeJSONArray(r.Body); err != nil {
+				writeErrorCode(w, http.StatusBadRequest, "invalid_json", err.Error())
+				return
+			}
+		}
+
+		ctx, span := tracing.Tracer().Start(tracing.ExtractHTTP(r), "jobs.create_batch",
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.Bool("batch.all_or_nothing", allOrNothing)),
+		)
+		traceContext := tracing.Inject(ctx)
+
+		// Todos los jobs del lote comparten el contexto de traza del request
+		withTrace := func(yield func(domain.CreateJobInput, error) bool) {
+			for input, err := range items {
+				input.TraceContext = traceContext
+				if !yield(input, err) {
+					return
+				}
+			}
+		}
+
+		result, err := j.service.CreateBatch(ctx, withTrace, opts)
+		if err != nil {
+			tracing.End(span, err)
+			writeError(w, r, err)
+			return
+		}
+		span.SetAttributes(
+			attribute.String("batch.id", result.ID.String()),
+			attribute.Int("batch.created", result.Created),
+			attribute.Int("batch.rejected", result.Rejected),
+		)
+		tracing.End(span, nil)
+
+		status := http.StatusCreated
+		switch {
+		case result.RolledBack:
+			status = http.StatusUnprocessableEntity
+		case result.Rejected > 0:
+			status = http.StatusMultiStatus
+		}
+		writeJSON(w, status, result)
+	}
+}
+
+func isNDJSON(r *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == "application/x-ndjson" || mediaType == "application/jsonlines"
+}
+
+// decodeNDJSON devuelve un job por línea no vacía. Una línea inválida se
+// informa como error de su item y el resto del lote sigue.
+func decodeNDJSON(body io.Reader) iter.Seq2[domain.CreateJobInput, error] {
+	return func(yield func(domain.CreateJobInput, error) bool) {
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 64*1024), maxNDJSONLine)
+
+		line := 0
+		for scanner.Scan() {
+			line++
+			raw := bytes.TrimSpace(scanner.Bytes())
+			if len(raw) == 0 {
+				continue
+			}
+
+			var input domain.CreateJobInput
+			if err := json.Unmarshal(raw, &input); err != nil {
+				if !yield(input, fmt.Errorf("line %d: %w", line, domain.ErrInvalidJSON)) {
+					return
+				}
+				continue
+			}
+			if !yield(input, nil) {
+				return
+			}
+		}
+
+		// Línea demasiado larga o body cortado: se informa como un último item inválido
+		if err := scanner.Err(); err != nil {
+			yield(domain.CreateJobInput{}, fmt.Errorf("line %d: %v: %w", line+1, err, domain.ErrInvalidJSON))
+		}
+	}
+}
+
+// decodeJSONArray recorre un array JSON elemento a elemento. Un elemento que no
+// encaja con CreateJobInput se informa como error de su item; un error de
+// sintaxis corta el lote. Devuelve error si el body no empieza por un array.
+func decodeJSONArray(body io.Reader) (iter.Seq2[domain.CreateJobInput, error], error) {
+	dec := json.NewDecoder(body)
+
+	tok, err := dec.Token()
+	if err != nil || tok != json.Delim('[') {
+		return nil, errors.New("request body must be a JSON array of jobs")
+	}
+
+	return func(yield func(domain.CreateJobInput, error) bool) {
+		for dec.More() {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				yield(domain.CreateJobInput{}, fmt.Errorf("malformed array: %w", domain.ErrInvalidJSON))
+				return
+			}
+
+			var input domain.CreateJobInput
+			if err := json.Unmarshal(raw, &input); err != nil {
+				if !yield(input, fmt.Errorf("item is not a valid job object: %w", domain.ErrInvalidJSON)) {
+					return
+				}
+				continue
+			}
+			if !yield(input, nil) {
+				return
+			}
+		}
+	}, nil
+}
@@ -0,0 +1,273 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type jobPage struct {
+	NextCursor *string `json:"nextCursor"`
+	Data       []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+func (a *testAPI) createJob(t *testing.T) string {
+	t.Helper()
+	var job struct {
+		ID string `json:"id"`
+	}
+	decode(t, a.do(t, http.MethodPost, "/jobs", map[string]any{
+		"type":         "email",
+		"callback_url": "https://example.com/callback",
+		"payload":      map[string]any{},
+	}), http.StatusCreated, &job)
+	return job.ID
+}
+
+// runJob lleva el job a running como el dispatcher y el worker.
+func (a *testAPI) runJob(t *testing.T, id string) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+	jobID := uuid.MustParse(id)
+	repo := a.uow.Job()
+	if err := repo.LockJob(ctx, jobID, "dispatcher-test", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.MarkQueued(ctx, jobID); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.MarkRunning(ctx, jobID); err != nil {
+		t.Fatal(err)
+	}
+	return jobID
+}
+
+// Recorrer el listado con nextCursor devuelve todos los jobs una sola vez, y
+// un cursor no se puede reusar con otro orden.
+func TestListJobsCursor(t *testing.T) {
+	api := newTestAPI(t)
+
+	var want []string
+	for range 5 {
+		want = append([]string{api.createJob(t)}, want...) // orden por defecto: -created_at
+		api.clock.Advance(time.Second)
+	}
+
+	var got []string
+	var cursors []string
+	query := url.Values{"limit": {"2"}}
+	for range len(want) {
+		var page jobPage
+		decode(t, api.do(t, http.MethodGet, "/jobs?"+query.Encode(), nil), http.StatusOK, &page)
+		for _, job := range page.Data {
+			got = append(got, job.ID)
+		}
+		if page.NextCursor == nil {
+			break
+		}
+		cursors = append(cursors, *page.NextCursor)
+		query.Set("cursor", *page.NextCursor)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if len(cursors) != 2 {
+		t.Fatalf("got %d cursors, want 2", len(cursors))
+	}
+
+	for _, sort := range []string{"priority", "created_at"} {
+		t.Run("cursor con sort "+sort, func(t *testing.T) {
+			query := url.Values{"limit": {"2"}, "sort": {sort}, "cursor": {cursors[0]}}
+			var body struct {
+				Error struct {
+					Code string `json:"code"`
+				} `json:"error"`
+			}
+			decode(t, api.do(t, http.MethodGet, "/jobs?"+query.Encode(), nil), http.StatusUnprocessableEntity, &body)
+			if body.Error.Code != "invalid" {
+				t.Fatalf("error code = %q, want invalid", body.Error.Code)
+			}
+		})
+	}
+}
+
+// Los errores salen siempre con el mismo envelope: el input inválido con
+// los campos que fallaron y los errores de dominio con su status.
+func TestJobErrors(t *testing.T) {
+	api := newTestAPI(t)
+
+	type errorBody struct {
+		Error struct {
+			Code   string `json:"code"`
+			Fields []struct {
+				Field string `json:"field"`
+				Code  string `json:"code"`
+			} `json:"fields"`
+		} `json:"error"`
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       any
+		wantStatus int
+		wantCode   string
+		wantFields []string
+	}{
+		{
+			name:       "body que no es JSON",
+			method:     http.MethodPost,
+			path:       "/jobs",
+			body:       "not-an-object",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_json",
+		},
+		{
+			name:       "campos inválidos",
+			method:     http.MethodPost,
+			path:       "/jobs",
+			body:       map[string]any{"type": " ", "callback_url": "not-a-url", "max_retries": -1},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "validation_failed",
+			wantFields: []string{"type", "callback_url", "max_retries"},
+		},
+		{
+			name:       "id que no es UUID",
+			method:     http.MethodGet,
+			path:       "/jobs/42",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_id",
+		},
+		{
+			name:       "job inexistente",
+			method:     http.MethodGet,
+			path:       "/jobs/00000000-0000-0000-0000-000000000001",
+			wantStatus: http.StatusNotFound,
+			wantCode:   "not_found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body errorBody
+			decode(t, api.do(t, tt.method, tt.path, tt.body), tt.wantStatus, &body)
+			if body.Error.Code != tt.wantCode {
+				t.Fatalf("error code = %q, want %q", body.Error.Code, tt.wantCode)
+			}
+			var fields []string
+			for _, f := range body.Error.Fields {
+				fields = append(fields, f.Field)
+			}
+			if !slices.Equal(fields, tt.wantFields) {
+				t.Fatalf("fields = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+// El PATCH exige el ETag vigente del job, rechaza los campos no editables y
+// los jobs terminados, y un failed reprogramado vuelve a pending.
+func TestUpdateJob(t *testing.T) {
+	api := newTestAPI(t)
+	ctx := context.Background()
+
+	type errorBody struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	type jobBody struct {
+		Status   string `json:"status"`
+		Priority int    `json:"priority"`
+	}
+
+	id := api.createJob(t)
+	path := "/jobs/" + id
+	get := api.do(t, http.MethodGet, path, nil)
+	decode(t, get, http.StatusOK, nil)
+	etag := get.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("GET /jobs/{id} sin ETag")
+	}
+
+	errorTests := []struct {
+		name       string
+		body       any
+		ifMatch    string
+		wantStatus int
+		wantCode   string
+	}{
+		{"sin If-Match", map[string]any{"priority": 5}, "", http.StatusPreconditionRequired, "precondition_required"},
+		{"ETag viejo", map[string]any{"priority": 5}, `"stale"`, http.StatusPreconditionFailed, "precondition_failed"},
+		{"campo no editable", map[string]any{"type": "sms"}, etag, http.StatusBadRequest, "invalid_json"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.ifMatch != "" {
+				header.Set("If-Match", tt.ifMatch)
+			}
+			var body errorBody
+			decode(t, api.doHeader(t, http.MethodPatch, path, tt.body, header), tt.wantStatus, &body)
+			if body.Error.Code != tt.wantCode {
+				t.Fatalf("error code = %q, want %q", body.Error.Code, tt.wantCode)
+			}
+		})
+	}
+
+	api.clock.Advance(time.Second)
+	patch := api.doHeader(t, http.MethodPatch, path, map[string]any{"priority": 5}, http.Header{"If-Match": {etag}})
+	var updated jobBody
+	decode(t, patch, http.StatusOK, &updated)
+	if updated.Priority != 5 {
+		t.Fatalf("priority = %d, want 5", updated.Priority)
+	}
+	if got := patch.Header().Get("ETag"); got == "" || got == etag {
+		t.Fatalf("ETag después del PATCH = %q, want uno nuevo distinto de %q", got, etag)
+	}
+	decode(t, api.doHeader(t, http.MethodPatch, path, map[string]any{"priority": 6}, http.Header{"If-Match": {etag}}),
+		http.StatusPreconditionFailed, nil)
+
+	t.Run("failed reprogramado", func(t *testing.T) {
+		id := api.createJob(t)
+		jobID := api.runJob(t, id)
+		if err := api.uow.Job().MarkFailed(ctx, jobID, "boom", ptrTo(503)); err != nil {
+			t.Fatal(err)
+		}
+
+		get := api.do(t, http.MethodGet, "/jobs/"+id, nil)
+		decode(t, get, http.StatusOK, nil)
+		body := map[string]any{"scheduled_at": api.clock.Now().Add(time.Hour)}
+		var job jobBody
+		decode(t, api.doHeader(t, http.MethodPatch, "/jobs/"+id, body, http.Header{"If-Match": {get.Header().Get("ETag")}}),
+			http.StatusOK, &job)
+		if job.Status != "pending" {
+			t.Fatalf("status = %q, want pending", job.Status)
+		}
+	})
+
+	t.Run("job completed", func(t *testing.T) {
+		id := api.createJob(t)
+		jobID := api.runJob(t, id)
+		if err := api.uow.Job().MarkCompleted(ctx, jobID); err != nil {
+			t.Fatal(err)
+		}
+
+		get := api.do(t, http.MethodGet, "/jobs/"+id, nil)
+		decode(t, get, http.StatusOK, nil)
+		var body errorBody
+		decode(t, api.doHeader(t, http.MethodPatch, "/jobs/"+id, map[string]any{"priority": 1}, http.Header{"If-Match": {get.Header().Get("ETag")}}),
+			http.StatusConflict, &body)
+		if body.Error.Code != "conflict" {
+			t.Fatalf("error code = %q, want conflict", body.Error.Code)
+		}
+	})
+}
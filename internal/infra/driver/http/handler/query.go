@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// queryParser lee parámetros del query string acumulando los errores de
+// formato como errores de validación por campo.
+type queryParser struct {
+	values url.Values
+	errs   *domain.ValidationError
+}
+
+func newQueryParser(r *http.Request) *queryParser {
+	return &queryParser{values: r.URL.Query(), errs: &domain.ValidationError{}}
+}
+
+func (p *queryParser) String(name string) *string {
+	val := strings.TrimSpace(p.values.Get(name))
+	if val == "" {
+		return nil
+	}
+	return &val
+}
+
+func (p *queryParser) List(name string) []string {
+	var list []string
+	for _, raw := range p.values[name] {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				list = append(list, v)
+			}
+		}
+	}
+	return list
+}
+
+func (p *queryParser) Uint(name string) *uint {
+	raw := p.String(name)
+	if raw == nil {
+		return nil
+	}
+	val, err := strconv.ParseUint(*raw, 10, 32)
+	if err != nil {
+		p.errs.Add(name, "invalid_number", name+" must be a non-negative integer")
+		return nil
+	}
+	u := uint(val)
+	return &u
+}
+
+func (p *queryParser) Int(name string) *int {
+	raw := p.String(name)
+	if raw == nil {
+		return nil
+	}
+	val, err := strconv.Atoi(*raw)
+	if err != nil {
+		p.errs.Add(name, "invalid_number", name+" must be an integer")
+		return nil
+	}
+	return &val
+}
+
+func (p *queryParser) Time(name string) *time.Time {
+	raw := p.String(name)
+	if raw == nil {
+		return nil
+	}
+	val, err := time.Parse(time.RFC3339, *raw)
+	if err != nil {
+		p.errs.Add(name, "invalid_time", name+" must be an RFC3339 timestamp")
+		return nil
+	}
+	return &val
+}
+
+func (p *queryParser) UUID(name string) *uuid.UUID {
+	raw := p.String(name)
+	if raw == nil {
+		return nil
+	}
+	val, err := uuid.Parse(*raw)
+	if err != nil {
+		p.errs.Add(name, "invalid_uuid", name+" must be a valid UUID")
+		return nil
+	}
+	return &val
+}
+
+// Sort valida el campo de orden contra los permitidos.
+func (p *queryParser) Sort(name string, def string, allowed []string) *string {
+	raw := p.String(name)
+	if raw == nil {
+		return &def
+	}
+	field := strings.TrimLeft(*raw, "+-")
+	for _, a := range allowed {
+		if a == field {
+			return raw
+		}
+	}
+	p.errs.Add(name, "invalid_value", name+" must be one of: "+strings.Join(allowed, ", "))
+	return nil
+}
+
+func (p *queryParser) Err() error {
+	return p.errs.Err()
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type RateLimitHandler struct {
+	service ports.IRateLimitService
+}
+
+func NewRateLimitHandler(service ports.IRateLimitService) *RateLimitHandler {
+	return &RateLimitHandler{service: service}
+}
+
+func RegisterRateLimitRoutes(r *mux.Router, handler *RateLimitHandler) {
+	r.HandleFunc("/admin/rate-limits", handler.List()).Methods(http.MethodGet)                    // GET para ver el estado de los limitadores
+	r.HandleFunc("/admin/rate-limits", handler.Put()).Methods(http.MethodPut)                     // PUT para crear o actualizar un límite
+	r.HandleFunc("/admin/rate-limits/{scope}/{key}", handler.Delete()).Methods(http.MethodDelete) // DELETE para quitar un límite
+}
+
+// List implements ports.IRateLimitHandler.
+func (h *RateLimitHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Filtros opcionales por query string
+		var params domain.RateLimitSearchParams
+		if scope := r.URL.Query().Get("scope"); scope != "" {
+			s := domain.RateLimitScope(scope)
+			params.Scope = &s
+		}
+		if key := r.URL.Query().Get("key"); key != "" {
+			params.Key = &key
+		}
+
+		limits, err := h.service.List(r.Context(), params)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if limits == nil {
+			limits = []domain.RateLimit{}
+		}
+
+		writeJSON(w, http.StatusOK, limits)
+	}
+}
+
+// Put implements ports.IRateLimitHandler.
+func (h *RateLimitHandler) Put() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input domain.RateLimitInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeErrorCode(w, http.StatusBadRequest, "invalid_json", "request body must be a valid JSON object")
+			return
+		}
+
+		limit, err := h.service.Put(r.Context(), input)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, limit)
+	}
+}
+
+// Delete implements ports.IRateLimitHandler.
+func (h *RateLimitHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		key := domain.RateLimitKey{
+			Scope: domain.RateLimitScope(vars["scope"]),
+			Key:   vars["key"],
+		}
+
+		if err := h.service.Delete(r.Context(), key); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// ErrorResponse is the envelope returned by every handler on error.
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code    string              `json:"code"`
+	Message string              `json:"message"`
+	Fields  []domain.FieldError `json:"fields,omitempty"`
+}
+
+type loggerKey struct{}
+
+// LoggerMiddleware stores logger in the request context; writeError logs the
+// unexpected errors through it (slog.Default() when the middleware is not used).
+func LoggerMiddleware(logger *slog.Logger) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), loggerKey{}, logger)))
+		})
+	}
+}
+
+func loggerFrom(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeErrorCode(w http.ResponseWriter, status int, code string, message string) {
+	writeJSON(w, status, ErrorResponse{Error: ErrorBody{Code: code, Message: message}})
+}
+
+// writeError traduce los errores de dominio al status HTTP correspondiente.
+// Los errores no reconocidos se loguean y se responden como 500 sin exponer el detalle.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var validationErr *domain.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		writeJSON(w, http.StatusUnprocessableEntity, ErrorResponse{Error: ErrorBody{
+			Code:    "validation_failed",
+			Message: "one or more fields are invalid",
+			Fields:  validationErr.Fields,
+		}})
+	case errors.Is(err, domain.ErrCallbackNotAllowed):
+		writeErrorCode(w, http.StatusUnprocessableEntity, "callback_not_allowed", err.Error())
+	case errors.Is(err, domain.ErrInvalid):
+		writeErrorCode(w, http.StatusUnprocessableEntity, "invalid", err.Error())
+	case errors.Is(err, domain.ErrUnauthorized):
+		w.Header().Set("WWW-Authenticate", `Bearer realm="job-scheduler"`)
+		writeErrorCode(w, http.StatusUnauthorized, "unauthorized", err.Error())
+	case errors.Is(err, domain.ErrForbidden):
+		writeErrorCode(w, http.StatusForbidden, "forbidden", err.Error())
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		writeErrorCode(w, http.StatusTooManyRequests, "quota_exceeded", err.Error())
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		writeErrorCode(w, http.StatusPreconditionFailed, "precondition_failed", err.Error())
+	case errors.Is(err, domain.ErrPreconditionRequired):
+		writeErrorCode(w, http.StatusPreconditionRequired, "precondition_required", err.Error())
+	case errors.Is(err, domain.ErrNotFound):
+		writeErrorCode(w, http.StatusNotFound, "not_found", err.Error())
+	case errors.Is(err, domain.ErrConflict):
+		writeErrorCode(w, http.StatusConflict, "conflict", err.Error())
+	default:
+		loggerFrom(r.Context()).ErrorContext(r.Context(), "internal error", "method", r.Method, "path", r.URL.Path, "error", err)
+		writeErrorCode(w, http.StatusInternalServerError, "internal_error", "internal server error")
+	}
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type StatsHandler struct {
+	service ports.IStatsService
+}
+
+func NewStatsHandler(service ports.IStatsService) *StatsHandler {
+	return &StatsHandler{service: service}
+}
+
+func RegisterStatsRoutes(r *mux.Router, handler *StatsHandler) {
+	r.HandleFunc("/stats", handler.Get()).Methods(http.MethodGet) // GET para ver conteos, throughput y profundidad de la cola
+}
+
+// Get implements ports.IStatsHandler.
+func (h *StatsHandler) Get() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats, err := h.service.Get(r.Context())
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, stats)
+	}
+}
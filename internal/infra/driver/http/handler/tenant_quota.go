@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"encoding/json"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type TenantQuotaHandler struct {
+	service ports.ITenantQuotaService
+}
+
+func NewTenantQuotaHandler(service ports.ITenantQuotaService) *TenantQuotaHandler {
+	return &TenantQuotaHandler{service: service}
+}
+
+func RegisterTenantQuotaRoutes(r *mux.Router, handler *TenantQuotaHandler) {
+	r.HandleFunc("/admin/tenant-quotas", handler.List()).Methods(http.MethodGet)               // GET para ver cuotas y jobs pendientes por tenant
+	r.HandleFunc("/admin/tenant-quotas", handler.Put()).Methods(http.MethodPut)                // PUT para crear o actualizar una cuota
+	r.HandleFunc("/admin/tenant-quotas/{tenant}", handler.Delete()).Methods(http.MethodDelete) // DELETE para quitar una cuota
+}
+
+// List implements ports.ITenantQuotaHandler.
+func (h *TenantQuotaHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var params domain.TenantQuotaSearchParams
+		if tenant := r.URL.Query().Get("tenant"); tenant != "" {
+			params.Tenant = &tenant
+		}
+
+		quotas, err := h.service.List(r.Context(), params)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if quotas == nil {
+			quotas = []domain.TenantQuota{}
+		}
+
+		writeJSON(w, http.StatusOK, quotas)
+	}
+}
+
+// Put implements ports.ITenantQuotaHandler.
+func (h *TenantQuotaHandler) Put() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input domain.TenantQuotaInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeErrorCode(w, http.StatusBadRequest, "invalid_json", "request body must be a valid JSON object")
+			return
+		}
+
+		quota, err := h.service.Put(r.Context(), input)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, quota)
+	}
+}
+
+// Delete implements ports.ITenantQuotaHandler.
+func (h *TenantQuotaHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.service.Delete(r.Context(), mux.Vars(r)["tenant"]); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
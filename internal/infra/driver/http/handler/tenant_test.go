@@ -0,0 +1,61 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+// Una key de tenant solo ve los jobs de su tenant: los ajenos no aparecen en
+// el listado y por id dan 404.
+func TestTenantIsolation(t *testing.T) {
+	api := newAuthAPI(t)
+
+	scopes := []string{"read", "create"}
+	_, acme := api.createKey(t, map[string]any{"name": "acme", "tenant": "acme", "scopes": scopes})
+	_, globex := api.createKey(t, map[string]any{"name": "globex", "tenant": "globex", "scopes": scopes})
+
+	var job struct {
+		ID     string `json:"id"`
+		Tenant string `json:"tenant"`
+	}
+	decode(t, api.doHeader(t, http.MethodPost, "/jobs", map[string]any{
+		"type":         "email",
+		"callback_url": "https://example.com/callback",
+		"payload":      map[string]any{},
+	}, bearer(acme)), http.StatusCreated, &job)
+	if job.Tenant != "acme" {
+		t.Fatalf("tenant = %q, want acme", job.Tenant)
+	}
+
+	decode(t, api.doHeader(t, http.MethodPost, "/jobs", map[string]any{
+		"type":         "email",
+		"callback_url": "https://example.com/callback",
+		"payload":      map[string]any{},
+		"tenant":       "acme",
+	}, bearer(globex)), http.StatusForbidden, nil)
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"mismo tenant", acme, 1},
+		{"otro tenant", globex, 0},
+		{"bootstrap", bootstrapKey, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var page jobPage
+			decode(t, api.doHeader(t, http.MethodGet, "/jobs", nil, bearer(tt.token)), http.StatusOK, &page)
+			if len(page.Data) != tt.want {
+				t.Fatalf("listed %d jobs, want %d", len(page.Data), tt.want)
+			}
+
+			status := http.StatusOK
+			if tt.want == 0 {
+				status = http.StatusNotFound
+			}
+			decode(t, api.doHeader(t, http.MethodGet, "/jobs/"+job.ID, nil, bearer(tt.token)), status, nil)
+		})
+	}
+}
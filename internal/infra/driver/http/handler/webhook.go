@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"encoding/json"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type WebhookHandler struct {
+	service ports.IWebhookService
+}
+
+func NewWebhookHandler(service ports.IWebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+func RegisterWebhookRoutes(r *mux.Router, handler *WebhookHandler) {
+	r.HandleFunc("/webhooks/subscriptions", handler.CreateSubscription()).Methods(http.MethodPost)        // POST para crear una suscripción
+	r.HandleFunc("/webhooks/subscriptions", handler.ListSubscriptions()).Methods(http.MethodGet)          // GET para listar las suscripciones activas
+	r.HandleFunc("/webhooks/subscriptions/{id}", handler.DeleteSubscription()).Methods(http.MethodDelete) // DELETE para desactivar una suscripción
+	r.HandleFunc("/webhooks/deliveries", handler.ListDeliveries()).Methods(http.MethodGet)                // GET para consultar el log de entregas
+}
+
+// CreateSubscription implements ports.IWebhookHandler.
+func (h *WebhookHandler) CreateSubscription() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input domain.WebhookSubscriptionInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeErrorCode(w, http.StatusBadRequest, "invalid_json", "request body must be a valid JSON object")
+			return
+		}
+
+		subscription, err := h.service.CreateSubscription(r.Context(), input)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, subscription)
+	}
+}
+
+// ListSubscriptions implements ports.IWebhookHandler.
+func (h *WebhookHandler) ListSubscriptions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := newQueryParser(r)
+		params := domain.WebhookSubscriptionSearchParams{
+			JobID:   q.UUID("job_id"),
+			JobType: q.String("job_type"),
+		}
+		if err := q.Err(); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		subscriptions, err := h.service.ListSubscriptions(r.Context(), params)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, subscriptions)
+	}
+}
+
+// DeleteSubscription implements ports.IWebhookHandler.
+func (h *WebhookHandler) DeleteSubscription() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(mux.Vars(r)["id"])
+		if err != nil {
+			writeErrorCode(w, http.StatusBadRequest, "invalid_id", "subscription id must be a valid UUID")
+			return
+		}
+
+		if err := h.service.DeleteSubscription(r.Context(), id); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ListDeliveries implements ports.IWebhookHandler.
+func (h *WebhookHandler) ListDeliveries() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parsear filtros, orden y paginación del query string
+		q := newQueryParser(r)
+		params := domain.WebhookDeliverySearchParams{
+			SubscriptionID: q.UUID("subscription_id"),
+			JobID:          q.UUID("job_id"),
+		}
+		if eventType := q.String("event_type"); eventType != nil {
+			t := domain.EventType(*eventType)
+			if !t.IsValid() {
+				q.errs.Add("event_type", "invalid_value", "event_type is not a valid event type")
+			}
+			params.EventType = &t
+		}
+		if status := q.String("status"); status != nil {
+			s := domain.WebhookDeliveryStatus(*status)
+			if !s.IsValid() {
+				q.errs.Add("status", "invalid_value", "status must be one of: pending, delivered, dead")
+			}
+			params.Status = &s
+		}
+		params.Page = q.Uint("page")
+		params.Limit = q.Uint("limit")
+		params.Cursor = q.String("cursor")
+		params.Sort = q.Sort("sort", "-created_at", domain.WebhookDeliverySortFields)
+
+		if err := q.Err(); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		deliveries, err := h.service.ListDeliveries(r.Context(), params)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, deliveries)
+	}
+}
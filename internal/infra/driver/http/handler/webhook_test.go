@@ -0,0 +1,57 @@
+package handler_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// El secreto de firma se ve una sola vez, en la respuesta que crea la
+// suscripción; ni el listado ni el job lo exponen después.
+func TestWebhookSecretOnlyOnCreate(t *testing.T) {
+	api := newTestAPI(t)
+
+	var created map[string]any
+	decode(t, api.do(t, http.MethodPost, "/webhooks/subscriptions", map[string]any{
+		"url": "https://hooks.example.com/jobs",
+	}), http.StatusCreated, &created)
+	secret, _ := created["secret"].(string)
+	if secret == "" {
+		t.Fatalf("create response has no secret: %v", created)
+	}
+
+	var job map[string]any
+	decode(t, api.do(t, http.MethodPost, "/jobs", map[string]any{
+		"type":         "email",
+		"callback_url": "https://example.com/callback",
+		"payload":      map[string]any{},
+		"notify_url":   "https://hooks.example.com/job",
+	}), http.StatusCreated, &job)
+	notifySecret, _ := job["notify_secret"].(string)
+	if notifySecret == "" {
+		t.Fatalf("job create response has no notify_secret: %v", job)
+	}
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"listado", "/webhooks/subscriptions"},
+		{"listado por job", "/webhooks/subscriptions?job_id=" + job["id"].(string)},
+		{"job", "/jobs/" + job["id"].(string)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := api.do(t, http.MethodGet, tt.path, nil)
+			decode(t, rec, http.StatusOK, nil)
+
+			body := rec.Body.String()
+			if strings.Contains(body, secret) || strings.Contains(body, notifySecret) {
+				t.Fatalf("GET %s exposes a signing secret: %s", tt.path, body)
+			}
+			if strings.Contains(body, `"secret"`) {
+				t.Fatalf("GET %s has a secret field: %s", tt.path, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"sync"
+)
+
+// memoryBaseQueue es el nombre de la cola base, igual que en RabbitMQ.
+const memoryBaseQueue = "jobs_queue"
+
+// MemoryBroker holds the queues shared by every MemoryClient it creates, the
+// way a RabbitMQ server outlives the connections of its clients: messages a
+// client did not consume stay queued for the next one.
+type MemoryBroker struct {
+	mu      sync.Mutex
+	queues  map[string][]domain.RabbitJobMessage
+	clients map[*MemoryClient]struct{}
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		queues:  map[string][]domain.RabbitJobMessage{},
+		clients: map[*MemoryClient]struct{}{},
+	}
+}
+
+// Client abre un cliente sobre las colas del broker.
+func (b *MemoryBroker) Client() *MemoryClient {
+	c := &MemoryClient{
+		broker: b,
+		notify: make(chan struct{}, 1),
+		closed: make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	b.clients[c] = struct{}{}
+	b.mu.Unlock()
+
+	return c
+}
+
+// MemoryClient is an in-process replacement for RabbitClient used by the
+// single-process dev mode. Queues follow the naming of RabbitClient and the
+// consumer takes one message per consumed queue in turn. Messages are
+// acknowledged on delivery, like the autoAck consumer of RabbitClient, and
+// are lost when the process exits.
+type MemoryClient struct {
+	broker *MemoryBroker
+
+	// consuming y next están protegidos por broker.mu
+	consuming []string // colas con consumer, en orden de alta
+	next      int      // próxima cola del round robin
+
+	// notify avisa al loop de Consume que hay mensajes nuevos
+	notify    chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+var _ ports.IRabbitMQClient = (*MemoryClient)(nil)
+var _ ports.IQueueInspector = (*MemoryClient)(nil)
+
+// NewMemoryClient crea un cliente con un broker propio.
+func NewMemoryClient() *MemoryClient {
+	return NewMemoryBroker().Client()
+}
+
+// Close implements ports.RabbitMQClient.
+// Los mensajes que el cliente no llegó a consumir quedan en el broker.
+func (m *MemoryClient) Close() error {
+	m.closeOnce.Do(func() {
+		m.broker.mu.Lock()
+		delete(m.broker.clients, m)
+		m.consuming = nil
+		m.broker.mu.Unlock()
+
+		close(m.closed)
+	})
+	return nil
+}
+
+// Consume implements ports.RabbitMQClient.
+// Atiende la cola base y las que se agreguen con ConsumeTenant hasta Close.
+func (m *MemoryClient) Consume(handler func(domain.RabbitJobMessage)) error {
+	m.broker.mu.Lock()
+	m.consumeLocked(memoryBaseQueue)
+	m.broker.mu.Unlock()
+
+	for {
+		// Close tiene prioridad sobre los mensajes pendientes
+		select {
+		case <-m.closed:
+			return nil
+		default:
+		}
+
+		if msg, ok := m.take(); ok {
+			handler(msg)
+			continue
+		}
+
+		select {
+		case <-m.notify:
+		case <-m.closed:
+			return nil
+		}
+	}
+}
+
+// ConsumeTenant implements ports.RabbitMQClient.
+func (m *MemoryClient) ConsumeTenant(tenant string) error {
+	m.broker.mu.Lock()
+	m.consumeLocked(memoryQueueFor(tenant))
+	m.broker.mu.Unlock()
+
+	m.signal()
+	return nil
+}
+
+// Publish implements ports.RabbitMQClient.
+func (m *MemoryClient) Publish(msg domain.RabbitJobMessage) error {
+	select {
+	case <-m.closed:
+		return errors.New("memory queue closed")
+	default:
+	}
+
+	name := memoryQueueFor(msg.Tenant)
+	m.broker.mu.Lock()
+	m.broker.queues[name] = append(m.broker.queues[name], msg)
+	clients := make([]*MemoryClient, 0, len(m.broker.clients))
+	for c := range m.broker.clients {
+		clients = append(clients, c)
+	}
+	m.broker.mu.Unlock()
+
+	for _, c := range clients {
+		c.signal()
+	}
+	return nil
+}
+
+// QueueDepth implements ports.IQueueInspector.
+// Con una key de tenant se informa la cola de ese tenant.
+func (m *MemoryClient) QueueDepth(ctx context.Context) (*domain.QueueDepth, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	name := memoryBaseQueue
+	if tenant := domain.TenantFrom(ctx); tenant != nil {
+		name = memoryQueueFor(*tenant)
+	}
+
+	m.broker.mu.Lock()
+	defer m.broker.mu.Unlock()
+
+	depth := &domain.QueueDepth{Name: name, Messages: len(m.broker.queues[name])}
+	for c := range m.broker.clients {
+		for _, q := range c.consuming {
+			if q == name {
+				depth.Consumers++
+			}
+		}
+	}
+	return depth, nil
+}
+
+// Check informa si el cliente sigue abierto; lo usan los probes de /readyz.
+func (m *MemoryClient) Check(ctx context.Context) error {
+	select {
+	case <-m.closed:
+		return errors.New("memory queue closed")
+	default:
+	}
+	return ctx.Err()
+}
+
+// take saca el próximo mensaje tomando una cola consumida por turno.
+func (m *MemoryClient) take() (domain.RabbitJobMessage, bool) {
+	m.broker.mu.Lock()
+	defer m.broker.mu.Unlock()
+
+	for i := 0; i < len(m.consuming); i++ {
+		name := m.consuming[(m.next+i)%len(m.consuming)]
+		if msgs := m.broker.queues[name]; len(msgs) > 0 {
+			m.broker.queues[name] = msgs[1:]
+			m.next = (m.next + i + 1) % len(m.consuming)
+			return msgs[0], true
+		}
+	}
+	return domain.RabbitJobMessage{}, false
+}
+
+// consumeLocked agrega la cola al round robin si todavía no está. Requiere broker.mu.
+func (m *MemoryClient) consumeLocked(name string) {
+	for _, q := range m.consuming {
+		if q == name {
+			return
+		}
+	}
+	m.consuming = append(m.consuming, name)
+}
+
+// signal despierta a Consume sin bloquear si ya hay un aviso pendiente.
+func (m *MemoryClient) signal() {
+	select {
+	case m.notify <- struct{}{}:
+	default:
+	}
+}
+
+// memoryQueueFor devuelve la cola del tenant con la misma convención que RabbitClient.
+func memoryQueueFor(tenant string) string {
+	if tenant == "" || tenant == domain.DefaultTenant {
+		return memoryBaseQueue
+	}
+	return memoryBaseQueue + "." + tenant
+}
@@ -0,0 +1,15 @@
+package mq_test
+
+import (
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+	"job_scheduler_go_rabbitmq/internal/core/ports/portstest"
+	"job_scheduler_go_rabbitmq/internal/infra/driver/mq"
+	"testing"
+)
+
+func TestMemoryClient(t *testing.T) {
+	portstest.TestRabbitMQClient(t, func(t *testing.T) func() ports.IRabbitMQClient {
+		broker := mq.NewMemoryBroker()
+		return func() ports.IRabbitMQClient { return broker.Client() }
+	})
+}
@@ -1,22 +1,44 @@
 package mq
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"job_scheduler_go_rabbitmq/internal/core/domain"
 	"job_scheduler_go_rabbitmq/internal/core/ports"
-	"log"
-	"os"
+	"job_scheduler_go_rabbitmq/internal/infra/metrics"
+	"job_scheduler_go_rabbitmq/internal/infra/tracing"
+	"log/slog"
+	"sync"
 
 	"github.com/streadway/amqp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// RabbitClient publishes each job to the queue of its tenant: the default
+// tenant uses the base queue and every other tenant "<base>.<tenant>". The
+// consumer merges every tenant queue into a single handler loop taking one
+// message per queue in turn, so a tenant with a large backlog cannot starve
+// the others.
 type RabbitClient struct {
 	conn    *amqp.Connection
 	channel *amqp.Channel
 	queue   amqp.Queue
+	logger  *slog.Logger
+	// closed recibe el cierre del canal de publicación/consumo (ver Check)
+	closed chan *amqp.Error
+
+	mu        sync.Mutex
+	declared  map[string]bool // colas de tenant ya declaradas
+	consuming map[string]bool // colas con consumer activo
+	// deliveries junta los mensajes de todas las colas; done se cierra al cerrarse el canal
+	deliveries chan amqp.Delivery
+	done       chan struct{}
 }
 
 var _ ports.IRabbitMQClient = (*RabbitClient)(nil)
+var _ ports.IQueueInspector = (*RabbitClient)(nil)
 
 // Close implements ports.RabbitMQClient.
 func (r *RabbitClient) Close() error {
@@ -30,6 +52,8 @@ func (r *RabbitClient) Close() error {
 }
 
 // Consume implements ports.RabbitMQClient.
+// Atiende la cola base y las que se agreguen con ConsumeTenant hasta que se
+// cierre el canal.
 func (r *RabbitClient) Consume(handler func(domain.RabbitJobMessage)) error {
 	msgs, err := r.channel.Consume(
 		r.queue.Name,
@@ -44,14 +68,104 @@ func (r *RabbitClient) Consume(handler func(domain.RabbitJobMessage)) error {
 		return err
 	}
 
+	r.mu.Lock()
+	r.consuming[r.queue.Name] = true
+	r.mu.Unlock()
+
+	// Todas las colas comparten el canal: cuando se cierra la base se cierran todas
+	go func() {
+		r.forward(msgs)
+		close(r.done)
+	}()
+
+	for {
+		select {
+		case msg := <-r.deliveries:
+			var job domain.RabbitJobMessage
+			if err := json.Unmarshal(msg.Body, &job); err != nil {
+				continue
+			}
+			job.TraceContext = traceContextFromHeaders(msg.Headers)
+			handler(job)
+		case <-r.done:
+			return nil
+		}
+	}
+}
+
+// ConsumeTenant implements ports.RabbitMQClient.
+func (r *RabbitClient) ConsumeTenant(tenant string) error {
+	name := r.queueFor(tenant)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.consuming[name] {
+		return nil
+	}
+	if err := r.declareLocked(name); err != nil {
+		return err
+	}
+
+	msgs, err := r.channel.Consume(
+		name,
+		"",
+		true,
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	r.consuming[name] = true
+	go r.forward(msgs)
+
+	r.logger.InfoContext(context.Background(), "consuming", "queue", name)
+	return nil
+}
+
+// forward pasa los mensajes de una cola al loop de Consume. Cada cola tiene
+// a lo sumo un mensaje esperando, y como los envíos bloqueados en un canal se
+// atienden en orden de llegada, el loop toma un mensaje de cada cola por turno.
+func (r *RabbitClient) forward(msgs <-chan amqp.Delivery) {
 	for msg := range msgs {
-		var job domain.RabbitJobMessage
-		if err := json.Unmarshal(msg.Body, &job); err != nil {
-			continue
+		select {
+		case r.deliveries <- msg:
+		case <-r.done:
+			return
 		}
-		handler(job)
+	}
+}
+
+// queueFor devuelve la cola del tenant.
+func (r *RabbitClient) queueFor(tenant string) string {
+	if tenant == "" || tenant == domain.DefaultTenant {
+		return r.queue.Name
+	}
+	return r.queue.Name + "." + tenant
+}
+
+// declareLocked declara la cola si todavía no se hizo. Requiere r.mu.
+func (r *RabbitClient) declareLocked(name string) error {
+	if name == r.queue.Name || r.declared[name] {
+		return nil
 	}
 
+	if _, err := r.channel.QueueDeclare(
+		name,
+		true,
+		false,
+		false,
+		false,
+		nil,
+	); err != nil {
+		return err
+	}
+
+	r.declared[name] = true
 	return nil
 }
 
@@ -62,20 +176,125 @@ func (r *RabbitClient) Publish(msg domain.RabbitJobMessage) error {
 		return err
 	}
 
-	return r.channel.Publish(
+	queue := r.queueFor(msg.Tenant)
+	r.mu.Lock()
+	err = r.declareLocked(queue)
+	r.mu.Unlock()
+	if err != nil {
+		metrics.PublishErrors.Inc()
+		return err
+	}
+
+	// El span de publish cuelga de la traza del job y viaja en los headers
+	ctx, span := tracing.Tracer().Start(tracing.Extract(context.Background(), msg.TraceContext), "rabbitmq.publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.destination.name", queue),
+			attribute.String("job.id", msg.JobID.String()),
+		),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	err = r.channel.Publish(
 		"",
-		r.queue.Name,
+		queue,
 		false,
 		false,
 		amqp.Publishing{
 			ContentType: "application/json",
+			Headers:     traceContextHeaders(tracing.Inject(ctx)),
 			Body:        body,
 		},
 	)
+	if err != nil {
+		metrics.PublishErrors.Inc()
+	}
+	return err
 }
 
-func NewRabbitClient() (*RabbitClient, error) {
-	rabbitURL := os.Getenv("RABBITMQ_URL")
+// QueueDepth implements ports.IQueueInspector.
+// Usa un declare pasivo en un canal propio: si falla, RabbitMQ cierra ese
+// canal y no el que se usa para publicar. Con una key de tenant se informa la
+// cola de ese tenant.
+func (r *RabbitClient) QueueDepth(ctx context.Context) (*domain.QueueDepth, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	name := r.queue.Name
+	if tenant := domain.TenantFrom(ctx); tenant != nil {
+		name = r.queueFor(*tenant)
+	}
+
+	ch, err := r.conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+	defer ch.Close()
+
+	q, err := ch.QueueDeclarePassive(
+		name,
+		true,
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.QueueDepth{
+		Name:      q.Name,
+		Messages:  q.Messages,
+		Consumers: q.Consumers,
+	}, nil
+}
+
+// Check informa si la conexión y el canal siguen abiertos; lo usan los
+// probes de /readyz.
+func (r *RabbitClient) Check(ctx context.Context) error {
+	if r.conn == nil || r.conn.IsClosed() {
+		return errors.New("rabbitmq connection closed")
+	}
+	select {
+	case <-r.closed:
+		return errors.New("rabbitmq channel closed")
+	default:
+	}
+	return ctx.Err()
+}
+
+func traceContextHeaders(carrier map[string]string) amqp.Table {
+	if len(carrier) == 0 {
+		return nil
+	}
+	headers := amqp.Table{}
+	for k, v := range carrier {
+		headers[k] = v
+	}
+	return headers
+}
+
+func traceContextFromHeaders(headers amqp.Table) map[string]string {
+	carrier := map[string]string{}
+	for k, v := range headers {
+		if s, ok := v.(string); ok {
+			carrier[k] = s
+		}
+	}
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// NewRabbitClient se conecta a rabbitURL y declara queue, la cola base.
+// logger puede ser nil para usar slog.Default().
+func NewRabbitClient(rabbitURL, queue string, logger *slog.Logger) (*RabbitClient, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
 
 	conn, err := amqp.Dial(rabbitURL)
 	if err != nil {
@@ -88,7 +307,7 @@ func NewRabbitClient() (*RabbitClient, error) {
 	}
 
 	q, err := ch.QueueDeclare(
-		"jobs_queue",
+		queue,
 		true,
 		false,
 		false,
@@ -99,11 +318,21 @@ func NewRabbitClient() (*RabbitClient, error) {
 		return nil, err
 	}
 
-	log.Println("[RabbitMQ] Connected")
+	logger.InfoContext(context.Background(), "connected", "queue", queue)
+
+	// Con buffer para no bloquear a la librería cuando cierra el canal
+	closed := ch.NotifyClose(make(chan *amqp.Error, 1))
 
 	return &RabbitClient{
 		conn:    conn,
 		channel: ch,
 		queue:   q,
+		logger:  logger,
+		closed:  closed,
+
+		declared:   map[string]bool{},
+		consuming:  map[string]bool{},
+		deliveries: make(chan amqp.Delivery),
+		done:       make(chan struct{}),
 	}, nil
 }
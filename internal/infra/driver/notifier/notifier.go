@@ -0,0 +1,45 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+)
+
+// Notifier delivers pending webhooks in batches.
+type Notifier struct {
+	service   ports.IWebhookDeliveryService
+	batchSize int
+	logger    *slog.Logger
+}
+
+// New creates a new Notifier instance. logger may be nil to use slog.Default().
+func New(service ports.IWebhookDeliveryService, batchSize int, logger *slog.Logger) *Notifier {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Notifier{
+		service:   service,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+// RunOnce sends the deliveries that are due. It returns true when a full batch
+// was processed, meaning more deliveries may be waiting.
+func (n *Notifier) RunOnce(ctx context.Context) (bool, error) {
+	sent, err := n.service.DeliverDue(ctx, n.batchSize)
+	if err != nil {
+		return false, err
+	}
+
+	if sent > 0 {
+		n.logger.InfoContext(ctx, "webhook deliveries processed", "count", sent)
+	}
+
+	return sent == n.batchSize, nil
+}
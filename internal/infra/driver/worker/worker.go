@@ -4,25 +4,93 @@ import (
 	"context"
 	"job_scheduler_go_rabbitmq/internal/core/domain"
 	"job_scheduler_go_rabbitmq/internal/core/ports"
-	"log"
+	"job_scheduler_go_rabbitmq/internal/infra/logging"
+	"job_scheduler_go_rabbitmq/internal/infra/tracing"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Worker struct {
 	service ports.IJobExecutionService
 	rabbit  ports.IRabbitMQClient
+	nodeID  string
+	// tenantPollInterval es cada cuánto el worker busca tenants nuevos con jobs encolados
+	tenantPollInterval time.Duration
+	logger             *slog.Logger
 }
 
-func New(service ports.IJobExecutionService, rabbit ports.IRabbitMQClient) *Worker {
+func New(service ports.IJobExecutionService, rabbit ports.IRabbitMQClient, nodeID string, tenantPollInterval time.Duration, logger *slog.Logger) *Worker {
 	return &Worker{
-		service: service,
-		rabbit:  rabbit,
+		service:            service,
+		rabbit:             rabbit,
+		nodeID:             nodeID,
+		tenantPollInterval: tenantPollInterval,
+		logger:             logger,
 	}
 }
 
 func (w *Worker) Start(ctx context.Context) error {
+	// La identidad del worker queda registrada en cada intento
+	ctx = domain.WithWorkerID(ctx, w.nodeID)
+	ctx = logging.WithAttrs(ctx, slog.String("node_id", w.nodeID))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go w.watchTenants(ctx)
+
 	return w.rabbit.Consume(func(msg domain.RabbitJobMessage) {
-		if err := w.service.ProcessJobMessage(ctx, msg); err != nil {
-			log.Printf("[WORKER] failed processing job %s: %v", msg.JobID, err)
+		// Se retoma la traza iniciada al crear el job
+		msgCtx, span := tracing.Tracer().Start(tracing.Extract(ctx, msg.TraceContext), "job.process",
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(
+				attribute.String("job.id", msg.JobID.String()),
+				attribute.String("job.type", msg.Type),
+				attribute.Int("job.attempt", msg.Attempt),
+				attribute.String("worker.id", w.nodeID),
+			),
+		)
+
+		// Todo lo que se loguee procesando el mensaje lleva los datos del job
+		msgCtx = logging.WithAttrs(msgCtx,
+			slog.String("job_id", msg.JobID.String()),
+			slog.String("tenant", msg.Tenant),
+			slog.String("type", msg.Type),
+			slog.Int("attempt", msg.Attempt),
+		)
+
+		w.logger.DebugContext(msgCtx, "processing job")
+		err := w.service.ProcessJobMessage(msgCtx, msg)
+		if err != nil {
+			w.logger.ErrorContext(msgCtx, "failed processing job", "error", err)
 		}
+		tracing.End(span, err)
 	})
 }
+
+// watchTenants suma al consumer la cola de cada tenant que tenga mensajes
+// pendientes (jobs encolados o reintentos publicados).
+func (w *Worker) watchTenants(ctx context.Context) {
+	ticker := time.NewTicker(w.tenantPollInterval)
+	defer ticker.Stop()
+
+	for {
+		tenants, err := w.service.QueuedTenants(ctx)
+		if err != nil {
+			w.logger.WarnContext(ctx, "failed to list queued tenants", "error", err)
+		}
+		for _, tenant := range tenants {
+			if err := w.rabbit.ConsumeTenant(tenant); err != nil {
+				w.logger.WarnContext(ctx, "failed to consume tenant queue", "tenant", tenant, "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
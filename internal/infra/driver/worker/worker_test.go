@@ -0,0 +1,97 @@
+package worker_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/service"
+	"job_scheduler_go_rabbitmq/internal/infra/clock"
+	"job_scheduler_go_rabbitmq/internal/infra/driven/memory"
+	"job_scheduler_go_rabbitmq/internal/infra/driver/mq"
+	"job_scheduler_go_rabbitmq/internal/infra/driver/worker"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failOnce falla el primer callback y acepta los siguientes.
+type failOnce struct {
+	calls atomic.Int32
+}
+
+func (f *failOnce) Execute(ctx context.Context, job *domain.Job) domain.ExecutionResult {
+	if f.calls.Add(1) == 1 {
+		return domain.ExecutionResult{HTTPStatus: 503, Error: errors.New("callback failed with status 503")}
+	}
+	return domain.ExecutionResult{HTTPStatus: 200}
+}
+
+// Un tenant cuya única cola pendiente es un reintento publicado también se
+// consume: el worker lo descubre con QueuedTenants aunque el job esté failed.
+func TestWorkerConsumesTenantRetries(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	uow := memory.NewDataStore(memory.NewStore(clk), nil)
+	client := mq.NewMemoryBroker().Client()
+	exec := &failOnce{}
+	jobs := service.NewJobService(uow, exec, client, nil, clk, nil, nil)
+
+	created, err := jobs.Create(ctx, domain.CreateJobInput{
+		Tenant:      "initech",
+		Type:        "email",
+		CallbackURL: "https://api.example.com/callback",
+		Payload:     json.RawMessage(`{}`),
+		MaxRetries:  3,
+	})
+	if err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	if err := uow.Job().LockJob(ctx, created.ID, "dispatcher-test", time.Minute); err != nil {
+		t.Fatalf("lock job: %v", err)
+	}
+	if err := uow.Job().MarkQueued(ctx, created.ID); err != nil {
+		t.Fatalf("queue job: %v", err)
+	}
+	job, err := uow.Job().GetOne(ctx, domain.JobSearchParams{ID: &created.ID})
+	if err != nil {
+		t.Fatalf("get job: %v", err)
+	}
+
+	// El primer intento falla y publica el reintento en la cola del tenant
+	if err := jobs.ProcessJobMessage(ctx, domain.NewRabbitJobMessageFromJob(*job)); err != nil {
+		t.Fatalf("process job: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	w := worker.New(jobs, client, "worker-test", 10*time.Millisecond, logger)
+	done := make(chan error, 1)
+	go func() { done <- w.Start(ctx) }()
+	defer func() {
+		client.Close()
+		if err := <-done; err != nil {
+			t.Errorf("worker: %v", err)
+		}
+	}()
+
+	deadline := time.After(5 * time.Second)
+	for {
+		job, err := uow.Job().GetOne(ctx, domain.JobSearchParams{ID: &created.ID})
+		if err != nil {
+			t.Fatalf("get job: %v", err)
+		}
+		if job.Status == domain.JobStatusCompleted {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("job is %s after 5s, want the worker to consume the tenant retry", job.Status)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if calls := exec.calls.Load(); calls != 2 {
+		t.Fatalf("callback called %d times, want 2", calls)
+	}
+}
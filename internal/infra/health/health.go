@@ -0,0 +1,160 @@
+// Package health serves the liveness (/healthz) and readiness (/readyz)
+// probes of every process.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// checkTimeout limita cuánto puede tardar cada probe.
+const checkTimeout = 2 * time.Second
+
+// Check returns nil when the dependency is healthy.
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker agrupa los checks de un proceso. Liveness solo falla si el proceso
+// está trabado; readiness además falla si una dependencia no responde o si el
+// proceso está drenando para apagarse.
+type Checker struct {
+	mu        sync.RWMutex
+	liveness  []namedCheck
+	readiness []namedCheck
+	draining  atomic.Bool
+}
+
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// AddLiveness registra un check que, si falla, indica que hay que reiniciar el proceso.
+// Los checks de liveness también cuentan para readiness.
+func (c *Checker) AddLiveness(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.liveness = append(c.liveness, namedCheck{name: name, check: check})
+}
+
+// AddReadiness registra un check de dependencia (base de datos, broker).
+func (c *Checker) AddReadiness(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readiness = append(c.readiness, namedCheck{name: name, check: check})
+}
+
+// SetDraining hace que /readyz responda 503 para que el balanceador deje de enviar tráfico.
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+type response struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Liveness responde /healthz.
+func (c *Checker) Liveness() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c.mu.RLock()
+		checks := append([]namedCheck(nil), c.liveness...)
+		c.mu.RUnlock()
+
+		c.write(w, r, checks, false)
+	}
+}
+
+// Readiness responde /readyz.
+func (c *Checker) Readiness() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c.mu.RLock()
+		checks := append(append([]namedCheck(nil), c.liveness...), c.readiness...)
+		c.mu.RUnlock()
+
+		c.write(w, r, checks, c.draining.Load())
+	}
+}
+
+func (c *Checker) write(w http.ResponseWriter, r *http.Request, checks []namedCheck, draining bool) {
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+
+	resp := response{Status: "ok", Checks: map[string]string{}}
+	status := http.StatusOK
+
+	for _, nc := range checks {
+		if err := nc.check(ctx); err != nil {
+			resp.Checks[nc.name] = err.Error()
+			resp.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		resp.Checks[nc.name] = "ok"
+	}
+
+	if draining {
+		resp.Status = "draining"
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// Register agrega /healthz y /readyz al mux.
+func (c *Checker) Register(mux *http.ServeMux) {
+	mux.Handle("/healthz", c.Liveness())
+	mux.Handle("/readyz", c.Readiness())
+}
+
+// Serve levanta un listener con /healthz y /readyz para los procesos que no
+// tienen API (worker, dispatcher). Con addr vacío no hace nada. logger puede
+// ser nil para usar slog.Default().
+func Serve(addr string, c *Checker, logger *slog.Logger) *http.Server {
+	if addr == "" {
+		return nil
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	mux := http.NewServeMux()
+	c.Register(mux)
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		ctx := context.Background()
+		logger.InfoContext(ctx, "health probes listening", "addr", addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.ErrorContext(ctx, "health listener stopped", "addr", addr, "error", err)
+		}
+	}()
+
+	return server
+}
+
+// Recent devuelve un check que falla si last() es más viejo que maxAge.
+func Recent(last func() time.Time, maxAge time.Duration) Check {
+	return func(ctx context.Context) error {
+		if age := time.Since(last()); age > maxAge {
+			return errors.New("last success " + age.Round(time.Second).String() + " ago")
+		}
+		return nil
+	}
+}
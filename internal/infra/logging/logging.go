@@ -0,0 +1,88 @@
+// Package logging builds the slog loggers used by every binary. Output format
+// and levels come from the environment:
+//
+//	LOG_FORMAT=text|json          (default text)
+//	LOG_LEVEL=debug|info|warn|error (default info)
+//	LOG_LEVEL_<COMPONENT>=...      overrides the level of one component, e.g. LOG_LEVEL_UOW=debug
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// New devuelve el logger de un componente (worker, dispatcher, uow, ...).
+func New(component string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: levelFor(component)}
+
+	var handler slog.Handler
+	if strings.EqualFold(os.Getenv("LOG_FORMAT"), "json") {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	}
+
+	return slog.New(&contextHandler{Handler: handler}).With("component", component)
+}
+
+func levelFor(component string) slog.Level {
+	raw := os.Getenv("LOG_LEVEL_" + strings.ToUpper(strings.ReplaceAll(component, "-", "_")))
+	if raw == "" {
+		raw = os.Getenv("LOG_LEVEL")
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(raw)); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// Fatal loguea msg con nivel error y termina el proceso, como log.Fatal.
+func Fatal(ctx context.Context, logger *slog.Logger, msg string, args ...any) {
+	logger.ErrorContext(ctx, msg, args...)
+	os.Exit(1)
+}
+
+type ctxKey struct{}
+
+// WithAttrs agrega atributos que se incluyen en cada línea logueada con ctx
+// (ej. job_id, type, attempt mientras se procesa un job).
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	prev, _ := ctx.Value(ctxKey{}).([]slog.Attr)
+	merged := make([]slog.Attr, 0, len(prev)+len(attrs))
+	merged = append(merged, prev...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, ctxKey{}, merged)
+}
+
+// contextHandler agrega a cada registro los atributos guardados en el
+// contexto y el trace_id/span_id del span activo.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(ctxKey{}).([]slog.Attr); ok {
+		r.AddAttrs(attrs...)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,122 @@
+// Package metrics defines the Prometheus collectors shared by the server,
+// dispatcher and worker binaries.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "job_scheduler"
+
+var (
+	JobsCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_created_total",
+		Help:      "Jobs created through the API.",
+	}, []string{"type"})
+
+	JobsDispatched = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_dispatched_total",
+		Help:      "Jobs published to RabbitMQ by the dispatcher.",
+	}, []string{"type"})
+
+	JobsCompleted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_completed_total",
+		Help:      "Jobs whose callback succeeded.",
+	}, []string{"type"})
+
+	JobsFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_failed_total",
+		Help:      "Failed job attempts that will be retried.",
+	}, []string{"type"})
+
+	JobsDead = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_dead_total",
+		Help:      "Jobs that ran out of retries.",
+	}, []string{"type"})
+
+	ExecutionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_execution_duration_seconds",
+		Help:      "Duration of the job callback HTTP request.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12), // 10ms .. ~20s
+	}, []string{"type", "outcome"})
+
+	DispatcherTickDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "dispatcher_tick_duration_seconds",
+		Help:      "Duration of one dispatcher iteration.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	DispatcherBatchSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "dispatcher_batch_size",
+		Help:      "Ready jobs fetched per dispatcher iteration.",
+		Buckets:   []float64{0, 1, 5, 10, 20, 30, 40, 50},
+	})
+
+	PublishErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rabbitmq_publish_errors_total",
+		Help:      "Messages that could not be published to RabbitMQ.",
+	})
+
+	TxDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_transaction_duration_seconds",
+		Help:      "Duration of IUnitOfWork.Atomic transactions.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"outcome"})
+
+	TxRollbacks = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_transaction_rollbacks_total",
+		Help:      "IUnitOfWork.Atomic transactions rolled back.",
+	})
+)
+
+// Handler expone las métricas registradas en formato Prometheus.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Serve levanta un listener dedicado a /metrics. Con addr vacío no hace nada,
+// así worker y dispatcher lo exponen solo si se configura METRICS_ADDR.
+// logger puede ser nil para usar slog.Default().
+func Serve(addr string, logger *slog.Logger) {
+	if addr == "" {
+		return
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		ctx := context.Background()
+		logger.InfoContext(ctx, "metrics listening", "addr", addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.ErrorContext(ctx, "metrics listener stopped", "addr", addr, "error", err)
+		}
+	}()
+}
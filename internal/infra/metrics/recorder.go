@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"job_scheduler_go_rabbitmq/internal/core/domain"
+	"job_scheduler_go_rabbitmq/internal/core/ports"
+)
+
+// Recorder publishes the job lifecycle metrics reported by the core services.
+type Recorder struct{}
+
+var _ ports.IJobMetrics = (*Recorder)(nil)
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// JobCreated implements ports.IJobMetrics.
+func (r *Recorder) JobCreated(jobType string) {
+	JobsCreated.WithLabelValues(jobType).Inc()
+}
+
+// JobFinished implements ports.IJobMetrics.
+func (r *Recorder) JobFinished(jobType string, status domain.JobStatus) {
+	switch status {
+	case domain.JobStatusCompleted:
+		JobsCompleted.WithLabelValues(jobType).Inc()
+	case domain.JobStatusFailed:
+		JobsFailed.WithLabelValues(jobType).Inc()
+	case domain.JobStatusDead:
+		JobsDead.WithLabelValues(jobType).Inc()
+	}
+}
@@ -0,0 +1,378 @@
+// Package migrate applies the versioned SQL migrations embedded in the
+// migrations package and tracks them in the schema_migrations table.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"job_scheduler_go_rabbitmq/migrations"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockID es la clave del advisory lock que evita que dos procesos migren a la vez.
+const lockID = 7_260_046
+
+// ErrSchemaOutdated: la base no tiene aplicadas todas las migraciones que conoce el binario.
+var ErrSchemaOutdated = errors.New("database schema is out of date")
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is one schema version with the SQL to apply and revert it.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a known migration and whether it is applied.
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time // nil si está pendiente
+}
+
+// Load lee las migraciones NNNN_nombre.up.sql / NNNN_nombre.down.sql de fsys,
+// ordenadas por versión. Cada versión necesita sus dos archivos.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Migrator aplica y revierte migraciones sobre una base.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+	logger     *slog.Logger
+}
+
+// New creates a migrator for the embedded migrations. logger may be nil to use
+// slog.Default().
+func New(pool *pgxpool.Pool, logger *slog.Logger) (*Migrator, error) {
+	migrations, err := Load(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Migrator{pool: pool, migrations: migrations, logger: logger}, nil
+}
+
+// Latest devuelve la versión más nueva que conoce el binario (0 si no hay migraciones).
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status devuelve todas las migraciones conocidas con su fecha de aplicación.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx, m.pool)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := Status{Version: mig.Version, Name: mig.Name}
+		if at, ok := applied[mig.Version]; ok {
+			st.AppliedAt = &at
+		}
+		statuses = append(statuses, st)
+	}
+
+	return statuses, nil
+}
+
+// Check devuelve ErrSchemaOutdated si falta aplicar alguna migración. Una base
+// con versiones más nuevas que el binario se acepta: pasa durante un deploy
+// escalonado, con las migraciones ya aplicadas y procesos viejos aún vivos.
+func (m *Migrator) Check(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	var pending []string
+	for _, st := range statuses {
+		if st.AppliedAt == nil {
+			pending = append(pending, fmt.Sprintf("%04d_%s", st.Version, st.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: pending migrations %s (run `migrate up`)", ErrSchemaOutdated, strings.Join(pending, ", "))
+	}
+
+	return nil
+}
+
+// Up aplica todas las migraciones pendientes y devuelve cuántas aplicó.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	return m.To(ctx, m.Latest())
+}
+
+// Down revierte las últimas steps migraciones aplicadas.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	var count int
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if err := m.revert(ctx, conn, mig); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// To lleva la base a la versión indicada: aplica las pendientes hasta ella y
+// revierte las aplicadas posteriores. Devuelve cuántas migraciones ejecutó.
+func (m *Migrator) To(ctx context.Context, version int64) (int, error) {
+	if version != 0 && !m.known(version) {
+		return 0, fmt.Errorf("unknown migration version %d", version)
+	}
+
+	var count int
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		// Primero se revierte lo que sobra, de la más nueva a la más vieja
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; ok && mig.Version > version {
+				if err := m.revert(ctx, conn, mig); err != nil {
+					return err
+				}
+				count++
+			}
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; !ok && mig.Version <= version {
+				if err := m.apply(ctx, conn, mig); err != nil {
+					return err
+				}
+				count++
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Baseline marca como aplicadas, sin ejecutarlas, las migraciones hasta
+// version. Sirve para adoptar una base creada a mano con el esquema de esa versión.
+func (m *Migrator) Baseline(ctx context.Context, version int64) error {
+	if !m.known(version) {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.locked(ctx, func(conn *pgxpool.Conn) error {
+		for _, mig := range m.migrations {
+			if mig.Version > version {
+				break
+			}
+			if _, err := conn.Exec(ctx,
+				`INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3) ON CONFLICT (version) DO NOTHING`,
+				mig.Version, mig.Name, time.Now(),
+			); err != nil {
+				return fmt.Errorf("baseline migration %d: %w", mig.Version, err)
+			}
+		}
+		m.logger.InfoContext(ctx, "schema baselined", "version", version)
+		return nil
+	})
+}
+
+func (m *Migrator) known(version int64) bool {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// locked ejecuta fn con una conexión dedicada que tiene el advisory lock de
+// migraciones y la tabla schema_migrations creada.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		// Con un ctx cancelado el unlock fallaría; el lock se libera igual al cerrar la conexión
+		_, _ = conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID)
+	}()
+
+	if _, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL
+		)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// applied devuelve las versiones aplicadas. Sin tabla schema_migrations no hay ninguna.
+func (m *Migrator) applied(ctx context.Context, q querier) (map[int64]time.Time, error) {
+	var exists bool
+	if err := q.QueryRow(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("check schema_migrations: %w", err)
+	}
+
+	applied := map[int64]time.Time{}
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := q.Query(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		applied[version] = at
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return applied, nil
+}
+
+// apply ejecuta el up de la migración y la registra en la misma transacción.
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, mig Migration) error {
+	start := time.Now()
+	err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, mig.Up); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx,
+			`INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`,
+			mig.Version, mig.Name, time.Now(),
+		)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("apply migration %04d_%s: %w", mig.Version, mig.Name, err)
+	}
+
+	m.logger.InfoContext(ctx, "migration applied", "version", mig.Version, "name", mig.Name, "duration", time.Since(start))
+	return nil
+}
+
+// revert ejecuta el down de la migración y borra su registro en la misma transacción.
+func (m *Migrator) revert(ctx context.Context, conn *pgxpool.Conn, mig Migration) error {
+	start := time.Now()
+	err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, mig.Down); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("revert migration %04d_%s: %w", mig.Version, mig.Name, err)
+	}
+
+	m.logger.InfoContext(ctx, "migration reverted", "version", mig.Version, "name", mig.Name, "duration", time.Since(start))
+	return nil
+}
+
+// CheckSchema es el chequeo de arranque de los binarios: falla si la base no
+// tiene aplicadas todas las migraciones embebidas.
+func CheckSchema(ctx context.Context, pool *pgxpool.Pool) error {
+	m, err := New(pool, nil)
+	if err != nil {
+		return err
+	}
+	return m.Check(ctx)
+}
@@ -0,0 +1,131 @@
+package migrate
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"job_scheduler_go_rabbitmq/migrations"
+)
+
+func TestLoad(t *testing.T) {
+	file := func(body string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(body)} }
+
+	tests := []struct {
+		name     string
+		fsys     fstest.MapFS
+		versions []int64
+		wantErr  string
+	}{
+		{
+			name: "ordena por versión numérica, no por nombre",
+			fsys: fstest.MapFS{
+				"10_c.up.sql":     file("c up"),
+				"10_c.down.sql":   file("c down"),
+				"2_b.up.sql":      file("b up"),
+				"2_b.down.sql":    file("b down"),
+				"0001_a.up.sql":   file("a up"),
+				"0001_a.down.sql": file("a down"),
+			},
+			versions: []int64{1, 2, 10},
+		},
+		{
+			name: "ignora archivos que no son migraciones",
+			fsys: fstest.MapFS{
+				"0001_a.up.sql":   file("a up"),
+				"0001_a.down.sql": file("a down"),
+				"migrations.go":   file("package migrations"),
+				"README.md":       file("docs"),
+				"0002_B.up.sql":   file("mayúsculas"),
+			},
+			versions: []int64{1},
+		},
+		{
+			name:     "sin migraciones",
+			fsys:     fstest.MapFS{},
+			versions: []int64{},
+		},
+		{
+			name: "falta el down",
+			fsys: fstest.MapFS{
+				"0001_a.up.sql": file("a up"),
+			},
+			wantErr: "needs both an up and a down file",
+		},
+		{
+			name: "falta el up",
+			fsys: fstest.MapFS{
+				"0001_a.down.sql": file("a down"),
+			},
+			wantErr: "needs both an up and a down file",
+		},
+		{
+			name: "dos nombres para la misma versión",
+			fsys: fstest.MapFS{
+				"0001_a.up.sql":   file("a up"),
+				"0001_b.down.sql": file("b down"),
+			},
+			wantErr: "has two names",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.fsys)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			versions := []int64{}
+			for _, m := range got {
+				versions = append(versions, m.Version)
+			}
+			if !slices.Equal(versions, tt.versions) {
+				t.Fatalf("versions = %v, want %v", versions, tt.versions)
+			}
+		})
+	}
+}
+
+func TestLoadKeepsBodies(t *testing.T) {
+	got, err := Load(fstest.MapFS{
+		"0003_add_x.up.sql":   {Data: []byte("ALTER TABLE t ADD COLUMN x INT;")},
+		"0003_add_x.down.sql": {Data: []byte("ALTER TABLE t DROP COLUMN x;")},
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := Migration{Version: 3, Name: "add_x", Up: "ALTER TABLE t ADD COLUMN x INT;", Down: "ALTER TABLE t DROP COLUMN x;"}
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+// Las migraciones embebidas tienen que cargar y estar numeradas sin huecos
+// desde 1: baseline y To usan esos números.
+func TestEmbeddedMigrations(t *testing.T) {
+	got, err := Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("Load(migrations.FS) error = %v", err)
+	}
+	if len(got) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+
+	for i, m := range got {
+		if m.Version != int64(i+1) {
+			t.Fatalf("migration %d_%s: want version %d", m.Version, m.Name, i+1)
+		}
+	}
+	if got[0].Name != "initial_schema" {
+		t.Fatalf("first migration is %s, want initial_schema", got[0].Name)
+	}
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS job_events;
+DROP TABLE IF EXISTS job_attempts;
+DROP TABLE IF EXISTS jobs;
@@ -1,3 +1,7 @@
+-- Esquema inicial: exactamente el que se aplicaba a mano desde migrations/ddl.sql.
+-- Una base creada con ese archivo se marca como migrada con `migrate baseline 1`
+-- y después se actualiza con `migrate up`.
+
 CREATE TABLE jobs (
     id UUID PRIMARY KEY,
     type TEXT NOT NULL,                 -- send_email, generate_invoice, etc
@@ -41,7 +45,3 @@ CREATE TABLE job_events (
 );
 
 CREATE INDEX idx_job_events_job_id ON job_events(job_id);
-
-
-
-
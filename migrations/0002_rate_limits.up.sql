@@ -0,0 +1,12 @@
+-- Rate limiting de callbacks por tipo, host y tenant (token bucket)
+CREATE TABLE rate_limits (
+    scope TEXT NOT NULL,                -- type | host | tenant
+    key TEXT NOT NULL,                  -- tipo de job, host del callback o tenant
+    rate_per_second DOUBLE PRECISION NOT NULL,
+    burst INT NOT NULL,
+    tokens DOUBLE PRECISION NOT NULL,   -- tokens disponibles al momento de refilled_at
+    refilled_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (scope, key)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS concurrency_slots;
+DROP TABLE IF EXISTS concurrency_limits;
@@ -0,0 +1,15 @@
+CREATE TABLE concurrency_limits (
+    job_type TEXT PRIMARY KEY,
+    max_concurrent INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- semáforo distribuido: un slot por job en ejecución de un tipo con límite
+CREATE TABLE concurrency_slots (
+    job_id UUID PRIMARY KEY REFERENCES jobs(id),
+    job_type TEXT NOT NULL,
+    acquired_at TIMESTAMPTZ NOT NULL    -- los slots más viejos que el lease se consideran huérfanos
+);
+
+CREATE INDEX idx_concurrency_slots_job_type ON concurrency_slots(job_type);
@@ -0,0 +1,2 @@
+ALTER TABLE jobs DROP COLUMN IF EXISTS attempts;
+DROP TABLE IF EXISTS circuit_breakers;
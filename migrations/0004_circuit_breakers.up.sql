@@ -0,0 +1,15 @@
+-- estado de los circuit breakers que publica cada worker
+CREATE TABLE circuit_breakers (
+    node_id TEXT NOT NULL,
+    host TEXT NOT NULL,
+    state TEXT NOT NULL,                -- closed | open | half_open
+    failures INT NOT NULL,
+    opened_at TIMESTAMPTZ,
+    retry_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (node_id, host)
+);
+
+-- Intentos ejecutados por job: el número del próximo intento sale de acá y no
+-- del mensaje, así un job diferido por circuito abierto no vuelve al intento 1
+ALTER TABLE jobs ADD COLUMN attempts INT NOT NULL DEFAULT 0;
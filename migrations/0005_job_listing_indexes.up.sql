@@ -0,0 +1,3 @@
+-- GET /jobs: orden por created_at con desempate por id, filtro por tipo
+CREATE INDEX idx_jobs_created_at ON jobs(created_at, id);
+CREATE INDEX idx_jobs_type ON jobs(type);
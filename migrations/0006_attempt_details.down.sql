@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_job_attempts_http_status;
+DROP INDEX IF EXISTS idx_job_attempts_created_at;
+
+ALTER TABLE job_attempts
+    DROP COLUMN IF EXISTS worker_id,
+    DROP COLUMN IF EXISTS duration_ms,
+    DROP COLUMN IF EXISTS finished_at;
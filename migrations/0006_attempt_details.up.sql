@@ -0,0 +1,7 @@
+ALTER TABLE job_attempts
+    ADD COLUMN finished_at TIMESTAMPTZ,
+    ADD COLUMN duration_ms BIGINT,
+    ADD COLUMN worker_id TEXT;          -- INSTANCE_ID del worker que ejecutó el intento
+
+CREATE INDEX idx_job_attempts_created_at ON job_attempts(created_at, id);
+CREATE INDEX idx_job_attempts_http_status ON job_attempts(http_status);
@@ -0,0 +1 @@
+ALTER TABLE job_events DROP COLUMN IF EXISTS seq;
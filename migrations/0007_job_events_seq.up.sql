@@ -0,0 +1,2 @@
+-- orden global de los eventos, usado como id en el stream SSE
+ALTER TABLE job_events ADD COLUMN seq BIGSERIAL NOT NULL UNIQUE;
@@ -0,0 +1,39 @@
+CREATE TABLE webhook_subscriptions (
+    id UUID PRIMARY KEY,
+    job_id UUID REFERENCES jobs(id),        -- suscripción de un solo job (notify_url)
+    job_type TEXT,                          -- suscripción global filtrada por tipo (NULL = todos)
+    event_types TEXT[] NOT NULL DEFAULT '{}', -- vacío = todos los eventos
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,                   -- clave HMAC de la firma
+    max_attempts INT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_webhook_subscriptions_job_id ON webhook_subscriptions(job_id) WHERE active;
+CREATE INDEX idx_webhook_subscriptions_global ON webhook_subscriptions(job_type) WHERE active AND job_id IS NULL;
+
+
+CREATE TABLE webhook_deliveries (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id),
+    event_id UUID NOT NULL,
+    job_id UUID NOT NULL REFERENCES jobs(id),
+    event_type TEXT NOT NULL,
+    url TEXT NOT NULL,
+    status TEXT NOT NULL,                   -- pending | delivered | dead
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL,
+    next_attempt_at TIMESTAMPTZ,
+    last_http_status INT,
+    last_error TEXT,
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_job_id ON webhook_deliveries(job_id);
+CREATE INDEX idx_webhook_deliveries_created_at ON webhook_deliveries(created_at, id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_job_attempts_created_at_status;
+DROP INDEX IF EXISTS idx_jobs_status_scheduled_at;
@@ -0,0 +1,3 @@
+-- consultas de /stats
+CREATE INDEX idx_jobs_status_scheduled_at ON jobs(status, scheduled_at);
+CREATE INDEX idx_job_attempts_created_at_status ON job_attempts(created_at, status);
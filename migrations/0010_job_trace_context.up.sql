@@ -0,0 +1 @@
+ALTER TABLE jobs ADD COLUMN trace_context JSONB;  -- traceparent del request que creó el job
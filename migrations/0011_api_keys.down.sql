@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_jobs_created_by_key;
+ALTER TABLE jobs DROP COLUMN IF EXISTS created_by_key_id;
+DROP TABLE IF EXISTS api_keys;
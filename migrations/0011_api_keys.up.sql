@@ -0,0 +1,17 @@
+-- API keys: solo se guarda el SHA-256 del token
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,                          -- primeros caracteres del token, para reconocerlo
+    key_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,                        -- create | read | admin
+    allowed_job_types TEXT[] NOT NULL DEFAULT '{}', -- vacío = cualquier tipo
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+ALTER TABLE jobs ADD COLUMN created_by_key_id UUID;  -- API key que encoló el job (api_keys.id)
+
+CREATE INDEX idx_jobs_created_by_key ON jobs(created_by_key_id) WHERE created_by_key_id IS NOT NULL;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_job_events_tenant;
+DROP INDEX IF EXISTS idx_job_attempts_tenant;
+DROP INDEX IF EXISTS idx_jobs_tenant_status;
+
+DROP TABLE IF EXISTS tenant_quotas;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant;
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS tenant;
+ALTER TABLE webhook_subscriptions DROP COLUMN IF EXISTS tenant;
+ALTER TABLE job_events DROP COLUMN IF EXISTS tenant;
+ALTER TABLE job_attempts DROP COLUMN IF EXISTS tenant;
+ALTER TABLE jobs DROP COLUMN IF EXISTS tenant;
@@ -0,0 +1,18 @@
+-- Multi-tenancy: las filas existentes quedan en el tenant default
+ALTER TABLE jobs ADD COLUMN tenant TEXT NOT NULL DEFAULT 'default';          -- equipo dueño del job; cada consulta del API se filtra por él
+ALTER TABLE job_attempts ADD COLUMN tenant TEXT NOT NULL DEFAULT 'default';  -- copia de jobs.tenant
+ALTER TABLE job_events ADD COLUMN tenant TEXT NOT NULL DEFAULT 'default';    -- copia de jobs.tenant
+ALTER TABLE webhook_subscriptions ADD COLUMN tenant TEXT NOT NULL DEFAULT 'default'; -- solo recibe eventos de jobs de su tenant
+ALTER TABLE webhook_deliveries ADD COLUMN tenant TEXT NOT NULL DEFAULT 'default';    -- copia de webhook_subscriptions.tenant
+ALTER TABLE api_keys ADD COLUMN tenant TEXT NOT NULL DEFAULT '';             -- vacío = key de operador, ve todos los tenants
+
+CREATE TABLE tenant_quotas (
+    tenant TEXT PRIMARY KEY,
+    max_pending_jobs INT NOT NULL,   -- tope de jobs pending + queued
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_jobs_tenant_status ON jobs(tenant, status);
+CREATE INDEX idx_job_attempts_tenant ON job_attempts(tenant, created_at);
+CREATE INDEX idx_job_events_tenant ON job_events(tenant, seq);
@@ -0,0 +1,16 @@
+-- Altas masivas (POST /jobs/batch)
+CREATE TABLE job_batches (
+    id UUID PRIMARY KEY,
+    tenant TEXT NOT NULL,
+    total INT NOT NULL,        -- items recibidos
+    created INT NOT NULL,      -- jobs insertados
+    rejected INT NOT NULL,     -- items rechazados
+    all_or_nothing BOOLEAN NOT NULL,
+    created_by_key_id UUID,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+ALTER TABLE jobs ADD COLUMN batch_id UUID;  -- lote de POST /jobs/batch (job_batches.id)
+
+CREATE INDEX idx_jobs_batch ON jobs(batch_id) WHERE batch_id IS NOT NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE job_batches
+    DROP COLUMN IF EXISTS completed_at,
+    DROP COLUMN IF EXISTS sealed_at,
+    DROP COLUMN IF EXISTS callback_job_id,
+    DROP COLUMN IF EXISTS callback_url,
+    DROP COLUMN IF EXISTS failed,
+    DROP COLUMN IF EXISTS succeeded;
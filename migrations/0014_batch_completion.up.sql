@@ -0,0 +1,11 @@
+-- Progreso de los lotes y callback al terminar
+ALTER TABLE job_batches
+    ADD COLUMN succeeded INT NOT NULL DEFAULT 0, -- jobs completed
+    ADD COLUMN failed INT NOT NULL DEFAULT 0,    -- jobs dead
+    ADD COLUMN callback_url TEXT,                -- recibe el resumen cuando todos los jobs terminan
+    ADD COLUMN callback_job_id UUID,             -- job batch.completed que entrega ese callback
+    ADD COLUMN sealed_at TIMESTAMPTZ,            -- ya no llegan más items
+    ADD COLUMN completed_at TIMESTAMPTZ;
+
+-- Los lotes anteriores ya recibieron todos sus items
+UPDATE job_batches SET sealed_at = created_at;
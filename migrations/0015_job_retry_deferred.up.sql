@@ -0,0 +1,3 @@
+-- Reintento diferido por rate/concurrency limit: el job sigue failed sin mensaje
+-- en la cola, y el dispatcher lo vuelve a publicar desde scheduled_at
+ALTER TABLE jobs ADD COLUMN retry_deferred BOOLEAN NOT NULL DEFAULT false;
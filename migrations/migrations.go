@@ -0,0 +1,11 @@
+// Package migrations embebe las migraciones SQL versionadas del esquema.
+//
+// Cada versión tiene un NNNN_nombre.up.sql y su NNNN_nombre.down.sql. Se
+// aplican con cmd/migrate; nunca se edita una migración ya publicada, se
+// agrega una nueva.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
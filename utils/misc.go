@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -12,14 +14,78 @@ type QueryBuilder struct {
 }
 
 type PaginatorWrapper[T any] struct {
-	TotalPages uint `json:"totalPages"`
-	Data       T    `json:"data"`
+	TotalPages uint    `json:"totalPages"`
+	Total      uint    `json:"total"`
+	Page       uint    `json:"page,omitempty"`
+	Limit      uint    `json:"limit"`
+	NextCursor *string `json:"nextCursor,omitempty"`
+	Data       T       `json:"data"`
 }
 
 type SearchParams struct {
 	Q     *string
 	Page  *uint
 	Limit *uint
+
+	// Sort es un campo, con prefijo "-" para orden descendente (ej. "-created_at")
+	Sort *string
+	// Cursor de paginación por keyset; si está presente se ignora Page
+	Cursor *string
+}
+
+// Cursor identifica la última fila devuelta en una paginación por keyset.
+// Sort es el orden con el que se generó: el valor solo se puede comparar
+// contra la misma columna.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// ParseSort separa el campo de la dirección de un parámetro de orden.
+func ParseSort(raw string) (field string, desc bool) {
+	if strings.HasPrefix(raw, "-") {
+		return raw[1:], true
+	}
+	return strings.TrimPrefix(raw, "+"), false
+}
+
+// FormatSort es la inversa de ParseSort.
+func FormatSort(field string, desc bool) string {
+	if desc {
+		return "-" + field
+	}
+	return field
+}
+
+func EncodeCursor(c Cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor decodifica raw y verifica que se haya generado con el orden
+// sort (ver FormatSort).
+func DecodeCursor(raw, sort string) (Cursor, error) {
+	var c Cursor
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return c, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if c.Sort != sort {
+		return c, fmt.Errorf("invalid cursor: generated for sort %q, not %q", c.Sort, sort)
+	}
+	return c, nil
+}
+
+// TotalPages calcula la cantidad de páginas para total elementos.
+func TotalPages(total, limit uint) uint {
+	if limit == 0 {
+		return 0
+	}
+	return (total + limit - 1) / limit
 }
 
 func MustEnv(key string) string {